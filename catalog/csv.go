@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Kesalahan berkas CSV (bukan kesalahan per baris).
+var (
+	ErrEmptyFile    = errors.New("berkas kosong")
+	ErrMissingTitle = errors.New("kolom judul (title) tidak ditemukan pada baris pertama")
+	ErrTooManyRows  = fmt.Errorf("berkas melebihi %d baris data", MaxRows)
+)
+
+// csvColumns memetakan nama kolom yang diterima (tidak peka huruf besar/kecil) ke field Record.
+// Nama kolom mengikuti ekspor katalog sehingga hasil ekspor bisa diimpor kembali.
+var csvColumns = map[string]string{
+	"title": "title", "judul": "title",
+	"author": "author", "penulis": "author", "pengarang": "author",
+	"category": "category", "kategori": "category",
+	"publisher": "publisher", "penerbit": "publisher",
+	"stock": "stock", "stok": "stock", "eksemplar": "stock", "jumlah": "stock",
+	"published_year": "published_year", "year": "published_year", "tahun": "published_year",
+	"tahun terbit": "published_year", "tahun_terbit": "published_year",
+	"isbn":  "isbn",
+	"cover": "cover", "cover_url": "cover", "image": "cover", "image_url": "cover", "sampul": "cover",
+}
+
+// CSVColumns adalah urutan kolom yang dianjurkan untuk berkas impor.
+var CSVColumns = []string{"title", "author", "category", "publisher", "stock", "published_year", "isbn", "cover"}
+
+// ParseCSV membaca berkas CSV impor katalog. Baris pertama harus berisi nama kolom; kolom yang
+// tidak dikenal diabaikan. Pemisah ";" dikenali otomatis (ekspor Excel berlokal Indonesia).
+// Baris yang seluruhnya kosong dilewati.
+func ParseCSV(r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	// Buang BOM UTF-8 yang biasa ditambahkan Excel
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+	first, _ := br.Peek(4096)
+	if len(bytes.TrimSpace(first)) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = detectDelimiter(first)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		field, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]
+		if _, dup := index[field]; ok && !dup {
+			index[field] = i
+		}
+	}
+	if _, ok := index["title"]; !ok {
+		return nil, ErrMissingTitle
+	}
+
+	var records []Record
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// *csv.ParseError sudah memuat nomor baris
+			return nil, err
+		}
+		if blank(fields) {
+			continue
+		}
+		if len(records) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		line, _ := cr.FieldPos(0)
+		get := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return fields[i]
+		}
+		records = append(records, Record{
+			Line:          line,
+			Title:         get("title"),
+			Author:        get("author"),
+			Category:      get("category"),
+			Publisher:     get("publisher"),
+			Stock:         get("stock"),
+			PublishedYear: get("published_year"),
+			ISBN:          get("isbn"),
+			Cover:         get("cover"),
+		})
+	}
+	return records, nil
+}
+
+// detectDelimiter memilih ";" jika baris pertama lebih banyak memuat titik koma daripada koma.
+func detectDelimiter(sample []byte) rune {
+	if i := bytes.IndexByte(sample, '\n'); i >= 0 {
+		sample = sample[:i]
+	}
+	if bytes.Count(sample, []byte(";")) > bytes.Count(sample, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func blank(fields []string) bool {
+	for _, f := range fields {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package catalog_test
+
+import (
+	"errors"
+	"latihan_cloud8/catalog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []catalog.Record
+	}{
+		{
+			name: "english columns",
+			in: "title,author,category,publisher,stock,published_year,isbn,cover\n" +
+				"Laskar Pelangi,Andrea Hirata,Fiksi,Bentang,3,2005,9789793062792,laskar.jpg\n",
+			want: []catalog.Record{{Line: 2, Title: "Laskar Pelangi", Author: "Andrea Hirata", Category: "Fiksi",
+				Publisher: "Bentang", Stock: "3", PublishedYear: "2005", ISBN: "9789793062792", Cover: "laskar.jpg"}},
+		},
+		{
+			name: "indonesian columns, semicolon and BOM",
+			in: "\xEF\xBB\xBFJudul;Pengarang;Kategori;Penerbit;Eksemplar;Tahun Terbit;ISBN;Sampul\n" +
+				"Bumi Manusia;Pramoedya;Sastra;Lentera;2;1980;;\n",
+			want: []catalog.Record{{Line: 2, Title: "Bumi Manusia", Author: "Pramoedya", Category: "Sastra",
+				Publisher: "Lentera", Stock: "2", PublishedYear: "1980"}},
+		},
+		{
+			name: "header case, unknown and repeated columns",
+			in:   " TITLE ,Catatan,Stok,jumlah,Penulis\nPemrograman Go,rak 3,4,9,Smith\n",
+			want: []catalog.Record{{Line: 2, Title: "Pemrograman Go", Author: "Smith", Stock: "4"}},
+		},
+		{
+			name: "blank lines skipped and short rows",
+			in:   "title,author,stock\nSatu,A,1\n\n , ,\nDua\n",
+			want: []catalog.Record{{Line: 2, Title: "Satu", Author: "A", Stock: "1"}, {Line: 5, Title: "Dua"}},
+		},
+		{
+			name: "quoted fields",
+			in:   "title,author\n\"Laut Bercerita, Edisi Baru\",\"Chudori, Leila S.\"\n",
+			want: []catalog.Record{{Line: 2, Title: "Laut Bercerita, Edisi Baru", Author: "Chudori, Leila S."}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := catalog.ParseCSV(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tooMany := "title\n" + strings.Repeat("Buku\n", catalog.MaxRows+1)
+	tests := []struct {
+		name string
+		in   string
+		want error
+	}{
+		{"empty", "", catalog.ErrEmptyFile},
+		{"whitespace only", " \n\n", catalog.ErrEmptyFile},
+		{"no title column", "author,isbn\nAndrea Hirata,9789793062792\n", catalog.ErrMissingTitle},
+		{"too many rows", tooMany, catalog.ErrTooManyRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := catalog.ParseCSV(strings.NewReader(tt.in)); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package catalog berisi impor data katalog buku: pembacaan berkas, validasi per baris,
+// deteksi duplikat terhadap katalog yang ada dan penentuan kategori baru.
+package catalog
+
+import "strings"
+
+// NormalizeISBN membuang spasi dan tanda hubung serta membesarkan check digit "x".
+func NormalizeISBN(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteByte('X')
+		case r == '-' || r == ' ':
+		default:
+			// Karakter lain dibiarkan agar ValidISBN menolaknya
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ValidISBN memeriksa ISBN-10 atau ISBN-13 (sudah dinormalisasi) beserta check digit-nya.
+func ValidISBN(isbn string) bool {
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i := 0; i < 10; i++ {
+			c := isbn[i]
+			var d int
+			switch {
+			case c >= '0' && c <= '9':
+				d = int(c - '0')
+			case c == 'X' && i == 9:
+				d = 10
+			default:
+				return false
+			}
+			sum += d * (10 - i)
+		}
+		return sum%11 == 0
+	case 13:
+		sum := 0
+		for i := 0; i < 13; i++ {
+			c := isbn[i]
+			if c < '0' || c > '9' {
+				return false
+			}
+			d := int(c - '0')
+			if i%2 == 1 {
+				d *= 3
+			}
+			sum += d
+		}
+		return sum%10 == 0
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package catalog
+
+import (
+	"latihan_cloud8/marc"
+	"latihan_cloud8/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MARCRecord memetakan buku ke rekaman bibliografis MARC21:
+//
+//	001 ID buku            020 $a ISBN          100 $a penulis
+//	245 $a judul           264 $b penerbit, $c tahun terbit
+//	650 $a kategori (subjek)
+//
+// Nilai ditulis tanpa tanda baca ISBD (leader/18 = "c").
+func MARCRecord(b *models.Book) *marc.Record {
+	rec := marc.NewRecord()
+	rec.AddControl("001", strconv.Itoa(b.ID))
+	rec.AddControl("005", b.CreatedAt.Format("20060102150405")+".0")
+	rec.AddControl("008", fixedData(b))
+
+	rec.AddData("020", ' ', ' ', marc.Subfield{Code: 'a', Value: b.ISBN})
+	rec.AddData("100", '1', ' ', marc.Subfield{Code: 'a', Value: b.Author})
+	// Indikator 1 menandai ada entri utama (100) sehingga judul menjadi entri tambahan
+	ind1 := byte('0')
+	if strings.TrimSpace(b.Author) != "" {
+		ind1 = '1'
+	}
+	rec.AddData("245", ind1, '0', marc.Subfield{Code: 'a', Value: b.Title})
+	year := ""
+	if b.PublishedYear > 0 {
+		year = strconv.Itoa(b.PublishedYear)
+	}
+	rec.AddData("264", ' ', '1', marc.Subfield{Code: 'b', Value: b.Publisher}, marc.Subfield{Code: 'c', Value: year})
+	rec.AddData("650", ' ', '4', marc.Subfield{Code: 'a', Value: b.Category})
+	return rec
+}
+
+// fixedData menyusun field 008 (40 karakter) untuk buku: tanggal entri, tahun terbit,
+// negara terbit Indonesia ("io") dan bahasa Indonesia ("ind").
+func fixedData(b *models.Book) string {
+	created := b.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	dateType, date1 := "n", "uuuu"
+	if b.PublishedYear >= 1000 && b.PublishedYear <= 9999 {
+		dateType, date1 = "s", strconv.Itoa(b.PublishedYear)
+	}
+	return created.Format("060102") + dateType + date1 + "    " + "io " +
+		strings.Repeat("|", 17) + "ind" + " " + "d"
+}
+
+// RecordFromMARC mengubah rekaman MARC21 menjadi rekaman impor katalog bernomor n.
+// Penerbit dan tahun diambil dari 264 (atau 260 untuk rekaman lama), tahun dari 008 jika
+// $c kosong, dan kategori dari subjek 650 pertama. Stok tidak ada di rekaman bibliografis
+// sehingga mengikuti Options.DefaultStock.
+func RecordFromMARC(rec *marc.Record, n int) Record {
+	r := Record{Line: n}
+	if f := rec.Field("245"); f != nil {
+		r.Title = trimPunct(f.Subfield('a'))
+		if sub := trimPunct(f.Subfield('b')); sub != "" {
+			r.Title += ": " + sub
+		}
+	}
+	for _, tag := range []string{"100", "110", "111", "700", "710"} {
+		if f := rec.Field(tag); f != nil && f.Subfield('a') != "" {
+			r.Author = trimPunct(f.Subfield('a'))
+			break
+		}
+	}
+	if f := publication(rec); f != nil {
+		r.Publisher = trimPunct(f.Subfield('b'))
+		r.PublishedYear = firstYear(f.Subfield('c'))
+	}
+	if r.PublishedYear == "" {
+		if f := rec.Field("008"); f != nil && len(f.Value) >= 11 {
+			r.PublishedYear = firstYear(f.Value[7:11])
+		}
+	}
+	r.ISBN = marcISBN(rec)
+	if f := rec.Field("650"); f != nil {
+		r.Category = trimPunct(f.Subfield('a'))
+	}
+	return r
+}
+
+// publication memilih field penerbitan: 264 berindikator 2 = 1 (penerbitan) lebih dulu,
+// lalu 264 lain, lalu 260.
+func publication(rec *marc.Record) *marc.Field {
+	fields := rec.FieldsOf("264")
+	for _, f := range fields {
+		if f.Ind2 == '1' {
+			return f
+		}
+	}
+	if len(fields) > 0 {
+		return fields[0]
+	}
+	return rec.Field("260")
+}
+
+// marcISBN mengambil ISBN valid pertama dari 020 $a. Keterangan seperti "(pbk.)" dibuang.
+// Jika tidak ada yang valid, ISBN pertama dikembalikan apa adanya agar ditandai oleh validasi.
+func marcISBN(rec *marc.Record) string {
+	first := ""
+	for _, f := range rec.FieldsOf("020") {
+		s := strings.TrimSpace(f.Subfield('a'))
+		if i := strings.IndexAny(s, " ("); i >= 0 {
+			s = s[:i]
+		}
+		if s == "" {
+			continue
+		}
+		if ValidISBN(NormalizeISBN(s)) {
+			return s
+		}
+		if first == "" {
+			first = s
+		}
+	}
+	return first
+}
+
+// firstYear mengambil empat digit berurutan pertama, mis. "c2019." atau "[2019?]" menjadi "2019".
+func firstYear(s string) string {
+	run := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			run++
+			if run == 4 && (i+1 == len(s) || s[i+1] < '0' || s[i+1] > '9') {
+				return s[i-3 : i+1]
+			}
+		} else {
+			run = 0
+		}
+	}
+	return ""
+}
+
+// trimPunct membuang tanda baca ISBD di akhir nilai ("Judul /", "Jakarta :", "Hirata, Andrea.").
+// Titik setelah inisial ("Smith, J.") dipertahankan.
+func trimPunct(s string) string {
+	s = strings.TrimRightFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("/:;,=", r)
+	})
+	if strings.HasSuffix(s, ".") {
+		word := s[strings.LastIndexAny(s, " ,")+1 : len(s)-1]
+		if len([]rune(word)) > 1 {
+			s = strings.TrimRightFunc(s[:len(s)-1], unicode.IsSpace)
+		}
+	}
+	return strings.TrimSpace(s)
+}
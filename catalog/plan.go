@@ -0,0 +1,277 @@
+package catalog
+
+import (
+	"fmt"
+	"latihan_cloud8/models"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Status baris impor.
+const (
+	RowValid     = "valid"
+	RowInvalid   = "invalid"
+	RowDuplicate = "duplicate"
+)
+
+// Batas nilai impor.
+const (
+	MaxRows  = 10000 // Baris data per berkas
+	MaxStock = 1000  // Eksemplar awal per judul
+)
+
+// Record adalah satu baris/rekaman berkas impor sebelum divalidasi; semua nilai masih berupa
+// teks mentah.
+type Record struct {
+	Line          int
+	Title         string
+	Author        string
+	Category      string
+	Publisher     string
+	Stock         string
+	PublishedYear string
+	ISBN          string
+	Cover         string // URL http(s) atau nama berkas di upload/books
+}
+
+// Options mengatur validasi impor.
+type Options struct {
+	// CoverExists memeriksa apakah berkas sampul ada di folder upload/books.
+	// Jika nil, nama berkas sampul tidak diperiksa.
+	CoverExists func(filename string) bool
+	// DefaultStock dipakai jika kolom stok kosong.
+	DefaultStock int
+}
+
+// Plan memvalidasi setiap rekaman, mendeteksi duplikat terhadap katalog yang ada dan terhadap
+// baris sebelumnya di berkas yang sama, lalu menentukan kategori yang perlu dibuat.
+// Rekaman tidak disimpan; lihat Books untuk buku yang siap diimpor.
+func Plan(records []Record, existing []models.Book, categories []models.Category, opts Options) *models.ImportResult {
+	result := &models.ImportResult{Total: len(records), NewCategories: []string{}, Rows: make([]models.ImportRow, 0, len(records))}
+
+	byISBN := make(map[string]int)
+	byTitle := make(map[string][]models.Book)
+	for _, b := range existing {
+		if b.ISBN != "" {
+			byISBN[b.ISBN] = b.ID
+		}
+		key := titleKey(b.Title, b.Author)
+		byTitle[key] = append(byTitle[key], b)
+	}
+	knownCategory := make(map[string]string, len(categories))
+	for _, c := range categories {
+		knownCategory[strings.ToLower(c.Name)] = c.Name
+	}
+
+	// Baris valid sebelumnya pada berkas, untuk duplikat di dalam berkas
+	seenISBN := make(map[string]int)
+	seenTitle := make(map[string][]models.ImportRow)
+
+	for _, rec := range records {
+		row := validate(rec, opts)
+		if row.Status == RowValid {
+			// Kategori memakai penulisan yang sudah ada (tidak peka huruf besar/kecil)
+			if c := row.Book.Category; c != "" {
+				if name, ok := knownCategory[strings.ToLower(c)]; ok {
+					row.Book.Category = name
+				} else {
+					knownCategory[strings.ToLower(c)] = c
+					result.NewCategories = append(result.NewCategories, c)
+				}
+			}
+			markDuplicate(&row, byISBN, byTitle, seenISBN, seenTitle)
+		}
+
+		switch row.Status {
+		case RowValid:
+			result.Valid++
+			result.Copies += row.Book.Stock
+			if row.Book.ISBN != "" {
+				seenISBN[row.Book.ISBN] = row.Line
+			}
+			key := titleKey(row.Book.Title, row.Book.Author)
+			seenTitle[key] = append(seenTitle[key], row)
+		case RowInvalid:
+			result.Invalid++
+		case RowDuplicate:
+			result.Duplicates++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	// Kategori baru hanya dihitung dari baris yang benar-benar akan dibuat
+	used := make(map[string]bool)
+	for _, row := range result.Rows {
+		if row.Status == RowValid {
+			used[strings.ToLower(row.Book.Category)] = true
+		}
+	}
+	categoriesUsed := result.NewCategories[:0]
+	for _, c := range result.NewCategories {
+		if used[strings.ToLower(c)] {
+			categoriesUsed = append(categoriesUsed, c)
+		}
+	}
+	result.NewCategories = categoriesUsed
+	return result
+}
+
+// Books mengembalikan buku dari baris valid hasil Plan, sesuai urutan berkas.
+func Books(result *models.ImportResult) []models.Book {
+	books := make([]models.Book, 0, result.Valid)
+	for _, row := range result.Rows {
+		if row.Status == RowValid {
+			books = append(books, row.Book)
+		}
+	}
+	return books
+}
+
+// validate mengubah rekaman mentah menjadi buku dan mengumpulkan semua kesalahannya.
+func validate(rec Record, opts Options) models.ImportRow {
+	row := models.ImportRow{Line: rec.Line, Status: RowValid}
+	fail := func(format string, args ...any) {
+		row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+	}
+	b := &row.Book
+
+	b.Title = clean(rec.Title)
+	b.Author = clean(rec.Author)
+	b.Category = clean(rec.Category)
+	b.Publisher = clean(rec.Publisher)
+	if b.Title == "" {
+		fail("Judul wajib diisi")
+	}
+	checkLength := func(label, v string, max int) {
+		if utf8.RuneCountInString(v) > max {
+			fail("%s maksimal %d karakter", label, max)
+		}
+	}
+	checkLength("Judul", b.Title, 255)
+	checkLength("Penulis", b.Author, 255)
+	checkLength("Kategori", b.Category, 100)
+	checkLength("Penerbit", b.Publisher, 255)
+
+	b.Stock = opts.DefaultStock
+	if s := clean(rec.Stock); s != "" {
+		n, err := strconv.Atoi(s)
+		switch {
+		case err != nil:
+			fail("Stok harus berupa angka: %q", s)
+		case n < 0 || n > MaxStock:
+			fail("Stok harus antara 0 dan %d", MaxStock)
+		default:
+			b.Stock = n
+		}
+	}
+
+	if s := clean(rec.PublishedYear); s != "" {
+		maxYear := time.Now().Year() + 1
+		n, err := strconv.Atoi(s)
+		switch {
+		case err != nil:
+			fail("Tahun terbit harus berupa angka: %q", s)
+		case n < 1000 || n > maxYear:
+			fail("Tahun terbit harus antara 1000 dan %d", maxYear)
+		default:
+			b.PublishedYear = n
+		}
+	}
+
+	if s := clean(rec.ISBN); s != "" {
+		isbn := NormalizeISBN(s)
+		if !ValidISBN(isbn) {
+			fail("ISBN tidak valid: %q", s)
+		} else {
+			b.ISBN = isbn
+		}
+	}
+
+	if s := clean(rec.Cover); s != "" {
+		imageURL, msg := coverURL(s, opts.CoverExists)
+		if msg != "" {
+			fail("%s", msg)
+		} else {
+			b.ImageURL = imageURL
+		}
+	}
+
+	if len(row.Errors) > 0 {
+		row.Status = RowInvalid
+	}
+	return row
+}
+
+// coverURL mengubah kolom sampul menjadi image_url. URL http(s) dipakai apa adanya; selain itu
+// dianggap nama berkas yang sudah diunggah ke upload/books.
+func coverURL(s string, exists func(string) bool) (string, string) {
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		if len(s) > 255 {
+			return "", "URL sampul maksimal 255 karakter"
+		}
+		return s, ""
+	}
+	name := strings.TrimPrefix(s, "/upload/books/")
+	if name == "" || name != path.Base(name) || strings.ContainsAny(name, `\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Sprintf("Nama berkas sampul tidak valid: %q", s)
+	}
+	if exists != nil && !exists(name) {
+		return "", fmt.Sprintf("Berkas sampul %q tidak ditemukan di upload/books", name)
+	}
+	return "/upload/books/" + name, ""
+}
+
+// markDuplicate menandai baris yang sudah ada di katalog atau muncul lebih dulu di berkas.
+// Buku dianggap sama jika ISBN-nya sama, atau judul dan penulisnya sama dan salah satunya
+// tidak memiliki ISBN (ISBN berbeda berarti edisi berbeda).
+func markDuplicate(row *models.ImportRow, byISBN map[string]int, byTitle map[string][]models.Book,
+	seenISBN map[string]int, seenTitle map[string][]models.ImportRow) {
+	b := row.Book
+	if b.ISBN != "" {
+		if id, ok := byISBN[b.ISBN]; ok {
+			row.Status, row.DuplicateOf = RowDuplicate, id
+			row.Errors = append(row.Errors, fmt.Sprintf("ISBN %s sudah ada di katalog (buku #%d)", b.ISBN, id))
+			return
+		}
+		if line, ok := seenISBN[b.ISBN]; ok {
+			row.Status, row.DuplicateLine = RowDuplicate, line
+			row.Errors = append(row.Errors, fmt.Sprintf("ISBN %s sama dengan baris %d", b.ISBN, line))
+			return
+		}
+	}
+	key := titleKey(b.Title, b.Author)
+	for _, e := range byTitle[key] {
+		if b.ISBN == "" || e.ISBN == "" {
+			row.Status, row.DuplicateOf = RowDuplicate, e.ID
+			row.Errors = append(row.Errors, fmt.Sprintf("Judul dan penulis sudah ada di katalog (buku #%d)", e.ID))
+			return
+		}
+	}
+	for _, prev := range seenTitle[key] {
+		if b.ISBN == "" || prev.Book.ISBN == "" {
+			row.Status, row.DuplicateLine = RowDuplicate, prev.Line
+			row.Errors = append(row.Errors, fmt.Sprintf("Judul dan penulis sama dengan baris %d", prev.Line))
+			return
+		}
+	}
+}
+
+// titleKey membentuk kunci pembanding judul dan penulis (tanpa beda huruf besar dan spasi).
+func titleKey(title, author string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " ")) + "\x00" +
+		strings.ToLower(strings.Join(strings.Fields(author), " "))
+}
+
+// clean merapikan nilai sel: membuang spasi di tepi dan tanda kutip pelindung rumus yang
+// ditambahkan ekspor CSV (mis. "'=..." menjadi "=...").
+func clean(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+		s = s[1:]
+	}
+	return s
+}
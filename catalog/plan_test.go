@@ -0,0 +1,124 @@
+package catalog_test
+
+import (
+	"latihan_cloud8/catalog"
+	"latihan_cloud8/models"
+	"reflect"
+	"testing"
+)
+
+func TestPlanStock(t *testing.T) {
+	tests := []struct {
+		stock     string
+		wantValid bool
+		want      int
+	}{
+		{"", true, 2}, // DefaultStock
+		{"0", true, 0},
+		{" 7 ", true, 7},
+		{"1000", true, catalog.MaxStock},
+		{"1001", false, 0},
+		{"-1", false, 0},
+		{"tiga", false, 0},
+	}
+	for _, tt := range tests {
+		rec := catalog.Record{Line: 2, Title: "Laskar Pelangi", Stock: tt.stock}
+		result := catalog.Plan([]catalog.Record{rec}, nil, nil, catalog.Options{DefaultStock: 2})
+		row := result.Rows[0]
+		if valid := row.Status == catalog.RowValid; valid != tt.wantValid {
+			t.Errorf("stock %q: status = %s %v", tt.stock, row.Status, row.Errors)
+			continue
+		}
+		if tt.wantValid && (row.Book.Stock != tt.want || result.Copies != tt.want) {
+			t.Errorf("stock %q: book stock = %d, copies = %d, want %d", tt.stock, row.Book.Stock, result.Copies, tt.want)
+		}
+	}
+}
+
+func TestPlanDuplicatesInFile(t *testing.T) {
+	records := []catalog.Record{
+		{Line: 2, Title: "Laskar Pelangi", Author: "Andrea Hirata", ISBN: "978-979-3062-79-2"},
+		// ISBN sama setelah dinormalisasi
+		{Line: 3, Title: "Laskar Pelangi (Cetakan 2)", Author: "Andrea Hirata", ISBN: "9789793062792"},
+		// Judul dan penulis sama tanpa ISBN, beda spasi dan huruf besar
+		{Line: 4, Title: "Bumi  Manusia", Author: "Pramoedya"},
+		{Line: 5, Title: "bumi manusia", Author: "PRAMOEDYA"},
+		// Judul sama tetapi ISBN berbeda adalah edisi lain
+		{Line: 6, Title: "Pemrograman Go", Author: "Smith", ISBN: "0306406152"},
+		{Line: 7, Title: "Pemrograman Go", Author: "Smith", ISBN: "9780306406157"},
+		// Judul sama, salah satunya tanpa ISBN
+		{Line: 8, Title: "Pemrograman Go", Author: "Smith"},
+		// Baris tidak valid tidak dihitung sebagai pembanding duplikat
+		{Line: 9, Title: "Ronggeng Dukuh Paruk", ISBN: "9789792201475", Stock: "5000"},
+		{Line: 10, Title: "Ronggeng Dukuh Paruk", ISBN: "9789792201475"},
+	}
+	result := catalog.Plan(records, nil, nil, catalog.Options{DefaultStock: 1})
+
+	type row struct {
+		status        string
+		duplicateLine int
+	}
+	want := []row{
+		{catalog.RowValid, 0},
+		{catalog.RowDuplicate, 2},
+		{catalog.RowValid, 0},
+		{catalog.RowDuplicate, 4},
+		{catalog.RowValid, 0},
+		{catalog.RowValid, 0},
+		{catalog.RowDuplicate, 6},
+		{catalog.RowInvalid, 0},
+		{catalog.RowValid, 0},
+	}
+	for i, r := range result.Rows {
+		if got := (row{r.Status, r.DuplicateLine}); got != want[i] {
+			t.Errorf("line %d = %+v %v, want %+v", r.Line, got, r.Errors, want[i])
+		}
+	}
+	if result.Valid != 5 || result.Duplicates != 3 || result.Invalid != 1 || result.Copies != 5 {
+		t.Errorf("valid=%d duplicates=%d invalid=%d copies=%d, want 5/3/1/5",
+			result.Valid, result.Duplicates, result.Invalid, result.Copies)
+	}
+	if books := catalog.Books(result); len(books) != 5 || books[0].ISBN != "9789793062792" {
+		t.Errorf("books = %+v", books)
+	}
+}
+
+func TestPlanDuplicatesInCatalog(t *testing.T) {
+	existing := []models.Book{
+		{ID: 7, Title: "Laskar Pelangi", Author: "Andrea Hirata", ISBN: "9789793062792"},
+		{ID: 8, Title: "Bumi Manusia", Author: "Pramoedya"},
+	}
+	records := []catalog.Record{
+		{Line: 2, Title: "Laskar Pelangi Edisi Baru", ISBN: "9789793062792"},
+		{Line: 3, Title: "Bumi Manusia", Author: "Pramoedya", ISBN: "9789799731234"},
+		{Line: 4, Title: "Laskar Pelangi", Author: "Andrea Hirata", ISBN: "0306406152"},
+	}
+	result := catalog.Plan(records, existing, nil, catalog.Options{})
+
+	want := []int{7, 8, 0}
+	for i, r := range result.Rows {
+		if r.DuplicateOf != want[i] {
+			t.Errorf("line %d duplicate_of = %d (%s), want %d", r.Line, r.DuplicateOf, r.Status, want[i])
+		}
+	}
+}
+
+func TestPlanCategories(t *testing.T) {
+	categories := []models.Category{{ID: 1, Name: "Fiksi"}}
+	records := []catalog.Record{
+		{Line: 2, Title: "Satu", Category: "fiksi"},
+		{Line: 3, Title: "Dua", Category: "Sains"},
+		{Line: 4, Title: "Tiga", Category: "sains"},
+		// Kategori dari baris yang tidak dibuat tidak ikut dibuat
+		{Line: 5, Title: "Empat", Category: "Sejarah", Stock: "x"},
+		{Line: 6, Title: "Satu", Category: "Biografi"},
+	}
+	result := catalog.Plan(records, nil, categories, catalog.Options{})
+
+	if got := []string{result.Rows[0].Book.Category, result.Rows[2].Book.Category}; !reflect.DeepEqual(got, []string{"Fiksi", "Sains"}) {
+		t.Errorf("categories = %v, want existing spelling", got)
+	}
+	if !reflect.DeepEqual(result.NewCategories, []string{"Sains"}) {
+		t.Errorf("new categories = %v, want [Sains]", result.NewCategories)
+	}
+}
@@ -0,0 +1,74 @@
+package documents
+
+import (
+	"latihan_cloud8/models"
+	"latihan_cloud8/pdf"
+	"latihan_cloud8/store"
+	"time"
+)
+
+// FineReceipt adalah data kuitansi pembayaran atau bukti pembebasan denda.
+type FineReceipt struct {
+	Entry   *models.FineEntry
+	User    *models.User
+	Balance *models.FineBalance // Saldo denda anggota saat dicetak, opsional
+}
+
+// RenderFineReceipt membuat kuitansi pembayaran denda (atau bukti pembebasan untuk waiver)
+// berukuran A5.
+func RenderFineReceipt(lh *models.Letterhead, receipt FineReceipt, now time.Time) *pdf.Document {
+	e := receipt.Entry
+	title, docTitle := "KUITANSI PEMBAYARAN DENDA", "Kuitansi Pembayaran Denda"
+	if e.Type == store.FineWaiver {
+		title, docTitle = "BUKTI PEMBEBASAN DENDA", "Bukti Pembebasan Denda"
+	}
+	s := newSheet(pdf.A5Width, pdf.A5Height, lh, docTitle)
+	s.title(title, "No. "+store.FineDocumentNo(e))
+
+	if e.Type == store.FineWaiver {
+		s.field("Dibebaskan untuk", memberName(receipt.User))
+	} else {
+		s.field("Telah terima dari", memberName(receipt.User))
+	}
+	if receipt.User.NIP != "" {
+		s.field("NIP/NPM", receipt.User.NIP)
+	}
+	s.field("Username", receipt.User.Username)
+	s.space(6)
+
+	s.field("Sejumlah", rupiah(e.Amount))
+	s.field("Terbilang", capitalize(terbilang(e.Amount)))
+	purpose := "Pembayaran denda keterlambatan pengembalian buku"
+	if e.Type == store.FineWaiver {
+		purpose = "Pembebasan denda keterlambatan pengembalian buku"
+	}
+	if e.Book != nil && e.Book.Title != "" {
+		purpose += " \"" + e.Book.Title + "\""
+	}
+	s.field("Untuk", purpose)
+	if e.Type == store.FinePayment {
+		method := "Tunai"
+		if e.Method == store.PaymentTransfer {
+			method = "Transfer"
+		}
+		s.field("Cara Bayar", method)
+	}
+	if e.Reason != "" {
+		s.field("Alasan", e.Reason)
+	}
+	s.field("Tanggal", dateTime(e.CreatedAt))
+	if receipt.Balance != nil {
+		s.field("Sisa Tunggakan", rupiah(receipt.Balance.Outstanding))
+	}
+
+	// Kotak nominal seperti kuitansi tulis
+	s.space(10)
+	amount := rupiah(e.Amount)
+	w := pdf.TextWidth(pdf.Bold, 12, amount) + 24
+	s.page.Rect(s.left(), s.y-14, w, 22, 1)
+	s.page.Text(s.left()+12, s.y+1, pdf.Bold, 12, amount)
+	s.space(4)
+
+	s.signature([]string{joinNonEmpty(", ", lh.City, date(e.CreatedAt)), "Petugas,"}, e.RecordedByName, "")
+	return s.finish(dateTime(now))
+}
@@ -0,0 +1,116 @@
+package documents
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var monthNames = [...]string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli",
+	"Agustus", "September", "Oktober", "November", "Desember"}
+
+var dayNames = [...]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+// date memformat tanggal dalam bahasa Indonesia, mis. "16 Oktober 2026".
+func date(t time.Time) string {
+	return strconv.Itoa(t.Day()) + " " + monthNames[t.Month()] + " " + strconv.Itoa(t.Year())
+}
+
+// longDate menambahkan nama hari, mis. "Jumat, 16 Oktober 2026".
+func longDate(t time.Time) string {
+	return dayNames[t.Weekday()] + ", " + date(t)
+}
+
+// dateTime memformat tanggal beserta jam, mis. "16 Oktober 2026 14:05".
+func dateTime(t time.Time) string {
+	return date(t) + " " + t.Format("15:04")
+}
+
+// monthYear memformat bulan laporan, mis. "Oktober 2026".
+func monthYear(t time.Time) string {
+	return monthNames[t.Month()] + " " + strconv.Itoa(t.Year())
+}
+
+// rupiah memformat nominal dengan pemisah ribuan titik, mis. "Rp 12.500".
+func rupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return sign + "Rp " + thousands(amount)
+}
+
+// thousands memformat bilangan bulat dengan pemisah ribuan titik.
+func thousands(n int) string {
+	s := strconv.Itoa(n)
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+var digitWords = [...]string{"", "satu", "dua", "tiga", "empat", "lima", "enam", "tujuh", "delapan",
+	"sembilan", "sepuluh", "sebelas"}
+
+// terbilang menuliskan nominal dengan kata-kata untuk kuitansi, mis. 12500 menjadi
+// "dua belas ribu lima ratus rupiah".
+func terbilang(amount int) string {
+	if amount == 0 {
+		return "nol rupiah"
+	}
+	words := spell(amount)
+	if amount < 0 {
+		words = "minus " + spell(-amount)
+	}
+	return words + " rupiah"
+}
+
+func spell(n int) string {
+	switch {
+	case n < 12:
+		return digitWords[n]
+	case n < 20:
+		return digitWords[n-10] + " belas"
+	case n < 100:
+		return joinNonEmpty(" ", digitWords[n/10]+" puluh", spell(n%10))
+	case n < 200:
+		return joinNonEmpty(" ", "seratus", spell(n-100))
+	case n < 1000:
+		return joinNonEmpty(" ", digitWords[n/100]+" ratus", spell(n%100))
+	case n < 2000:
+		return joinNonEmpty(" ", "seribu", spell(n-1000))
+	case n < 1000000:
+		return joinNonEmpty(" ", spell(n/1000)+" ribu", spell(n%1000))
+	case n < 1000000000:
+		return joinNonEmpty(" ", spell(n/1000000)+" juta", spell(n%1000000))
+	default:
+		return joinNonEmpty(" ", spell(n/1000000000)+" miliar", spell(n%1000000000))
+	}
+}
+
+// capitalize membesarkan huruf pertama kalimat.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// roleLabel menerjemahkan role anggota untuk dokumen cetak.
+func roleLabel(role string) string {
+	switch role {
+	case "mahasiswa":
+		return "Mahasiswa"
+	case "dosen":
+		return "Dosen"
+	case "admin":
+		return "Admin"
+	default:
+		return capitalize(role)
+	}
+}
@@ -0,0 +1,128 @@
+package documents
+
+import (
+	"fmt"
+	"latihan_cloud8/models"
+	"latihan_cloud8/pdf"
+	"latihan_cloud8/store"
+	"time"
+)
+
+// LoanSlip adalah data slip peminjaman. Loan wajib terisi; User dan Book memakai data lengkap
+// anggota dan buku (bukan ringkasan pada Loan).
+type LoanSlip struct {
+	Loan       *models.Loan
+	User       *models.User
+	Book       *models.Book
+	FinePerDay int // Tarif denda kebijakan yang berlaku untuk pinjaman ini
+}
+
+// RenderLoanSlip membuat slip peminjaman berukuran A5.
+func RenderLoanSlip(lh *models.Letterhead, slip LoanSlip, now time.Time) *pdf.Document {
+	s := newSheet(pdf.A5Width, pdf.A5Height, lh, "Slip Peminjaman Buku")
+	s.title("SLIP PEMINJAMAN BUKU", "No. "+store.LoanSlipNo(slip.Loan))
+
+	memberFields(s, slip.User)
+	s.space(6)
+	bookFields(s, slip.Book, slip.Loan)
+	s.field("Tanggal Pinjam", dateTime(slip.Loan.LoanDate))
+	s.field("Jatuh Tempo", longDate(slip.Loan.DueDate))
+	if slip.Loan.Renewals > 0 {
+		s.field("Perpanjangan", fmt.Sprintf("%d kali", slip.Loan.Renewals))
+	}
+
+	s.space(8)
+	s.paragraph(pdf.Italic, smallSize, fmt.Sprintf("Harap kembalikan buku paling lambat pada tanggal jatuh tempo. "+
+		"Keterlambatan dikenakan denda %s per hari (hari libur perpustakaan tidak dihitung). "+
+		"Simpan slip ini sebagai bukti peminjaman.", rupiah(slip.FinePerDay)))
+
+	s.signature([]string{date(now), "Peminjam,"}, memberName(slip.User), "")
+	return s.finish(dateTime(now))
+}
+
+// ReturnReceipt adalah data bukti pengembalian.
+type ReturnReceipt struct {
+	Loan    *models.Loan
+	User    *models.User
+	Book    *models.Book
+	Balance *models.FineBalance // Saldo denda anggota saat dicetak, opsional
+}
+
+// RenderReturnReceipt membuat bukti pengembalian berukuran A5 beserta rincian denda.
+func RenderReturnReceipt(lh *models.Letterhead, receipt ReturnReceipt, now time.Time) *pdf.Document {
+	loan := receipt.Loan
+	s := newSheet(pdf.A5Width, pdf.A5Height, lh, "Bukti Pengembalian Buku")
+	s.title("BUKTI PENGEMBALIAN BUKU", "No. "+store.ReturnReceiptNo(loan))
+
+	memberFields(s, receipt.User)
+	s.space(6)
+	bookFields(s, receipt.Book, loan)
+	s.field("Tanggal Pinjam", date(loan.LoanDate))
+	s.field("Jatuh Tempo", date(loan.DueDate))
+	if loan.ReturnDate != nil {
+		s.field("Tanggal Kembali", dateTime(*loan.ReturnDate))
+	}
+	late := "Tepat waktu"
+	if loan.ReturnDate != nil && loan.ReturnDate.After(loan.DueDate) {
+		if days := daysBetween(loan.DueDate, *loan.ReturnDate); days > 0 {
+			late = fmt.Sprintf("Terlambat %d hari", days)
+		}
+	}
+	s.field("Keterangan", late)
+
+	s.space(6)
+	s.field("Denda", rupiah(loan.Fine))
+	if loan.Fine > 0 {
+		s.field("Terbilang", capitalize(terbilang(loan.Fine)))
+	}
+	if b := receipt.Balance; b != nil {
+		s.field("Sisa Tunggakan", rupiah(b.Outstanding))
+		if b.Outstanding > 0 {
+			s.space(4)
+			s.paragraph(pdf.Italic, smallSize, "Denda dapat dibayarkan di meja sirkulasi. Tunggakan denda "+
+				"dapat menyebabkan peminjaman berikutnya diblokir.")
+		}
+	}
+
+	s.signature([]string{date(now), "Petugas,"}, "", "")
+	return s.finish(dateTime(now))
+}
+
+// memberFields mencetak identitas anggota.
+func memberFields(s *sheet, u *models.User) {
+	s.field("Nama", memberName(u))
+	if u.NIP != "" {
+		s.field("NIP/NPM", u.NIP)
+	}
+	s.field("Username", u.Username)
+	s.field("Status", roleLabel(u.Role))
+}
+
+// bookFields mencetak identitas buku dan eksemplar yang dipinjam.
+func bookFields(s *sheet, b *models.Book, loan *models.Loan) {
+	s.field("Judul Buku", b.Title)
+	if b.Author != "" {
+		s.field("Penulis", b.Author)
+	}
+	if b.Category != "" {
+		s.field("Kategori", b.Category)
+	}
+	if loan.Item != nil && loan.Item.Barcode != "" {
+		s.field("Barcode", loan.Item.Barcode)
+	}
+}
+
+func memberName(u *models.User) string {
+	if u.Fullname != "" {
+		return u.Fullname
+	}
+	return u.Username
+}
+
+// daysBetween menghitung selisih hari kalender (zona lokal) dari a ke b.
+func daysBetween(a, b time.Time) int {
+	a, b = a.Local(), b.Local()
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
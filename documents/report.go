@@ -0,0 +1,150 @@
+package documents
+
+import (
+	"fmt"
+	"latihan_cloud8/models"
+	"latihan_cloud8/pdf"
+	"latihan_cloud8/store"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RenderMonthlyReport membuat laporan sirkulasi bulanan berukuran A4 untuk ditandatangani
+// kepala perpustakaan. sum harus berinterval harian dengan rentang satu bulan penuh.
+func RenderMonthlyReport(lh *models.Letterhead, sum *models.ReportSummary, now time.Time) *pdf.Document {
+	month := sum.Range.Start
+	s := newSheet(pdf.A4Width, pdf.A4Height, lh, "Laporan Sirkulasi "+monthYear(month))
+	s.title("LAPORAN SIRKULASI PERPUSTAKAAN", "Periode "+date(sum.Range.Start)+" s.d. "+date(sum.Range.End.AddDate(0, 0, -1)))
+
+	loans, returned := 0, 0
+	for _, v := range sum.Volume {
+		loans += v.Loans
+		returned += v.Returned
+	}
+	s.heading("A. Ringkasan")
+	s.field("Total Peminjaman", thousands(loans))
+	s.field("Total Pengembalian", thousands(returned))
+	s.field("Denda Ditagihkan", rupiah(sum.Fines.Charged))
+	s.field("Denda Dibayar", rupiah(sum.Fines.Collected))
+	s.field("Denda Dibebaskan", rupiah(sum.Fines.Waived))
+	s.field("Denda Berjalan", rupiah(sum.Fines.Accruing)+" (per "+date(now)+")")
+	s.field("Tingkat Keterlambatan", fmt.Sprintf("%s%% (%d dari %d pinjaman jatuh tempo, %d belum kembali)",
+		percent(sum.Overdue.OverdueRate), sum.Overdue.Overdue, sum.Overdue.Due, sum.Overdue.StillOpen))
+
+	s.heading("B. Peminjaman Harian")
+	s.table(
+		column{title: "Tanggal", width: 0.25},
+		column{title: "Dipinjam", width: 0.11, right: true},
+		column{title: "Dikembalikan", width: 0.14, right: true},
+		column{title: "Denda Ditagihkan", width: 0.18, right: true},
+		column{title: "Denda Dibayar", width: 0.16, right: true},
+		column{title: "Dibebaskan", width: 0.16, right: true},
+	)
+	fines := make(map[string]models.FinePeriod, len(sum.Fines.Periods))
+	for _, p := range sum.Fines.Periods {
+		fines[p.Period] = p
+	}
+	for _, v := range sum.Volume {
+		f := fines[v.Period]
+		s.row(periodLabel(v.Period), thousands(v.Loans), thousands(v.Returned),
+			rupiah(f.Charged), rupiah(f.Collected), rupiah(f.Waived))
+	}
+	s.totalRow("Jumlah", thousands(loans), thousands(returned),
+		rupiah(sum.Fines.Charged), rupiah(sum.Fines.Collected), rupiah(sum.Fines.Waived))
+	s.endTable()
+
+	s.heading("C. Judul Terpopuler")
+	if len(sum.TopTitles) == 0 {
+		s.paragraph(pdf.Italic, fontSize, "Tidak ada peminjaman pada periode ini.")
+	} else {
+		s.table(
+			column{title: "No", width: 0.06, right: true},
+			column{title: "Judul", width: 0.44},
+			column{title: "Penulis", width: 0.22},
+			column{title: "Kategori", width: 0.16},
+			column{title: "Dipinjam", width: 0.12, right: true},
+		)
+		for i, t := range sum.TopTitles {
+			s.row(strconv.Itoa(i+1), t.Title, t.Author, t.Category, thousands(t.Loans))
+		}
+		s.endTable()
+	}
+
+	s.heading("D. Kategori Terpopuler")
+	if len(sum.TopCategories) == 0 {
+		s.paragraph(pdf.Italic, fontSize, "Tidak ada peminjaman pada periode ini.")
+	} else {
+		s.table(
+			column{title: "No", width: 0.06, right: true},
+			column{title: "Kategori", width: 0.76},
+			column{title: "Dipinjam", width: 0.18, right: true},
+		)
+		for i, c := range sum.TopCategories {
+			category := c.Category
+			if category == "" {
+				category = "(Tanpa kategori)"
+			}
+			s.row(strconv.Itoa(i+1), category, thousands(c.Loans))
+		}
+		s.endTable()
+	}
+
+	s.heading("E. Peminjam Teraktif")
+	if len(sum.TopBorrowers) == 0 {
+		s.paragraph(pdf.Italic, fontSize, "Tidak ada peminjaman pada periode ini.")
+	} else {
+		s.table(
+			column{title: "No", width: 0.06, right: true},
+			column{title: "Nama", width: 0.36},
+			column{title: "Username", width: 0.18},
+			column{title: "Status", width: 0.14},
+			column{title: "Dipinjam", width: 0.11, right: true},
+			column{title: "Denda", width: 0.15, right: true},
+		)
+		for i, b := range sum.TopBorrowers {
+			name := b.Fullname
+			if name == "" {
+				name = b.Username
+			}
+			s.row(strconv.Itoa(i+1), name, b.Username, roleLabel(b.Role), thousands(b.Loans), rupiah(b.Fines))
+		}
+		s.endTable()
+	}
+
+	s.heading("F. Peminjaman per Status Anggota")
+	s.table(
+		column{title: "Status", width: 0.22},
+		column{title: "Peminjam", width: 0.14, right: true},
+		column{title: "Dipinjam", width: 0.14, right: true},
+		column{title: "Dikembalikan", width: 0.16, right: true},
+		column{title: "Terlambat", width: 0.14, right: true},
+		column{title: "Denda", width: 0.20, right: true},
+	)
+	for _, r := range sum.Roles {
+		s.row(roleLabel(r.Role), thousands(r.Borrowers), thousands(r.Loans), thousands(r.Returned),
+			thousands(r.Overdue), rupiah(r.Fines))
+	}
+	s.endTable()
+
+	note := ""
+	if lh.HeadNIP != "" {
+		note = "NIP. " + lh.HeadNIP
+	}
+	s.signature([]string{joinNonEmpty(", ", lh.City, date(now)), "Mengetahui,", "Kepala Perpustakaan"}, lh.HeadName, note)
+	return s.finish(dateTime(now))
+}
+
+// periodLabel memformat periode laporan harian ("2006-01-02") dengan nama hari.
+func periodLabel(period string) string {
+	t, err := time.ParseInLocation(store.DateLayout, period, time.Local)
+	if err != nil {
+		return period
+	}
+	return longDate(t)
+}
+
+// percent memformat rasio 0-1 sebagai persen dengan satu desimal berkoma, mis. "12,5".
+func percent(rate float64) string {
+	return strings.Replace(strconv.FormatFloat(rate*100, 'f', 1, 64), ".", ",", 1)
+}
@@ -0,0 +1,283 @@
+// Package documents menyusun dokumen cetak SIMPUS (slip peminjaman, bukti pengembalian,
+// kuitansi denda dan laporan bulanan) sebagai PDF berkop surat perpustakaan.
+package documents
+
+import (
+	"fmt"
+	"latihan_cloud8/models"
+	"latihan_cloud8/pdf"
+	"strings"
+)
+
+// Ukuran huruf dan jarak baris dokumen.
+const (
+	fontSize   = 9.5
+	smallSize  = 8
+	lineHeight = 13
+)
+
+// column adalah satu kolom tabel: lebar dalam point dan perataan teks.
+type column struct {
+	title string
+	width float64
+	right bool
+}
+
+// sheet membantu menyusun dokumen dari atas ke bawah: menyimpan posisi baris (y), membuat
+// halaman baru saat ruang habis dan mencetak kop surat, judul tabel berulang serta nomor halaman.
+type sheet struct {
+	doc    *pdf.Document
+	page   *pdf.Page
+	lh     *models.Letterhead
+	margin float64
+	y      float64
+
+	// Judul tabel yang sedang ditulis, dicetak ulang setelah pindah halaman
+	columns []column
+}
+
+func newSheet(width, height float64, lh *models.Letterhead, title string) *sheet {
+	doc := pdf.New(width, height)
+	doc.Title = title
+	doc.Author = lh.LibraryName
+	doc.Subject = title
+	s := &sheet{doc: doc, lh: lh, margin: 36}
+	if width > pdf.A5Width {
+		s.margin = 48
+	}
+	s.newPage()
+	return s
+}
+
+func (s *sheet) left() float64  { return s.margin }
+func (s *sheet) right() float64 { return s.doc.Width() - s.margin }
+func (s *sheet) width() float64 { return s.right() - s.left() }
+
+// newPage menambah halaman: kop surat lengkap di halaman pertama, kop ringkas di halaman berikutnya.
+func (s *sheet) newPage() {
+	first := len(s.doc.Pages()) == 0
+	s.page = s.doc.AddPage()
+	s.y = s.margin
+	if first {
+		s.letterhead()
+	} else {
+		s.y += smallSize
+		s.page.Text(s.left(), s.y, pdf.Bold, smallSize, s.lh.LibraryName)
+		s.page.TextRight(s.right(), s.y, pdf.Italic, smallSize, s.doc.Title)
+		s.y += 5
+		s.page.Line(s.left(), s.y, s.right(), s.y, 0.5)
+		s.y += lineHeight
+	}
+	if s.columns != nil {
+		s.tableHeader()
+	}
+}
+
+// letterhead mencetak kop surat di tengah halaman diikuti garis ganda.
+func (s *sheet) letterhead() {
+	center := s.doc.Width() / 2
+	if s.lh.Institution != "" {
+		s.y += 10
+		s.page.TextCenter(center, s.y, pdf.Regular, 10, strings.ToUpper(s.lh.Institution))
+		s.y += 4
+	}
+	s.y += 14
+	s.page.TextCenter(center, s.y, pdf.Bold, 14, strings.ToUpper(s.lh.LibraryName))
+
+	address := joinNonEmpty(", ", s.lh.Address, s.lh.City)
+	if address != "" {
+		s.y += 12
+		s.page.TextCenter(center, s.y, pdf.Regular, smallSize, address)
+	}
+	var contact []string
+	if s.lh.Phone != "" {
+		contact = append(contact, "Telp. "+s.lh.Phone)
+	}
+	if s.lh.Email != "" {
+		contact = append(contact, "Email: "+s.lh.Email)
+	}
+	if s.lh.Website != "" {
+		contact = append(contact, s.lh.Website)
+	}
+	if len(contact) > 0 {
+		s.y += 11
+		s.page.TextCenter(center, s.y, pdf.Regular, smallSize, strings.Join(contact, " · "))
+	}
+	s.y += 7
+	s.page.Line(s.left(), s.y, s.right(), s.y, 1.5)
+	s.y += 2.5
+	s.page.Line(s.left(), s.y, s.right(), s.y, 0.5)
+	s.y += 22
+}
+
+// ensure pindah halaman jika ruang tersisa kurang dari h.
+func (s *sheet) ensure(h float64) {
+	if s.y+h > s.doc.Height()-s.margin-lineHeight {
+		s.newPage()
+	}
+}
+
+// title mencetak judul dokumen (tebal, di tengah) dan keterangan di bawahnya, mis. nomor dokumen.
+func (s *sheet) title(text, sub string) {
+	center := s.doc.Width() / 2
+	s.page.TextCenter(center, s.y, pdf.Bold, 12, text)
+	w := pdf.TextWidth(pdf.Bold, 12, text)
+	s.page.Line(center-w/2, s.y+2, center+w/2, s.y+2, 0.6)
+	if sub != "" {
+		s.y += lineHeight + 1
+		s.page.TextCenter(center, s.y, pdf.Regular, fontSize, sub)
+	}
+	s.y += lineHeight * 2
+}
+
+// heading mencetak judul bagian.
+func (s *sheet) heading(text string) {
+	s.ensure(lineHeight * 4)
+	s.y += 4
+	s.page.Text(s.left(), s.y, pdf.Bold, 10.5, text)
+	s.y += lineHeight + 2
+}
+
+// field mencetak pasangan "label : nilai"; nilai panjang dilanjutkan ke baris berikutnya.
+func (s *sheet) field(label, value string) {
+	labelWidth := 110.0
+	if s.doc.Width() <= pdf.A5Width {
+		labelWidth = 100
+	}
+	x := s.left() + labelWidth
+	lines := pdf.Wrap(pdf.Regular, fontSize, s.right()-x-8, value)
+	s.ensure(float64(len(lines)) * lineHeight)
+	s.page.Text(s.left(), s.y, pdf.Regular, fontSize, label)
+	s.page.Text(x, s.y, pdf.Regular, fontSize, ":")
+	for i, line := range lines {
+		if i > 0 {
+			s.y += lineHeight
+		}
+		s.page.Text(x+8, s.y, pdf.Regular, fontSize, line)
+	}
+	s.y += lineHeight
+}
+
+// paragraph mencetak teks rata kiri yang dipecah sesuai lebar halaman.
+func (s *sheet) paragraph(font pdf.Font, size float64, text string) {
+	for _, line := range pdf.Wrap(font, size, s.width(), text) {
+		s.ensure(lineHeight)
+		s.page.Text(s.left(), s.y, font, size, line)
+		s.y += lineHeight
+	}
+}
+
+// space menambah jarak vertikal.
+func (s *sheet) space(h float64) {
+	s.y += h
+}
+
+// table memulai tabel dengan judul kolom; lebar kolom dinyatakan sebagai bagian dari lebar
+// halaman sehingga jumlahnya 1.
+func (s *sheet) table(columns ...column) {
+	s.columns = make([]column, len(columns))
+	for i, c := range columns {
+		c.width *= s.width()
+		s.columns[i] = c
+	}
+	s.ensure(lineHeight * 3)
+	s.tableHeader()
+}
+
+func (s *sheet) tableHeader() {
+	s.page.FillRect(s.left(), s.y, s.width(), lineHeight+4, 0.88)
+	s.y += lineHeight - 1
+	s.cells(pdf.Bold, titles(s.columns))
+	s.y += 5
+}
+
+// row mencetak satu baris tabel; teks yang terlalu panjang dipotong.
+func (s *sheet) row(values ...string) {
+	s.ensure(lineHeight)
+	s.cells(pdf.Regular, values)
+	s.page.Line(s.left(), s.y+4, s.right(), s.y+4, 0.25)
+	s.y += lineHeight + 2
+}
+
+// totalRow mencetak baris jumlah bercetak tebal.
+func (s *sheet) totalRow(values ...string) {
+	s.ensure(lineHeight)
+	s.cells(pdf.Bold, values)
+	s.y += lineHeight + 2
+}
+
+func (s *sheet) cells(font pdf.Font, values []string) {
+	x := s.left()
+	for i, c := range s.columns {
+		if i < len(values) {
+			text := pdf.Fit(font, smallSize+0.5, c.width-8, values[i])
+			if c.right {
+				s.page.TextRight(x+c.width-4, s.y, font, smallSize+0.5, text)
+			} else {
+				s.page.Text(x+4, s.y, font, smallSize+0.5, text)
+			}
+		}
+		x += c.width
+	}
+}
+
+// endTable menutup tabel agar judul kolomnya tidak dicetak ulang di halaman berikutnya.
+func (s *sheet) endTable() {
+	s.columns = nil
+	s.y += lineHeight / 2
+}
+
+// signature mencetak blok tanda tangan rata kanan: baris pembuka (kota dan tanggal, jabatan),
+// ruang tanda tangan, lalu nama bergaris bawah dan keterangan di bawahnya.
+func (s *sheet) signature(opening []string, name, note string) {
+	s.ensure(float64(len(opening)+2)*lineHeight + 50)
+	x := s.right() - 150
+	if s.doc.Width() <= pdf.A5Width {
+		x = s.right() - 130
+	}
+	s.y += lineHeight
+	for _, line := range opening {
+		s.page.Text(x, s.y, pdf.Regular, fontSize, line)
+		s.y += lineHeight
+	}
+	s.y += 44
+	if name == "" {
+		name = strings.Repeat(".", 40)
+	}
+	s.page.Text(x, s.y, pdf.Bold, fontSize, name)
+	s.page.Line(x, s.y+2, x+pdf.TextWidth(pdf.Bold, fontSize, name), s.y+2, 0.5)
+	if note != "" {
+		s.y += lineHeight
+		s.page.Text(x, s.y, pdf.Regular, fontSize, note)
+	}
+	s.y += lineHeight
+}
+
+// finish menambah nomor halaman dan keterangan waktu cetak di kaki setiap halaman.
+func (s *sheet) finish(printed string) *pdf.Document {
+	pages := s.doc.Pages()
+	y := s.doc.Height() - s.margin/2
+	for i, p := range pages {
+		p.Text(s.left(), y, pdf.Italic, smallSize-1, "Dicetak "+printed)
+		p.TextRight(s.right(), y, pdf.Regular, smallSize-1, fmt.Sprintf("Halaman %d dari %d", i+1, len(pages)))
+	}
+	return s.doc
+}
+
+func titles(columns []column) []string {
+	t := make([]string, len(columns))
+	for i, c := range columns {
+		t[i] = c.title
+	}
+	return t
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
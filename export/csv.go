@@ -0,0 +1,71 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// utf8BOM ditulis di awal berkas agar Excel membaca CSV sebagai UTF-8.
+const utf8BOM = "\ufeff"
+
+type csvWriter struct {
+	w      io.Writer
+	csv    *csv.Writer
+	sheets int
+}
+
+// NewCSV membuat Writer CSV (pemisah koma, diawali BOM UTF-8).
+func NewCSV(w io.Writer) Writer {
+	return &csvWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Sheet(name string, header ...string) error {
+	if c.sheets == 0 {
+		if _, err := io.WriteString(c.w, utf8BOM); err != nil {
+			return err
+		}
+	} else {
+		if err := c.csv.Write(nil); err != nil {
+			return err
+		}
+		if err := c.csv.Write([]string{name}); err != nil {
+			return err
+		}
+	}
+	c.sheets++
+	return c.csv.Write(header)
+}
+
+func (c *csvWriter) Row(values ...any) error {
+	if c.sheets == 0 {
+		return errors.New("export: Row called before Sheet")
+	}
+	record := make([]string, len(values))
+	for i, v := range values {
+		s := text(v)
+		if _, isText := deref(v).(string); isText {
+			s = escapeFormula(s)
+		}
+		record[i] = s
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// escapeFormula mencegah teks dari pengguna (judul, nama) yang diawali =, +, - atau @
+// dijalankan sebagai rumus saat CSV dibuka di aplikasi spreadsheet.
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
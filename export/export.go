@@ -0,0 +1,108 @@
+// Package export menulis tabel ke berkas CSV atau XLSX secara streaming: setiap baris langsung
+// ditulis ke tujuan sehingga ekspor tabel besar tidak perlu dimuat seluruhnya ke memori.
+//
+// Nilai baris boleh berupa string, bilangan bulat, float64, bool, time.Time (tanggal dan jam),
+// Date (tanggal saja), *time.Time atau nil (sel kosong).
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format berkas ekspor.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Format tanggal pada CSV (gaya Indonesia, hari/bulan/tahun).
+const (
+	DateLayout     = "02/01/2006"
+	DateTimeLayout = "02/01/2006 15:04"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Date menandai waktu yang hanya ditampilkan tanggalnya.
+type Date time.Time
+
+// Writer menulis satu atau beberapa tabel. Sheet wajib dipanggil sebelum Row pertama;
+// Close wajib dipanggil untuk menyelesaikan berkas.
+type Writer interface {
+	// Sheet memulai tabel baru dengan judul kolom. Di XLSX setiap tabel menjadi worksheet
+	// tersendiri; di CSV tabel berikutnya dipisah baris kosong dan baris nama tabel.
+	Sheet(name string, header ...string) error
+	Row(values ...any) error
+	Close() error
+}
+
+// New membuat Writer untuk format yang diminta.
+func New(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSV(w), nil
+	case FormatXLSX:
+		return NewXLSX(w), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType mengembalikan MIME type berkas untuk format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// deref mengganti *time.Time dan Date menjadi nilai dasarnya; pointer nil menjadi nil.
+func deref(v any) any {
+	switch x := v.(type) {
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return *x
+	case *Date:
+		if x == nil {
+			return nil
+		}
+		return *x
+	}
+	return v
+}
+
+// text mengubah nilai menjadi teks untuk CSV.
+func text(v any) string {
+	switch x := deref(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Local().Format(DateTimeLayout)
+	case Date:
+		t := time.Time(x)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format(DateLayout)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		if x {
+			return "Ya"
+		}
+		return "Tidak"
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,255 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Indeks gaya sel pada styles.xml (cellXfs).
+const (
+	styleDefault  = 0
+	styleHeader   = 1
+	styleDate     = 2
+	styleDateTime = 3
+)
+
+// maxSheetName adalah panjang maksimal nama worksheet di Excel.
+const maxSheetName = 31
+
+// excelEpoch adalah tanggal nol nomor seri tanggal Excel (sistem 1900).
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const stylesXML = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="dd/mm/yyyy"/><numFmt numFmtId="165" formatCode="dd/mm/yyyy hh:mm"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+const rootRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// xlsxWriter menulis workbook XLSX minimal langsung ke arsip zip. Setiap worksheet ditulis
+// baris demi baris sebagai entri zip tersendiri (teks memakai inline string sehingga tidak perlu
+// tabel sharedStrings); workbook, relasi dan content type ditulis saat Close karena baru
+// saat itu daftar worksheet diketahui.
+type xlsxWriter struct {
+	zip    *zip.Writer
+	sheet  io.Writer // Entri worksheet yang sedang ditulis
+	sheets []string
+	row    int
+	buf    strings.Builder
+}
+
+// NewXLSX membuat Writer XLSX (Office Open XML).
+func NewXLSX(w io.Writer) Writer {
+	return &xlsxWriter{zip: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) Sheet(name string, header ...string) error {
+	if err := x.endSheet(); err != nil {
+		return err
+	}
+	x.sheets = append(x.sheets, sheetName(name, len(x.sheets)+1))
+	w, err := x.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(x.sheets)))
+	if err != nil {
+		return err
+	}
+	x.sheet = w
+	x.row = 0
+
+	x.buf.Reset()
+	x.buf.WriteString(xmlHeader)
+	x.buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	// Baris judul dibekukan agar tetap terlihat saat menggulir
+	x.buf.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	if len(header) > 0 {
+		x.buf.WriteString("<cols>")
+		for i, h := range header {
+			width := len([]rune(h)) + 4
+			if width < 14 {
+				width = 14
+			}
+			fmt.Fprintf(&x.buf, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		x.buf.WriteString("</cols>")
+	}
+	x.buf.WriteString("<sheetData>")
+	if _, err := io.WriteString(x.sheet, x.buf.String()); err != nil {
+		return err
+	}
+
+	values := make([]any, len(header))
+	for i, h := range header {
+		values[i] = h
+	}
+	return x.writeRow(styleHeader, values)
+}
+
+func (x *xlsxWriter) Row(values ...any) error {
+	if x.sheet == nil {
+		return errors.New("export: Row called before Sheet")
+	}
+	return x.writeRow(styleDefault, values)
+}
+
+// writeRow menulis satu baris; style berlaku untuk sel teks dan angka, sel waktu selalu
+// memakai gaya tanggal.
+func (x *xlsxWriter) writeRow(style int, values []any) error {
+	x.row++
+	x.buf.Reset()
+	fmt.Fprintf(&x.buf, `<row r="%d">`, x.row)
+	for i, v := range values {
+		ref := columnName(i) + strconv.Itoa(x.row)
+		switch val := deref(v).(type) {
+		case nil:
+			continue
+		case time.Time:
+			if !val.IsZero() {
+				x.numberCell(ref, styleDateTime, strconv.FormatFloat(serial(val), 'f', -1, 64))
+			}
+		case Date:
+			if t := time.Time(val); !t.IsZero() {
+				x.numberCell(ref, styleDate, strconv.FormatFloat(serial(t), 'f', -1, 64))
+			}
+		case int:
+			x.numberCell(ref, style, strconv.Itoa(val))
+		case int64:
+			x.numberCell(ref, style, strconv.FormatInt(val, 10))
+		case float64:
+			x.numberCell(ref, style, strconv.FormatFloat(val, 'f', -1, 64))
+		default:
+			x.textCell(ref, style, text(val))
+		}
+	}
+	x.buf.WriteString("</row>")
+	_, err := io.WriteString(x.sheet, x.buf.String())
+	return err
+}
+
+func (x *xlsxWriter) numberCell(ref string, style int, v string) {
+	fmt.Fprintf(&x.buf, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr(style), v)
+}
+
+func (x *xlsxWriter) textCell(ref string, style int, s string) {
+	fmt.Fprintf(&x.buf, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">`, ref, styleAttr(style))
+	xml.EscapeText(&x.buf, []byte(s))
+	x.buf.WriteString("</t></is></c>")
+}
+
+func styleAttr(style int) string {
+	if style == styleDefault {
+		return ""
+	}
+	return fmt.Sprintf(` s="%d"`, style)
+}
+
+// endSheet menutup worksheet yang sedang ditulis, jika ada.
+func (x *xlsxWriter) endSheet() error {
+	if x.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(x.sheet, "</sheetData></worksheet>")
+	x.sheet = nil
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if len(x.sheets) == 0 {
+		if err := x.Sheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+	if err := x.endSheet(); err != nil {
+		return err
+	}
+
+	var workbook, rels, types strings.Builder
+	workbook.WriteString(xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	types.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i, name := range x.sheets {
+		n := i + 1
+		workbook.WriteString(`<sheet name="`)
+		xml.EscapeText(&workbook, []byte(name))
+		fmt.Fprintf(&workbook, `" sheetId="%d" r:id="rId%d"/>`, n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+	}
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`, len(x.sheets)+1)
+	types.WriteString(`</Types>`)
+
+	parts := []struct{ name, body string }{
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", stylesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"[Content_Types].xml", types.String()},
+	}
+	for _, p := range parts {
+		w, err := x.zip.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, p.body); err != nil {
+			return err
+		}
+	}
+	return x.zip.Close()
+}
+
+// serial mengubah waktu (zona lokal) menjadi nomor seri tanggal Excel.
+func serial(t time.Time) float64 {
+	t = t.Local()
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+// columnName mengubah indeks kolom (mulai 0) menjadi huruf kolom Excel: A, B, ..., Z, AA, ...
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// sheetName membersihkan nama worksheet dari karakter yang ditolak Excel dan memotongnya
+// sesuai batas panjang; nama kosong diganti "Sheet<n>".
+func sheetName(name string, n int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if r := []rune(name); len(r) > maxSheetName {
+		name = string(r[:maxSheetName])
+	}
+	if name == "" {
+		name = "Sheet" + strconv.Itoa(n)
+	}
+	return name
+}
@@ -0,0 +1,150 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sheetXML adalah bagian worksheet yang diperiksa test.
+type sheetXML struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Style  string `xml:"s,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// unzip membaca semua entri arsip XLSX.
+func unzip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(body)
+	}
+	return files
+}
+
+func TestXLSXSheet(t *testing.T) {
+	header := make([]string, 28)
+	for i := range header {
+		header[i] = "Kolom " + columnName(i)
+	}
+	var nilTime *time.Time
+	row := make([]any, 28)
+	row[0] = `Buku <Baru> & "Edisi" Lama`
+	row[1] = 42
+	row[2] = Date(time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local))
+	row[3] = time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	row[4] = nilTime
+	row[26] = "kolom AA"
+	row[27] = 1.5
+
+	var buf bytes.Buffer
+	w := NewXLSX(&buf)
+	if err := w.Sheet("Buku & <Stok>", header...); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Row(row...); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := unzip(t, buf.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	if wb := files["xl/workbook.xml"]; !strings.Contains(wb, `<sheet name="Buku &amp; &lt;Stok&gt;"`) {
+		t.Errorf("sheet name not escaped in workbook: %s", wb)
+	}
+
+	raw := files["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(raw, "Buku &lt;Baru&gt; &amp; &#34;Edisi&#34; Lama") {
+		t.Errorf("text not escaped in sheet XML: %s", raw)
+	}
+	var sheet sheetXML
+	if err := xml.Unmarshal([]byte(raw), &sheet); err != nil {
+		t.Fatalf("sheet XML invalid: %v", err)
+	}
+	if len(sheet.Rows) != 2 || sheet.Rows[0].R != 1 || sheet.Rows[1].R != 2 {
+		t.Fatalf("rows = %+v, want rows 1 and 2", sheet.Rows)
+	}
+
+	headerRow := sheet.Rows[0].Cells
+	if len(headerRow) != 28 {
+		t.Fatalf("header has %d cells, want 28", len(headerRow))
+	}
+	for i, c := range headerRow {
+		if c.Ref != columnName(i)+"1" || c.Style != "1" || c.Inline != header[i] {
+			t.Errorf("header cell %d = %+v", i, c)
+		}
+	}
+	if headerRow[26].Ref != "AA1" || headerRow[27].Ref != "AB1" {
+		t.Errorf("header refs after Z = %s, %s, want AA1, AB1", headerRow[26].Ref, headerRow[27].Ref)
+	}
+
+	// Sel nil dilewati, jadi baris data hanya berisi sel yang terisi
+	type cell struct{ ref, typ, style, value string }
+	want := []cell{
+		{"A2", "inlineStr", "", row[0].(string)},
+		{"B2", "", "", "42"},
+		{"C2", "", "2", "46023"},
+		{"D2", "", "3", "46023.5"},
+		{"AA2", "inlineStr", "", "kolom AA"},
+		{"AB2", "", "", "1.5"},
+	}
+	got := sheet.Rows[1].Cells
+	if len(got) != len(want) {
+		t.Fatalf("data row has %d cells, want %d: %+v", len(got), len(want), got)
+	}
+	for i, c := range got {
+		value := c.Value
+		if c.Type == "inlineStr" {
+			value = c.Inline
+		}
+		if g := (cell{c.Ref, c.Type, c.Style, value}); g != want[i] {
+			t.Errorf("cell %d = %+v, want %+v", i, g, want[i])
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"}, {25, "Z"}, {26, "AA"}, {27, "AB"}, {51, "AZ"}, {52, "BA"}, {701, "ZZ"}, {702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.i); got != tt.want {
+			t.Errorf("columnName(%d) = %s, want %s", tt.i, got, tt.want)
+		}
+	}
+}
@@ -4,19 +4,24 @@ import (
 	"encoding/json"
 	"latihan_cloud8/middleware"
 	"latihan_cloud8/models"
+	"latihan_cloud8/notify"
 	"latihan_cloud8/store"
 	"latihan_cloud8/utils"
+	"latihan_cloud8/webhook"
+	"log"
 	"net/http"
+	"net/mail"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
-func NewAuthHandler(store *store.MySQLStore) *AuthHandler {
+func NewAuthHandler(store store.Store) *AuthHandler {
 	return &AuthHandler{Store: store}
 }
 
@@ -66,11 +71,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Buat user baru di database
-	_, err = h.Store.CreateUser(payload.Username, string(hashed), role, payload.Fullname)
+	user, err := h.Store.CreateUser(payload.Username, string(hashed), role, payload.Fullname)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	webhook.Emit(h.Store, webhook.EventUserRegistered, user)
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]string{
@@ -251,6 +257,10 @@ func (h *AuthHandler) UpdateSelf(w http.ResponseWriter, r *http.Request) {
 		NIP      string `json:"nip"`
 		Contact  string `json:"contact"`
 		Password string `json:"password,omitempty"`
+
+		// Opsional: tidak diubah jika tidak dikirim
+		Email              *string `json:"email"`
+		EmailNotifications *bool   `json:"email_notifications"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
@@ -263,12 +273,34 @@ func (h *AuthHandler) UpdateSelf(w http.ResponseWriter, r *http.Request) {
 	user.NIP = payload.NIP
 	user.Contact = payload.Contact
 
+	if payload.Email != nil {
+		user.Email = strings.TrimSpace(*payload.Email)
+		if user.Email != "" {
+			addr, err := mail.ParseAddress(user.Email)
+			if err != nil || addr.Address != user.Email {
+				http.Error(w, "Alamat email tidak valid", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if payload.EmailNotifications != nil {
+		user.EmailNotifications = *payload.EmailNotifications
+	}
+	if user.EmailNotifications && notify.EmailAddress(user) == "" {
+		http.Error(w, "Isi alamat email untuk menerima notifikasi email", http.StatusBadRequest)
+		return
+	}
+
 	// Update password jika ada (opsional)
 
 	if err := h.Store.UpdateUser(user); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := h.Store.SetEmailPreference(user.ID, user.Email, user.EmailNotifications); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// Simpan perubahan profil
 
@@ -276,6 +308,97 @@ func (h *AuthHandler) UpdateSelf(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Profile updated successfully"})
 }
 
+// BlockUser endpoint (khusus admin).
+// Memblokir peminjaman seorang pengguna secara manual; alasan wajib diisi dan dikirim ke pengguna.
+func (h *AuthHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		UserID string `json:"user_id"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	if payload.UserID == "" || payload.Reason == "" {
+		http.Error(w, "User ID and reason required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.SetUserBlock(payload.UserID, payload.Reason); err != nil {
+		writeUserError(w, err)
+		return
+	}
+	h.Store.CreateNotification(payload.UserID, store.NotifyAccount, store.SeverityCritical, "Akun Anda diblokir dari peminjaman: "+payload.Reason)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "User blocked"})
+}
+
+// UnblockUser endpoint (khusus admin).
+// Membuka blokir manual pengguna (?id=).
+func (h *AuthHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.SetUserBlock(id, ""); err != nil {
+		writeUserError(w, err)
+		return
+	}
+	h.Store.CreateNotification(id, store.NotifyAccount, store.SeverityInfo, "Blokir peminjaman pada akun Anda telah dibuka.")
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "User unblocked"})
+}
+
+// SetMembership endpoint (khusus admin).
+// Mengatur tanggal berakhirnya keanggotaan (format YYYY-MM-DD, kosong = tanpa batas).
+// Keanggotaan berlaku sampai akhir hari tersebut.
+func (h *AuthHandler) SetMembership(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		UserID    string `json:"user_id"`
+		ExpiresOn string `json:"expires_on"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if payload.UserID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	var expiresAt *time.Time
+	if payload.ExpiresOn != "" {
+		day, err := time.ParseInLocation(store.DateLayout, payload.ExpiresOn, time.Local)
+		if err != nil {
+			http.Error(w, "Invalid date, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		expiresAt = &day
+	}
+
+	if err := h.Store.SetMembershipExpiry(payload.UserID, expiresAt); err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Membership updated"})
+}
+
+// writeUserError memetakan error store pengguna ke respon HTTP.
+func writeUserError(w http.ResponseWriter, err error) {
+	if err == store.ErrUserNotFound {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
 // DeleteUser endpoint (khusus admin).
 // Fungsi ini menghapus pengguna dari database berdasarkan ID yang diberikan.
 func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
@@ -289,6 +412,10 @@ func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// Eksemplar yang disisihkan untuk reservasi pengguna ini diteruskan ke antrean lain
+	if _, err := store.ProcessHoldQueue(h.Store, 0); err != nil {
+		log.Println("Hold queue error:", err)
+	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "User deleted"})
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"latihan_cloud8/catalog"
 	"latihan_cloud8/models"
 	"latihan_cloud8/store"
+	"latihan_cloud8/webhook"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,10 +16,10 @@ import (
 )
 
 type BookHandler struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
-func NewBookHandler(store *store.MySQLStore) *BookHandler {
+func NewBookHandler(store store.Store) *BookHandler {
 	return &BookHandler{Store: store}
 }
 
@@ -47,6 +49,7 @@ func (h *BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 
 // CreateBook endpoint (khusus admin).
 // Menambah buku baru beserta upload gambar sampul.
+// Field stock menentukan jumlah eksemplar awal yang dibuat otomatis.
 func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	// Parse data form multipart (limit 10MB)
 	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB limit
@@ -57,15 +60,21 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	title := r.FormValue("title")
 	author := r.FormValue("author")
 	category := r.FormValue("category")
+	publisher := r.FormValue("publisher")
 	stockStr := r.FormValue("stock")
 	stock, _ := strconv.Atoi(stockStr)
 	yearStr := r.FormValue("published_year")
 	year, _ := strconv.Atoi(yearStr)
+	isbn := catalog.NormalizeISBN(r.FormValue("isbn"))
 
 	if title == "" || stock < 0 {
 		http.Error(w, "Invalid data. Title required, stock >= 0", http.StatusBadRequest)
 		return
 	}
+	if isbn != "" && !catalog.ValidISBN(isbn) {
+		http.Error(w, "ISBN tidak valid", http.StatusBadRequest)
+		return
+	}
 
 	// Proses upload gambar jika ada
 	imageURL := ""
@@ -101,8 +110,10 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		Title:         title,
 		Author:        author,
 		Category:      category,
+		Publisher:     publisher,
 		Stock:         stock,
 		PublishedYear: year,
+		ISBN:          isbn,
 		ImageURL:      imageURL,
 	}
 
@@ -111,6 +122,8 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	webhook.Emit(h.Store, webhook.EventBookCreated, book)
+
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(book)
 }
@@ -148,16 +161,24 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	if category := r.FormValue("category"); category != "" {
 		book.Category = category
 	}
-	if stockStr := r.FormValue("stock"); stockStr != "" {
-		if stock, err := strconv.Atoi(stockStr); err == nil {
-			book.Stock = stock
-		}
-	}
+	// Stok tidak diubah di sini; kelola lewat eksemplar (/api/books/items)
 	if yearStr := r.FormValue("published_year"); yearStr != "" {
 		if year, err := strconv.Atoi(yearStr); err == nil {
 			book.PublishedYear = year
 		}
 	}
+	// Penerbit dan ISBN boleh dikosongkan, jadi cukup periksa apakah field dikirim
+	if values, ok := r.Form["publisher"]; ok {
+		book.Publisher = values[0]
+	}
+	if values, ok := r.Form["isbn"]; ok {
+		isbn := catalog.NormalizeISBN(values[0])
+		if isbn != "" && !catalog.ValidISBN(isbn) {
+			http.Error(w, "ISBN tidak valid", http.StatusBadRequest)
+			return
+		}
+		book.ISBN = isbn
+	}
 
 	// Update gambar jika ada upload baru
 	file, handler, err := r.FormFile("image")
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"latihan_cloud8/catalog"
+	"latihan_cloud8/marc"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/webhook"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportSize adalah ukuran maksimal berkas impor katalog.
+const maxImportSize = 10 << 20 // 10MB
+
+// importTimeout menggantikan ReadTimeout dan WriteTimeout server untuk request impor:
+// mengunggah 10MB lewat koneksi lambat lalu menyimpan ribuan buku bisa lebih lama dari
+// batas server, tetapi body berasal dari klien sehingga batasnya tetap ada.
+const importTimeout = 5 * time.Minute
+
+// importFile membuka berkas impor dari field multipart "file", atau body request jika dikirim
+// langsung (mis. Content-Type: text/csv). Menulis error dan mengembalikan nil jika gagal.
+func importFile(w http.ResponseWriter, r *http.Request) io.Reader {
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(importTimeout)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		log.Printf("Import: read deadline not extended: %v", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		log.Printf("Import: write deadline not extended: %v", err)
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body
+	}
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return nil
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Berkas impor (field file) wajib diunggah", http.StatusBadRequest)
+		return nil
+	}
+	return file
+}
+
+// isTooLarge menulis 413 jika err berasal dari batas ukuran berkas impor.
+func isTooLarge(w http.ResponseWriter, err error) bool {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "Berkas impor maksimal 10MB", http.StatusRequestEntityTooLarge)
+		return true
+	}
+	return false
+}
+
+// coverExists memeriksa berkas sampul yang sudah diunggah ke upload/books.
+func coverExists(name string) bool {
+	info, err := os.Stat(filepath.Join("upload", "books", name))
+	return err == nil && !info.IsDir()
+}
+
+// planImport memvalidasi rekaman impor terhadap katalog dan kategori yang ada. defaultStock
+// dipakai untuk rekaman tanpa jumlah eksemplar.
+func (h *BookHandler) planImport(records []catalog.Record, defaultStock int) (*models.ImportResult, error) {
+	books, err := h.Store.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	categories, err := h.Store.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Plan(records, books, categories, catalog.Options{
+		CoverExists:  coverExists,
+		DefaultStock: defaultStock,
+	}), nil
+}
+
+// commitImport menyimpan hasil Plan jika dry run tidak diminta lalu mengirim hasilnya.
+// Impor ditolak seluruhnya (422) jika masih ada baris tidak valid; baris duplikat dilewati.
+func (h *BookHandler) commitImport(w http.ResponseWriter, r *http.Request, result *models.ImportResult) {
+	result.DryRun = r.URL.Query().Get("dry_run") == "true" || r.URL.Query().Get("dry_run") == "1"
+	w.Header().Set("Content-Type", "application/json")
+
+	if result.DryRun {
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+	if result.Invalid > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	books := catalog.Books(result)
+	if len(books) > 0 {
+		err := h.Store.ImportBooks(books, result.NewCategories)
+		if err == store.ErrISBNExists {
+			// Buku dengan ISBN yang sama ditambahkan setelah berkas divalidasi
+			http.Error(w, "Sebagian ISBN sudah ada di katalog sejak berkas divalidasi, ulangi impor untuk melihat duplikatnya", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			log.Println("Import books error:", err)
+			http.Error(w, "Error importing books", http.StatusInternalServerError)
+			return
+		}
+	}
+	result.Committed = true
+
+	// Salin ID buku yang baru dibuat ke baris hasil
+	i := 0
+	for j := range result.Rows {
+		if result.Rows[j].Status == catalog.RowValid {
+			result.Rows[j].Book = books[i]
+			i++
+		}
+	}
+	for i := range books {
+		webhook.Emit(h.Store, webhook.EventBookCreated, &books[i])
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// ImportBooks endpoint (khusus admin).
+// Impor katalog dari CSV (field multipart "file" atau body langsung) dengan kolom title,
+// author, category, publisher, stock, published_year, isbn, cover. Dengan ?dry_run=true hanya
+// memvalidasi tanpa menyimpan; tanpa dry run semua buku valid disimpan dalam satu transaksi
+// beserta kategori baru. Jika buku dengan ISBN yang sama ditambahkan setelah berkas divalidasi,
+// impor dibatalkan dengan 409.
+func (h *BookHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	file := importFile(w, r)
+	if file == nil {
+		return
+	}
+
+	records, err := catalog.ParseCSV(file)
+	if isTooLarge(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w, "CSV tidak valid: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.planImport(records, 0)
+	if err != nil {
+		http.Error(w, "Error fetching catalog", http.StatusInternalServerError)
+		return
+	}
+	h.commitImport(w, r, result)
+}
+
+// ImportMARC endpoint (khusus admin).
+// Impor katalog dari MARC21 biner (.mrc) atau MARCXML (?format=mrc|xml, bawaan dikenali dari
+// isi berkas). Rekaman bibliografis tidak memuat jumlah eksemplar, jadi setiap judul dibuat
+// dengan ?stock= eksemplar (bawaan 1). Validasi, dry run dan penyimpanan sama dengan ImportBooks.
+func (h *BookHandler) ImportMARC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	stock := 1
+	if s := r.URL.Query().Get("stock"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > catalog.MaxStock {
+			http.Error(w, fmt.Sprintf("stock harus antara 0 dan %d", catalog.MaxStock), http.StatusBadRequest)
+			return
+		}
+		stock = n
+	}
+	file := importFile(w, r)
+	if file == nil {
+		return
+	}
+
+	br := bufio.NewReader(file)
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		head, _ := br.Peek(512)
+		format = marc.Detect(head)
+	}
+	mr, err := marc.NewReader(format, br)
+	if err != nil {
+		http.Error(w, "Format harus mrc atau xml", http.StatusBadRequest)
+		return
+	}
+
+	var records []catalog.Record
+	for {
+		rec, err := mr.Read()
+		if err == io.EOF {
+			break
+		}
+		if isTooLarge(w, err) {
+			return
+		}
+		if err != nil {
+			http.Error(w, "MARC tidak valid: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(records) == catalog.MaxRows {
+			http.Error(w, catalog.ErrTooManyRows.Error(), http.StatusBadRequest)
+			return
+		}
+		records = append(records, catalog.RecordFromMARC(rec, len(records)+1))
+	}
+	if len(records) == 0 {
+		http.Error(w, "Berkas tidak berisi rekaman MARC", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.planImport(records, stock)
+	if err != nil {
+		http.Error(w, "Error fetching catalog", http.StatusInternalServerError)
+		return
+	}
+	h.commitImport(w, r, result)
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Nilai yang diizinkan untuk kondisi dan status eksemplar yang diatur admin.
+// Status "borrowed" dan "reserved" hanya diatur oleh proses peminjaman dan reservasi.
+var (
+	validItemConditions = map[string]bool{
+		store.ConditionGood:    true,
+		store.ConditionFair:    true,
+		store.ConditionDamaged: true,
+	}
+	validItemStatuses = map[string]bool{
+		store.ItemAvailable:   true,
+		store.ItemMaintenance: true,
+		store.ItemLost:        true,
+	}
+)
+
+// writeItemError memetakan error store eksemplar ke respon HTTP.
+func writeItemError(w http.ResponseWriter, err error) {
+	switch err {
+	case store.ErrBookNotFound:
+		http.Error(w, "Book not found", http.StatusNotFound)
+	case store.ErrItemNotFound:
+		http.Error(w, "Copy not found", http.StatusNotFound)
+	case store.ErrBarcodeExists, store.ErrItemBorrowed, store.ErrItemReserved, store.ErrItemHasLoans:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetItems endpoint (khusus admin).
+// Mengambil daftar eksemplar dari sebuah buku (?book_id=).
+func (h *BookHandler) GetItems(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.URL.Query().Get("book_id"))
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.Store.GetBookItems(bookID)
+	if err != nil {
+		http.Error(w, "Error fetching copies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// LookupItem endpoint.
+// Mencari eksemplar berdasarkan barcode (?barcode=), misal dari hasil scan.
+func (h *BookHandler) LookupItem(w http.ResponseWriter, r *http.Request) {
+	barcode := r.URL.Query().Get("barcode")
+	if barcode == "" {
+		http.Error(w, "Barcode required", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.Store.GetBookItemByBarcode(barcode)
+	if err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// CreateItem endpoint (khusus admin).
+// Menambah eksemplar baru; barcode dibuat otomatis jika kosong.
+func (h *BookHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var payload models.BookItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if payload.Condition == "" {
+		payload.Condition = store.ConditionGood
+	}
+	if payload.Status == "" {
+		payload.Status = store.ItemAvailable
+	}
+	if !validItemConditions[payload.Condition] {
+		http.Error(w, "Invalid condition. Must be one of: good, fair, damaged", http.StatusBadRequest)
+		return
+	}
+	if !validItemStatuses[payload.Status] {
+		http.Error(w, "Invalid status. Must be one of: available, maintenance, lost", http.StatusBadRequest)
+		return
+	}
+
+	item := &models.BookItem{
+		BookID:          payload.BookID,
+		Barcode:         payload.Barcode,
+		AccessionNumber: payload.AccessionNumber,
+		Condition:       payload.Condition,
+		ShelfLocation:   payload.ShelfLocation,
+		Status:          payload.Status,
+	}
+	if err := h.Store.CreateBookItem(item); err != nil {
+		writeItemError(w, err)
+		return
+	}
+	if _, err := store.ProcessHoldQueue(h.Store, item.BookID); err != nil {
+		log.Println("Hold queue error:", err)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// UpdateItem endpoint (khusus admin).
+// Memperbarui data eksemplar (?id=). Field kosong tidak diubah.
+func (h *BookHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload models.BookItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.Store.GetBookItemByID(id)
+	if err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	if payload.Barcode != "" {
+		item.Barcode = payload.Barcode
+	}
+	if payload.AccessionNumber != "" {
+		item.AccessionNumber = payload.AccessionNumber
+	}
+	if payload.ShelfLocation != "" {
+		item.ShelfLocation = payload.ShelfLocation
+	}
+	if payload.Condition != "" {
+		if !validItemConditions[payload.Condition] {
+			http.Error(w, "Invalid condition. Must be one of: good, fair, damaged", http.StatusBadRequest)
+			return
+		}
+		item.Condition = payload.Condition
+	}
+	if payload.Status != "" {
+		if !validItemStatuses[payload.Status] {
+			http.Error(w, "Invalid status. Must be one of: available, maintenance, lost", http.StatusBadRequest)
+			return
+		}
+		item.Status = payload.Status
+	}
+
+	if err := h.Store.UpdateBookItem(item); err != nil {
+		writeItemError(w, err)
+		return
+	}
+	if _, err := store.ProcessHoldQueue(h.Store, item.BookID); err != nil {
+		log.Println("Hold queue error:", err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Copy updated"})
+}
+
+// DeleteItem endpoint (khusus admin).
+// Menghapus eksemplar (?id=) yang tidak sedang dipinjam.
+func (h *BookHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.DeleteBookItem(id); err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Copy deleted"})
+}
@@ -8,10 +8,10 @@ import (
 )
 
 type CategoryHandler struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
-func NewCategoryHandler(store *store.MySQLStore) *CategoryHandler {
+func NewCategoryHandler(store store.Store) *CategoryHandler {
 	return &CategoryHandler{Store: store}
 }
 
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"latihan_cloud8/documents"
+	"latihan_cloud8/middleware"
+	"latihan_cloud8/models"
+	"latihan_cloud8/pdf"
+	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type DocumentHandler struct {
+	Store store.Store
+}
+
+func NewDocumentHandler(store store.Store) *DocumentHandler {
+	return &DocumentHandler{Store: store}
+}
+
+// writePDF mengirim dokumen untuk ditampilkan di browser (inline) dengan nama berkas name.pdf.
+// Dokumen disusun di memori dulu agar kegagalan tidak menghasilkan berkas terpotong.
+func writePDF(w http.ResponseWriter, doc *pdf.Document, name string) {
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		log.Println("PDF render error:", err)
+		http.Error(w, "Error rendering document", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, name))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Cache-Control", "no-store")
+	buf.WriteTo(w)
+}
+
+// requester mengambil user yang sedang login; menulis error dan mengembalikan nil jika gagal.
+func (h *DocumentHandler) requester(w http.ResponseWriter, r *http.Request) *models.User {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return nil
+	}
+	return user
+}
+
+// letterhead mengambil kop surat; kop bawaan dipakai jika gagal agar dokumen tetap bisa dicetak.
+func (h *DocumentHandler) letterhead() *models.Letterhead {
+	lh, err := h.Store.GetLetterhead()
+	if err != nil {
+		log.Println("Letterhead error:", err)
+		return store.DefaultLetterhead()
+	}
+	return lh
+}
+
+// loanDocument mengambil pinjaman ?loan_id= beserta data lengkap anggota dan bukunya. Anggota
+// biasa hanya boleh mengakses pinjamannya sendiri.
+func (h *DocumentHandler) loanDocument(w http.ResponseWriter, r *http.Request) (*models.Loan, *models.User, *models.Book, bool) {
+	requester := h.requester(w, r)
+	if requester == nil {
+		return nil, nil, nil, false
+	}
+	loanID, err := strconv.Atoi(r.URL.Query().Get("loan_id"))
+	if err != nil {
+		http.Error(w, "Invalid loan_id", http.StatusBadRequest)
+		return nil, nil, nil, false
+	}
+	loan, err := h.Store.GetLoanByID(loanID)
+	if err == store.ErrLoanNotFound {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return nil, nil, nil, false
+	}
+	if err != nil {
+		http.Error(w, "Error fetching loan", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	if requester.Role != "admin" && loan.UserID != requester.ID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, nil, nil, false
+	}
+
+	user, err := h.Store.GetUserByID(loan.UserID)
+	if err != nil {
+		http.Error(w, "Error fetching member", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	book, err := h.Store.GetBookByID(loan.BookID)
+	if err != nil {
+		http.Error(w, "Error fetching book", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	return loan, user, book, true
+}
+
+// LoanSlip endpoint.
+// Slip peminjaman PDF untuk ?loan_id= (milik sendiri, atau semua untuk admin).
+func (h *DocumentHandler) LoanSlip(w http.ResponseWriter, r *http.Request) {
+	loan, user, book, ok := h.loanDocument(w, r)
+	if !ok {
+		return
+	}
+	policy := store.LoanPolicyFor(h.Store, user.Role, book.Category)
+	doc := documents.RenderLoanSlip(h.letterhead(), documents.LoanSlip{
+		Loan: loan, User: user, Book: book, FinePerDay: policy.FinePerDay,
+	}, time.Now())
+	writePDF(w, doc, store.LoanSlipNo(loan))
+}
+
+// ReturnReceipt endpoint.
+// Bukti pengembalian PDF untuk ?loan_id= yang sudah dikembalikan (409 jika belum).
+func (h *DocumentHandler) ReturnReceipt(w http.ResponseWriter, r *http.Request) {
+	loan, user, book, ok := h.loanDocument(w, r)
+	if !ok {
+		return
+	}
+	if loan.ReturnDate == nil {
+		http.Error(w, "Buku belum dikembalikan", http.StatusConflict)
+		return
+	}
+	balance, err := h.Store.GetFineBalance(user.ID)
+	if err != nil {
+		// Saldo hanya pelengkap; bukti tetap dicetak tanpa sisa tunggakan
+		log.Println("Fine balance error:", err)
+		balance = nil
+	}
+	doc := documents.RenderReturnReceipt(h.letterhead(), documents.ReturnReceipt{
+		Loan: loan, User: user, Book: book, Balance: balance,
+	}, time.Now())
+	writePDF(w, doc, store.ReturnReceiptNo(loan))
+}
+
+// FineReceipt endpoint.
+// Kuitansi pembayaran atau bukti pembebasan denda PDF untuk entri ?id= (milik sendiri, atau
+// semua untuk admin). Entri tagihan tidak memiliki kuitansi.
+func (h *DocumentHandler) FineReceipt(w http.ResponseWriter, r *http.Request) {
+	requester := h.requester(w, r)
+	if requester == nil {
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	entry, err := h.Store.GetFineEntry(id)
+	if err == store.ErrFineNotFound {
+		http.Error(w, "Fine entry not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching fine entry", http.StatusInternalServerError)
+		return
+	}
+	if requester.Role != "admin" && entry.UserID != requester.ID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if entry.Type == store.FineCharge {
+		http.Error(w, "Kuitansi hanya tersedia untuk pembayaran dan pembebasan denda", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Store.GetUserByID(entry.UserID)
+	if err != nil {
+		http.Error(w, "Error fetching member", http.StatusInternalServerError)
+		return
+	}
+	balance, err := h.Store.GetFineBalance(user.ID)
+	if err != nil {
+		log.Println("Fine balance error:", err)
+		balance = nil
+	}
+	doc := documents.RenderFineReceipt(h.letterhead(), documents.FineReceipt{
+		Entry: entry, User: user, Balance: balance,
+	}, time.Now())
+	writePDF(w, doc, store.FineDocumentNo(entry))
+}
+
+// MonthlyReport endpoint (khusus admin).
+// Laporan sirkulasi bulanan PDF untuk ?month=YYYY-MM (bawaan bulan berjalan) dengan kop surat
+// dan kolom tanda tangan kepala perpustakaan.
+func (h *DocumentHandler) MonthlyReport(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if s := r.URL.Query().Get("month"); s != "" {
+		m, err := time.ParseInLocation("2006-01", s, time.Local)
+		if err != nil {
+			http.Error(w, "Format bulan harus YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		start = m
+	}
+	rng := models.ReportRange{Start: start, End: start.AddDate(0, 1, 0)}
+
+	sum, err := buildReportSummary(h.Store, rng, store.ReportDaily, reportDefaultLimit)
+	if err != nil {
+		http.Error(w, "Error building report", http.StatusInternalServerError)
+		return
+	}
+	doc := documents.RenderMonthlyReport(h.letterhead(), sum, now)
+	writePDF(w, doc, "laporan_sirkulasi_"+start.Format("2006-01"))
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"fmt"
+	"latihan_cloud8/catalog"
+	"latihan_cloud8/export"
+	"latihan_cloud8/marc"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"log"
+	"net/http"
+	"time"
+)
+
+type ExportHandler struct {
+	Store store.Store
+}
+
+func NewExportHandler(store store.Store) *ExportHandler {
+	return &ExportHandler{Store: store}
+}
+
+// startExport membaca ?format= (csv atau xlsx, bawaan csv), lalu menyiapkan header unduhan
+// dengan nama berkas name.<format>. Menulis 400 dan mengembalikan false jika format tidak dikenal.
+func startExport(w http.ResponseWriter, r *http.Request, name string) (export.Writer, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	ew, err := export.New(format, w)
+	if err != nil {
+		http.Error(w, "Format harus csv atau xlsx", http.StatusBadRequest)
+		return nil, false
+	}
+	startDownload(w, name, format, export.ContentType(format))
+	return ew, true
+}
+
+// startDownload menyiapkan header unduhan berkas name.<format>. Ekspor besar bisa berjalan
+// lebih lama dari WriteTimeout server, jadi batas waktu tulis untuk request ini dihapus.
+func startDownload(w http.ResponseWriter, name, format, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	w.Header().Set("Cache-Control", "no-store")
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Export %s: write deadline not cleared: %v", name, err)
+	}
+}
+
+// abortExport dipanggil jika ekspor gagal di tengah jalan. Header dan sebagian isi berkas
+// mungkin sudah terkirim, jadi koneksi diputus agar klien tidak menyimpan berkas terpotong
+// seolah-olah utuh.
+func abortExport(name string, err error) {
+	log.Printf("Export %s error: %v", name, err)
+	panic(http.ErrAbortHandler)
+}
+
+// rangeSuffix membentuk akhiran nama berkas dari rentang laporan (End eksklusif).
+func rangeSuffix(rng models.ReportRange) string {
+	return rng.Start.Format(store.DateLayout) + "_" + rng.End.AddDate(0, 0, -1).Format(store.DateLayout)
+}
+
+// loanStatusLabel menerjemahkan status pinjaman untuk berkas ekspor.
+func loanStatusLabel(l *models.Loan, now time.Time) string {
+	switch {
+	case l.ReturnDate != nil || l.Status == store.LoanReturned:
+		return "Dikembalikan"
+	case l.Status == store.LoanLate || l.DueDate.Before(now):
+		return "Terlambat"
+	default:
+		return "Dipinjam"
+	}
+}
+
+// Loans endpoint (khusus admin).
+// Mengunduh riwayat peminjaman dalam rentang ?start_date=&end_date= (bawaan 30 hari terakhir)
+// sebagai CSV atau XLSX (?format=). Baris dibaca dan ditulis satu per satu dari database.
+func (h *ExportHandler) Loans(w http.ResponseWriter, r *http.Request) {
+	rng, ok := reportRange(r)
+	if !ok {
+		http.Error(w, "Format tanggal harus YYYY-MM-DD dan start_date tidak boleh setelah end_date", http.StatusBadRequest)
+		return
+	}
+	ew, ok := startExport(w, r, "peminjaman_"+rangeSuffix(rng))
+	if !ok {
+		return
+	}
+
+	err := ew.Sheet("Peminjaman", "No. Pinjam", "Username", "Nama Peminjam", "Judul Buku", "Kategori", "Barcode",
+		"Tanggal Pinjam", "Jatuh Tempo", "Tanggal Kembali", "Status", "Perpanjangan", "Denda (Rp)")
+	if err != nil {
+		abortExport("loans", err)
+	}
+	now := time.Now()
+	// GetLoansFiltered memakai batas akhir inklusif
+	err = h.Store.EachLoan(rng.Start, rng.End.Add(-time.Nanosecond), func(l *models.Loan) error {
+		var username, fullname, title, category, barcode string
+		if l.User != nil {
+			username, fullname = l.User.Username, l.User.Fullname
+		}
+		if l.Book != nil {
+			title, category = l.Book.Title, l.Book.Category
+		}
+		if l.Item != nil {
+			barcode = l.Item.Barcode
+		}
+		// Pinjaman yang belum kembali memakai denda berjalan
+		fine := l.Fine
+		if store.IsOpenLoan(l.Status) {
+			fine = l.AccruedFine
+		}
+		return ew.Row(l.ID, username, fullname, title, category, barcode,
+			l.LoanDate, export.Date(l.DueDate), l.ReturnDate, loanStatusLabel(l, now), l.Renewals, fine)
+	})
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		abortExport("loans", err)
+	}
+}
+
+// Members endpoint (khusus admin).
+// Mengunduh daftar anggota sebagai CSV atau XLSX (?format=).
+func (h *ExportHandler) Members(w http.ResponseWriter, r *http.Request) {
+	ew, ok := startExport(w, r, "anggota_"+time.Now().Format("20060102"))
+	if !ok {
+		return
+	}
+
+	err := ew.Sheet("Anggota", "Username", "Nama Lengkap", "Role", "NIP/NPM", "Kontak", "Email",
+		"Notifikasi Email", "Tanggal Daftar", "Keanggotaan Berakhir", "Status")
+	if err != nil {
+		abortExport("members", err)
+	}
+	now := time.Now()
+	err = h.Store.EachUser(func(u *models.User) error {
+		status := "Aktif"
+		switch {
+		case u.BlockedReason != "":
+			status = "Diblokir: " + u.BlockedReason
+		case u.MembershipExpiresAt != nil && !now.Before(u.MembershipExpiresAt.AddDate(0, 0, 1)):
+			status = "Keanggotaan berakhir"
+		}
+		var expires any
+		if u.MembershipExpiresAt != nil {
+			expires = export.Date(*u.MembershipExpiresAt)
+		}
+		return ew.Row(u.Username, u.Fullname, u.Role, u.NIP, u.Contact, u.Email,
+			u.EmailNotifications, export.Date(u.CreatedAt), expires, status)
+	})
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		abortExport("members", err)
+	}
+}
+
+// Catalog endpoint (khusus admin).
+// Mengunduh katalog buku sebagai CSV atau XLSX (?format=).
+func (h *ExportHandler) Catalog(w http.ResponseWriter, r *http.Request) {
+	ew, ok := startExport(w, r, "katalog_"+time.Now().Format("20060102"))
+	if !ok {
+		return
+	}
+
+	err := ew.Sheet("Katalog", "ID Buku", "Judul", "Penulis", "Kategori", "Penerbit", "Tahun Terbit", "ISBN", "Stok", "Tanggal Ditambahkan")
+	if err != nil {
+		abortExport("catalog", err)
+	}
+	err = h.Store.EachBook(func(b *models.Book) error {
+		var year any
+		if b.PublishedYear > 0 {
+			year = b.PublishedYear
+		}
+		return ew.Row(b.ID, b.Title, b.Author, b.Category, b.Publisher, year, b.ISBN, b.Stock, export.Date(b.CreatedAt))
+	})
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		abortExport("catalog", err)
+	}
+}
+
+// CatalogMARC endpoint (khusus admin).
+// Mengunduh katalog buku sebagai MARC21 biner atau MARCXML (?format=mrc|xml, bawaan mrc) untuk
+// dipertukarkan dengan Perpusnas atau SLiMS. Pemetaan field lihat catalog.MARCRecord.
+func (h *ExportHandler) CatalogMARC(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = marc.FormatMARC
+	}
+	mw, err := marc.NewWriter(format, w)
+	if err != nil {
+		http.Error(w, "Format harus mrc atau xml", http.StatusBadRequest)
+		return
+	}
+	startDownload(w, "katalog_"+time.Now().Format("20060102"), format, marc.ContentType(format))
+
+	err = h.Store.EachBook(func(b *models.Book) error {
+		return mw.Write(catalog.MARCRecord(b))
+	})
+	if err == nil {
+		err = mw.Close()
+	}
+	if err != nil {
+		abortExport("catalog marc", err)
+	}
+}
+
+// Report endpoint (khusus admin).
+// Mengunduh laporan sirkulasi (parameter sama dengan /api/reports) sebagai CSV atau XLSX;
+// setiap bagian laporan menjadi worksheet tersendiri di XLSX.
+func (h *ExportHandler) Report(w http.ResponseWriter, r *http.Request) {
+	rng, interval, limit, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	// Laporan agregat berukuran kecil; dihitung dulu agar kegagalan query masih bisa
+	// dilaporkan sebagai 500 sebelum berkas mulai dikirim.
+	sum, err := buildReportSummary(h.Store, rng, interval, limit)
+	if err != nil {
+		http.Error(w, "Error building report", http.StatusInternalServerError)
+		return
+	}
+	ew, ok := startExport(w, r, "laporan_"+rangeSuffix(rng))
+	if !ok {
+		return
+	}
+	if err := writeReportSheets(ew, sum); err != nil {
+		abortExport("report", err)
+	}
+}
+
+// writeReportSheets menulis setiap bagian laporan sebagai tabel tersendiri.
+func writeReportSheets(ew export.Writer, sum *models.ReportSummary) error {
+	loans, returned := 0, 0
+	for _, v := range sum.Volume {
+		loans += v.Loans
+		returned += v.Returned
+	}
+	rows := [][]any{
+		{"Dari Tanggal", export.Date(sum.Range.Start)},
+		{"Sampai Tanggal", export.Date(sum.Range.End.AddDate(0, 0, -1))},
+		{"Total Peminjaman", loans},
+		{"Total Pengembalian", returned},
+		{"Denda Ditagihkan (Rp)", sum.Fines.Charged},
+		{"Denda Dibayar (Rp)", sum.Fines.Collected},
+		{"Denda Dibebaskan (Rp)", sum.Fines.Waived},
+		{"Denda Berjalan Saat Ini (Rp)", sum.Fines.Accruing},
+		{"Pinjaman Jatuh Tempo", sum.Overdue.Due},
+		{"Pinjaman Terlambat", sum.Overdue.Overdue},
+		{"Terlambat dan Belum Kembali", sum.Overdue.StillOpen},
+		{"Tingkat Keterlambatan (%)", sum.Overdue.OverdueRate * 100},
+	}
+	if err := ew.Sheet("Ringkasan", "Keterangan", "Nilai"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := ew.Row(row...); err != nil {
+			return err
+		}
+	}
+
+	if err := ew.Sheet("Peminjaman per Periode", "Periode", "Dipinjam", "Dikembalikan",
+		"Denda Ditagihkan (Rp)", "Denda Dibayar (Rp)", "Denda Dibebaskan (Rp)"); err != nil {
+		return err
+	}
+	fines := make(map[string]models.FinePeriod, len(sum.Fines.Periods))
+	for _, p := range sum.Fines.Periods {
+		fines[p.Period] = p
+	}
+	for _, v := range sum.Volume {
+		f := fines[v.Period]
+		if err := ew.Row(v.Period, v.Loans, v.Returned, f.Charged, f.Collected, f.Waived); err != nil {
+			return err
+		}
+	}
+
+	if err := ew.Sheet("Judul Terpopuler", "Peringkat", "Judul", "Penulis", "Kategori", "Jumlah Pinjam"); err != nil {
+		return err
+	}
+	for i, t := range sum.TopTitles {
+		if err := ew.Row(i+1, t.Title, t.Author, t.Category, t.Loans); err != nil {
+			return err
+		}
+	}
+
+	if err := ew.Sheet("Kategori Terpopuler", "Peringkat", "Kategori", "Jumlah Pinjam"); err != nil {
+		return err
+	}
+	for i, c := range sum.TopCategories {
+		if err := ew.Row(i+1, c.Category, c.Loans); err != nil {
+			return err
+		}
+	}
+
+	if err := ew.Sheet("Peminjam Teraktif", "Peringkat", "Username", "Nama Lengkap", "Role", "Jumlah Pinjam", "Denda (Rp)"); err != nil {
+		return err
+	}
+	for i, b := range sum.TopBorrowers {
+		if err := ew.Row(i+1, b.Username, b.Fullname, b.Role, b.Loans, b.Fines); err != nil {
+			return err
+		}
+	}
+
+	if err := ew.Sheet("Per Role", "Role", "Jumlah Peminjam", "Jumlah Pinjam", "Dikembalikan", "Terlambat", "Denda (Rp)"); err != nil {
+		return err
+	}
+	for _, rr := range sum.Roles {
+		if err := ew.Row(rr.Role, rr.Borrowers, rr.Loans, rr.Returned, rr.Overdue, rr.Fines); err != nil {
+			return err
+		}
+	}
+	return ew.Close()
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"latihan_cloud8/middleware"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
+	"net/http"
+	"strings"
+)
+
+type FineHandler struct {
+	Store store.Store
+}
+
+func NewFineHandler(store store.Store) *FineHandler {
+	return &FineHandler{Store: store}
+}
+
+// writeFineError memetakan error store denda ke respon HTTP.
+func writeFineError(w http.ResponseWriter, err error) {
+	switch err {
+	case store.ErrUserNotFound:
+		http.Error(w, "User not found", http.StatusNotFound)
+	case store.ErrLoanNotFound:
+		http.Error(w, "Loan not found for this member", http.StatusNotFound)
+	case store.ErrInvalidAmount:
+		http.Error(w, "Jumlah harus lebih dari 0", http.StatusBadRequest)
+	case store.ErrExceedsBalance:
+		http.Error(w, "Jumlah melebihi sisa denda", http.StatusBadRequest)
+	case store.ErrReceiptExists:
+		http.Error(w, "Nomor kuitansi sudah dipakai", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// fineUserID menentukan anggota yang datanya diminta: admin boleh memilih lewat ?user_id=,
+// anggota biasa selalu dirinya sendiri.
+func (h *FineHandler) fineUserID(r *http.Request) (string, int) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		return "", http.StatusUnauthorized
+	}
+	if claims.Role == "admin" {
+		return r.URL.Query().Get("user_id"), 0
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	return user.ID, 0
+}
+
+// ListFines endpoint.
+// Menampilkan riwayat denda (tagihan, pembayaran, pembebasan). Admin melihat semua anggota
+// atau satu anggota lewat ?user_id=; anggota biasa melihat miliknya sendiri.
+func (h *FineHandler) ListFines(w http.ResponseWriter, r *http.Request) {
+	userID, status := h.fineUserID(r)
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	entries, err := h.Store.GetFineEntries(userID)
+	if err != nil {
+		http.Error(w, "Error fetching fines", http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []models.FineEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// Balance endpoint.
+// Menampilkan saldo denda tertunggak seorang anggota (?user_id= untuk admin).
+func (h *FineHandler) Balance(w http.ResponseWriter, r *http.Request) {
+	userID, status := h.fineUserID(r)
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if userID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	balance, err := h.Store.GetFineBalance(userID)
+	if err != nil {
+		writeFineError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balance)
+}
+
+// Outstanding endpoint (khusus admin).
+// Menampilkan daftar anggota yang masih memiliki denda tertunggak.
+func (h *FineHandler) Outstanding(w http.ResponseWriter, r *http.Request) {
+	balances, err := h.Store.GetOutstandingBalances()
+	if err != nil {
+		http.Error(w, "Error fetching balances", http.StatusInternalServerError)
+		return
+	}
+	if balances == nil {
+		balances = []models.FineBalance{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balances)
+}
+
+// decodeFineRequest membaca payload pembayaran/pembebasan dan admin yang mencatatnya.
+func (h *FineHandler) decodeFineRequest(w http.ResponseWriter, r *http.Request) (*models.FinePaymentRequest, *models.User, bool) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, nil, false
+	}
+	admin, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+
+	var payload models.FinePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	if payload.UserID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return &payload, admin, true
+}
+
+// RecordPayment endpoint (khusus admin).
+// Mencatat pembayaran denda tunai atau transfer, boleh sebagian. Mengembalikan entri
+// pembayaran beserta nomor kuitansi dan sisa saldo.
+func (h *FineHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	payload, admin, ok := h.decodeFineRequest(w, r)
+	if !ok {
+		return
+	}
+	if payload.Method != store.PaymentCash && payload.Method != store.PaymentTransfer {
+		http.Error(w, "Invalid method. Must be one of: cash, transfer", http.StatusBadRequest)
+		return
+	}
+
+	entry := &models.FineEntry{
+		UserID:     payload.UserID,
+		LoanID:     payload.LoanID,
+		Amount:     payload.Amount,
+		Method:     payload.Method,
+		ReceiptNo:  strings.TrimSpace(payload.ReceiptNo),
+		RecordedBy: admin.ID,
+	}
+	if err := h.Store.RecordFinePayment(entry); err != nil {
+		writeFineError(w, err)
+		return
+	}
+	h.respondCredit(w, entry, fmt.Sprintf("Pembayaran denda Rp %d diterima. No. kuitansi: %s", entry.Amount, entry.ReceiptNo))
+}
+
+// WaiveFine endpoint (khusus admin).
+// Membebaskan sebagian atau seluruh denda dengan alasan yang wajib diisi.
+func (h *FineHandler) WaiveFine(w http.ResponseWriter, r *http.Request) {
+	payload, admin, ok := h.decodeFineRequest(w, r)
+	if !ok {
+		return
+	}
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	if payload.Reason == "" {
+		http.Error(w, "Reason required", http.StatusBadRequest)
+		return
+	}
+
+	entry := &models.FineEntry{
+		UserID:     payload.UserID,
+		LoanID:     payload.LoanID,
+		Amount:     payload.Amount,
+		Reason:     payload.Reason,
+		RecordedBy: admin.ID,
+	}
+	if err := h.Store.WaiveFine(entry); err != nil {
+		writeFineError(w, err)
+		return
+	}
+	h.respondCredit(w, entry, fmt.Sprintf("Denda Rp %d dibebaskan. Alasan: %s", entry.Amount, entry.Reason))
+}
+
+// respondCredit mengirim notifikasi ke anggota dan mengembalikan entri beserta saldo terbaru.
+func (h *FineHandler) respondCredit(w http.ResponseWriter, entry *models.FineEntry, msg string) {
+	balance, err := h.Store.GetFineBalance(entry.UserID)
+	if err != nil {
+		writeFineError(w, err)
+		return
+	}
+	h.Store.CreateNotification(entry.UserID, store.NotifyFine, store.SeverityInfo, fmt.Sprintf("%s. Sisa denda: Rp %d", msg, balance.Outstanding))
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entry":   entry,
+		"balance": balance,
+	})
+}
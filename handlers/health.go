@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"latihan_cloud8/health"
+	"latihan_cloud8/store"
+	"net/http"
+	"time"
+)
+
+// readyTimeout membatasi lama pemeriksaan database pada /readyz.
+const readyTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	Store store.Store
+}
+
+func NewHealthHandler(store store.Store) *HealthHandler {
+	return &HealthHandler{Store: store}
+}
+
+// Healthz endpoint (publik, untuk liveness probe).
+// Selalu 200 selama proses masih melayani HTTP.
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Readyz endpoint (publik, untuk readiness probe load balancer).
+// 200 jika database bisa dihubungi, semua migrasi sudah diterapkan dan setiap worker
+// masih berdetak; selain itu 503 beserta hasil tiap pemeriksaan.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	checks := make(map[string]string)
+	ready := true
+	fail := func(name, msg string) {
+		checks[name] = msg
+		ready = false
+	}
+
+	if hc, ok := h.Store.(store.HealthChecker); ok {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		if err := hc.Ping(ctx); err != nil {
+			fail("database", err.Error())
+		} else {
+			checks["database"] = "ok"
+		}
+	} else {
+		checks["database"] = "ok (in-memory)"
+	}
+
+	if m, ok := h.Store.(store.Migrator); ok && checks["database"] == "ok" {
+		pending, err := store.PendingMigrations(m)
+		switch {
+		case err != nil:
+			fail("migrations", err.Error())
+		case pending > 0:
+			fail("migrations", fmt.Sprintf("%d pending migration(s)", pending))
+		default:
+			checks["migrations"] = "ok"
+		}
+	}
+
+	stale := make(map[string]bool)
+	for _, name := range health.Stale(time.Now()) {
+		stale[name] = true
+	}
+	for _, name := range health.Workers() {
+		if stale[name] {
+			fail("worker:"+name, "no heartbeat")
+		} else {
+			checks["worker:"+name] = "ok"
+		}
+	}
+
+	status := "ready"
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		status = "not ready"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "checks": checks})
+}
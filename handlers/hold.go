@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"latihan_cloud8/middleware"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type HoldHandler struct {
+	Store store.Store
+}
+
+func NewHoldHandler(store store.Store) *HoldHandler {
+	return &HoldHandler{Store: store}
+}
+
+// writeHoldError memetakan error store reservasi ke respon HTTP.
+func writeHoldError(w http.ResponseWriter, err error) {
+	switch err {
+	case store.ErrBookNotFound:
+		http.Error(w, "Book not found", http.StatusNotFound)
+	case store.ErrHoldNotFound:
+		http.Error(w, "Hold not found", http.StatusNotFound)
+	case store.ErrHoldExists, store.ErrHoldClosed, store.ErrBookAvailable, store.ErrAlreadyBorrowing:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// PlaceHold endpoint.
+// Menambahkan pengguna yang login ke antrean reservasi buku yang stoknya habis.
+func (h *HoldHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	var payload models.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.Store.PlaceHold(user.ID, payload.BookID)
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+
+	msg := fmt.Sprintf("Reservasi berhasil: %s. Posisi antrean: %d", hold.Book.Title, hold.Position)
+	h.Store.CreateNotification(user.ID, store.NotifyHold, store.SeverityInfo, msg)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// ListHolds endpoint.
+// Admin melihat antrean aktif (semua buku, atau ?book_id= untuk satu buku);
+// pengguna biasa melihat reservasi miliknya sendiri.
+func (h *HoldHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var holds []models.Hold
+	var err error
+	if claims.Role == "admin" {
+		bookID := 0
+		if s := r.URL.Query().Get("book_id"); s != "" {
+			if bookID, err = strconv.Atoi(s); err != nil {
+				http.Error(w, "Invalid book ID", http.StatusBadRequest)
+				return
+			}
+		}
+		holds, err = h.Store.GetActiveHolds(bookID)
+	} else {
+		user, uErr := h.Store.GetByUsername(claims.Username)
+		if uErr != nil {
+			http.Error(w, "User error", http.StatusInternalServerError)
+			return
+		}
+		holds, err = h.Store.GetHoldsByUser(user.ID)
+	}
+	if err != nil {
+		http.Error(w, "Error fetching holds", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holds)
+}
+
+// CancelHold endpoint.
+// Membatalkan reservasi (?id=). Pengguna hanya dapat membatalkan miliknya sendiri, admin semua.
+// Eksemplar yang sudah disisihkan langsung diteruskan ke antrean berikutnya.
+func (h *HoldHandler) CancelHold(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.Store.GetHoldByID(id)
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+	if claims.Role != "admin" {
+		user, uErr := h.Store.GetByUsername(claims.Username)
+		if uErr != nil || user.ID != hold.UserID {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
+	hold, err = h.Store.CancelHold(id)
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+	if claims.Role == "admin" {
+		msg := fmt.Sprintf("Reservasi buku '%s' dibatalkan oleh petugas perpustakaan.", hold.Book.Title)
+		h.Store.CreateNotification(hold.UserID, store.NotifyHold, store.SeverityWarning, msg)
+	}
+	if _, err := store.ProcessHoldQueue(h.Store, hold.BookID); err != nil {
+		log.Println("Hold queue error:", err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Hold cancelled"})
+}
+
+// ExpireHolds endpoint (khusus admin).
+// Menjalankan pemeriksaan batas pengambilan sekarang juga tanpa menunggu worker.
+func (h *HoldHandler) ExpireHolds(w http.ResponseWriter, r *http.Request) {
+	expired, err := store.ExpireHoldQueue(h.Store, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"expired": len(expired)})
+}
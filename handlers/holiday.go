@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HolidayHandler struct {
+	Store store.Store
+}
+
+func NewHolidayHandler(store store.Store) *HolidayHandler {
+	return &HolidayHandler{Store: store}
+}
+
+// GetHolidays endpoint (khusus admin).
+// Mengambil daftar tanggal libur perpustakaan.
+func (h *HolidayHandler) GetHolidays(w http.ResponseWriter, r *http.Request) {
+	holidays, err := h.Store.GetHolidays()
+	if err != nil {
+		http.Error(w, "Error fetching holidays", http.StatusInternalServerError)
+		return
+	}
+	if holidays == nil {
+		holidays = []models.Holiday{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holidays)
+}
+
+// CreateHoliday endpoint (khusus admin).
+// Menambahkan satu tanggal libur (format YYYY-MM-DD).
+func (h *HolidayHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
+	var payload models.Holiday
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if _, err := time.Parse(store.DateLayout, payload.Date); err != nil {
+		http.Error(w, "Invalid date, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	payload.Name = strings.TrimSpace(payload.Name)
+	if payload.Name == "" {
+		http.Error(w, "Name required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.CreateHoliday(&payload); err != nil {
+		if err == store.ErrHolidayExists {
+			http.Error(w, "Tanggal libur sudah terdaftar", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// DeleteHoliday endpoint (khusus admin).
+// Menghapus tanggal libur (?id=).
+func (h *HolidayHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.DeleteHoliday(id); err != nil {
+		if err == store.ErrHolidayNotFound {
+			http.Error(w, "Holiday not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Holiday deleted"})
+}
+
+// ImportHolidays endpoint (khusus admin).
+// Mengimpor tanggal libur dari file iCalendar (.ics) pada field form "file".
+// Tanggal yang sudah terdaftar dilewati.
+func (h *HolidayHandler) ImportHolidays(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(2 << 20); err != nil { // 2MB limit
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "File required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	holidays, err := utils.ParseICalHolidays(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	added, err := h.Store.ImportHolidays(holidays)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"found": len(holidays), "imported": added})
+}
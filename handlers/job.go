@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/middleware"
+	"latihan_cloud8/models"
+	"latihan_cloud8/scheduler"
+	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
+	"net/http"
+)
+
+// jobRunListLimit membatasi jumlah riwayat run yang ditampilkan.
+const jobRunListLimit = 100
+
+type JobHandler struct {
+	Scheduler *scheduler.Scheduler
+}
+
+func NewJobHandler(sched *scheduler.Scheduler) *JobHandler {
+	return &JobHandler{Scheduler: sched}
+}
+
+// GetJobs endpoint (khusus admin).
+// Menampilkan job terjadwal beserta jadwal, waktu run berikutnya dan hasil run terakhir.
+func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.Scheduler.Jobs()
+	if err != nil {
+		http.Error(w, "Error fetching jobs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// GetJobRuns endpoint (khusus admin).
+// Menampilkan riwayat run terbaru, bisa difilter ?name=.
+func (h *JobHandler) GetJobRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.Scheduler.Store.GetJobRuns(r.URL.Query().Get("name"), jobRunListLimit)
+	if err != nil {
+		http.Error(w, "Error fetching job runs", http.StatusInternalServerError)
+		return
+	}
+	if runs == nil {
+		runs = []models.JobRun{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// RunJob endpoint (khusus admin).
+// Menjalankan job secara manual di latar belakang (?name=); hasilnya muncul di riwayat run.
+func (h *JobHandler) RunJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.Scheduler.Trigger(r.URL.Query().Get("name"), claims.Username); err != nil {
+		switch err {
+		case scheduler.ErrJobNotFound:
+			http.Error(w, "Job not found", http.StatusNotFound)
+		case scheduler.ErrJobRunning:
+			http.Error(w, "Job sedang berjalan", http.StatusConflict)
+		case store.ErrJobClaimed:
+			http.Error(w, "Job baru saja dijalankan, coba lagi sebentar", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Job dijalankan"})
+}
@@ -3,19 +3,23 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"latihan_cloud8/metrics"
 	"latihan_cloud8/middleware"
 	"latihan_cloud8/models"
 	"latihan_cloud8/store"
 	"latihan_cloud8/utils"
+	"latihan_cloud8/webhook"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 type LoanHandler struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
-func NewLoanHandler(store *store.MySQLStore) *LoanHandler {
+func NewLoanHandler(store store.Store) *LoanHandler {
 	return &LoanHandler{Store: store}
 }
 
@@ -34,13 +38,48 @@ func (h *LoanHandler) Borrow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Cek batasan jumlah pinjaman
-	settings, err := h.Store.GetSettings()
+	var payload models.LoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Tolak peminjaman jika anggota terkena blokir (terlambat, denda, keanggotaan, manual)
+	eligibility, err := store.CheckEligibility(h.Store, user, time.Now())
+	if err != nil {
+		http.Error(w, "Error checking eligibility", http.StatusInternalServerError)
+		return
+	}
+	if !eligibility.Eligible {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":   "Peminjaman diblokir",
+			"reasons": eligibility.Reasons,
+		})
+		return
+	}
+
+	// Tentukan buku yang dipinjam agar kebijakan per kategori dapat dipilih
+	var item *models.BookItem
+	if payload.Barcode != "" {
+		item, err = h.Store.GetBookItemByBarcode(payload.Barcode)
+		if err != nil {
+			http.Error(w, "Copy not found", http.StatusNotFound)
+			return
+		}
+		payload.BookID = item.BookID
+	}
+	book, err := h.Store.GetBookByID(payload.BookID)
 	if err != nil {
-		// Gunakan default jika setting gagal dimuat
-		settings = &models.Settings{MaxLoanBooks: 3}
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
 	}
 
+	// Kebijakan peminjaman sesuai role peminjam dan kategori buku
+	policy := store.LoanPolicyFor(h.Store, user.Role, book.Category)
+
+	// Cek batasan jumlah pinjaman
 	activeLoans, err := h.Store.GetLoansByUserID(user.ID)
 	if err != nil {
 		http.Error(w, "Error checking loans", http.StatusInternalServerError)
@@ -49,34 +88,34 @@ func (h *LoanHandler) Borrow(w http.ResponseWriter, r *http.Request) {
 
 	activeCount := 0
 	for _, l := range activeLoans {
-		if l.Status == "borrowed" {
+		if store.IsOpenLoan(l.Status) {
 			activeCount++
 		}
 	}
 
-	if activeCount >= settings.MaxLoanBooks {
-		http.Error(w, fmt.Sprintf("Batas maksimal peminjaman tercapai (%d buku)", settings.MaxLoanBooks), http.StatusBadRequest)
-		return
-	}
-
-	var payload models.LoanRequest
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "Invalid payload", http.StatusBadRequest)
+	if activeCount >= policy.MaxLoanBooks {
+		http.Error(w, fmt.Sprintf("Batas maksimal peminjaman tercapai (%d buku)", policy.MaxLoanBooks), http.StatusBadRequest)
 		return
 	}
 
 	// Validasi durasi pinjaman
 	duration := payload.Duration
 	if duration <= 0 {
-		duration = settings.LoanDuration // Default dari setting
+		duration = policy.LoanDuration // Default dari kebijakan
 	}
 	// Pastikan durasi tidak melebihi batas maksimal
-	if duration > settings.LoanDuration {
-		http.Error(w, fmt.Sprintf("Durasi maksimal peminjaman adalah %d hari", settings.LoanDuration), http.StatusBadRequest)
+	if duration > policy.LoanDuration {
+		http.Error(w, fmt.Sprintf("Durasi maksimal peminjaman adalah %d hari", policy.LoanDuration), http.StatusBadRequest)
 		return
 	}
 
-	loan, err := h.Store.BorrowBook(user.ID, payload.BookID, duration)
+	// Pinjam eksemplar tertentu jika barcode dikirim, selain itu pilih eksemplar tersedia
+	var loan *models.Loan
+	if item != nil {
+		loan, err = h.Store.BorrowItem(user.ID, item.ID, duration)
+	} else {
+		loan, err = h.Store.BorrowBook(user.ID, payload.BookID, duration)
+	}
 	if err == store.ErrBookNotFound {
 		http.Error(w, "Book not found", http.StatusNotFound)
 		return
@@ -85,20 +124,28 @@ func (h *LoanHandler) Borrow(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Out of stock", http.StatusBadRequest)
 		return
 	}
+	if err == store.ErrItemNotFound {
+		http.Error(w, "Copy not found", http.StatusNotFound)
+		return
+	}
+	if err == store.ErrItemUnavailable {
+		http.Error(w, "Copy not available", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// Eksemplar reservasi yang tidak jadi dipakai diteruskan ke antrean berikutnya
+	if _, err := store.ProcessHoldQueue(h.Store, loan.BookID); err != nil {
+		log.Println("Hold queue error:", err)
+	}
 
 	// Buat notifikasi peminjaman
-	// Ambil judul buku
-	book, _ := h.Store.GetBookByID(payload.BookID)
-	title := "Buku"
-	if book != nil {
-		title = book.Title
-	}
-	msg := fmt.Sprintf("Peminjaman berhasil: %s. Batas waktu: %s", title, loan.DueDate.Format("02 Jan 2006"))
-	h.Store.CreateNotification(user.ID, msg)
+	msg := fmt.Sprintf("Peminjaman berhasil: %s. Batas waktu: %s", book.Title, loan.DueDate.Format("02 Jan 2006"))
+	h.Store.CreateNotification(user.ID, store.NotifyLoan, store.SeverityInfo, msg)
+	metrics.LoansCreated.Inc()
+	webhook.EmitLoan(h.Store, webhook.EventLoanCreated, loan.ID)
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(loan)
@@ -130,10 +177,25 @@ func (h *LoanHandler) Return(w http.ResponseWriter, r *http.Request) {
 		title = book.Title
 	}
 	msg := fmt.Sprintf("Pengembalian berhasil: %s. Denda: Rp %d", title, loan.Fine)
-	h.Store.CreateNotification(loan.UserID, msg)
+	severity := store.SeverityInfo
+	if loan.Fine > 0 {
+		severity = store.SeverityWarning
+	}
+	h.Store.CreateNotification(loan.UserID, store.NotifyLoan, severity, msg)
+	metrics.LoansReturned.Inc()
+	webhook.EmitLoan(h.Store, webhook.EventLoanReturned, loan.ID)
+
+	// Eksemplar yang kembali disisihkan untuk antrean reservasi terdepan
+	if _, err := store.ProcessHoldQueue(h.Store, loan.BookID); err != nil {
+		log.Println("Hold queue error:", err)
+	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Book returned successfully"})
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":     "Book returned successfully",
+		"receipt_no":  store.ReturnReceiptNo(loan),
+		"receipt_url": fmt.Sprintf("/api/loans/receipt?loan_id=%d", loan.ID),
+	})
 }
 
 // ListLoans endpoint.
@@ -186,3 +248,134 @@ func (h *LoanHandler) ListLoans(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(loans)
 }
+
+// Renew endpoint.
+// Memperpanjang jatuh tempo peminjaman sebanyak durasi pinjam pada kebijakan peminjam.
+// Anggota hanya dapat memperpanjang pinjamannya sendiri, admin semua pinjaman.
+func (h *LoanHandler) Renew(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	var payload models.RenewRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.Store.GetLoanByID(payload.LoanID)
+	if err == store.ErrLoanNotFound {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if claims.Role != "admin" && loan.UserID != user.ID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Kebijakan mengikuti role pemilik pinjaman, bukan admin yang memperpanjang
+	policy := store.LoanPolicyFor(h.Store, loan.User.Role, loan.Book.Category)
+
+	loan, err = h.Store.RenewLoan(loan.ID, user.ID, policy.LoanDuration, policy.MaxRenewals)
+	switch err {
+	case nil:
+	case store.ErrLoanNotFound:
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	case store.ErrAlreadyReturned:
+		http.Error(w, "Peminjaman sudah dikembalikan", http.StatusBadRequest)
+		return
+	case store.ErrLoanOverdue:
+		http.Error(w, "Peminjaman sudah terlambat, tidak dapat diperpanjang", http.StatusBadRequest)
+		return
+	case store.ErrRenewalLimit:
+		http.Error(w, fmt.Sprintf("Batas perpanjangan tercapai (%d kali)", policy.MaxRenewals), http.StatusBadRequest)
+		return
+	case store.ErrHoldsPending:
+		http.Error(w, "Buku ini sedang direservasi anggota lain, tidak dapat diperpanjang", http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	msg := fmt.Sprintf("Perpanjangan berhasil: %s. Batas waktu baru: %s", loan.Book.Title, loan.DueDate.Format("02 Jan 2006"))
+	h.Store.CreateNotification(loan.UserID, store.NotifyLoan, store.SeverityInfo, msg)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loan)
+}
+
+// Renewals endpoint.
+// Menampilkan riwayat perpanjangan sebuah peminjaman (?loan_id=).
+func (h *LoanHandler) Renewals(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	loanID, err := strconv.Atoi(r.URL.Query().Get("loan_id"))
+	if err != nil {
+		http.Error(w, "Invalid loan ID", http.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.Store.GetLoanByID(loanID)
+	if err != nil {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	}
+	if claims.Role != "admin" && loan.User.Username != claims.Username {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	renewals, err := h.Store.GetLoanRenewals(loanID)
+	if err != nil {
+		http.Error(w, "Error fetching renewals", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(renewals)
+}
+
+// Eligibility endpoint.
+// Memeriksa apakah pengguna boleh meminjam beserta daftar alasan blokirnya.
+// Admin dapat memeriksa anggota lain dengan ?username=.
+func (h *LoanHandler) Eligibility(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	username := claims.Username
+	if q := r.URL.Query().Get("username"); q != "" && claims.Role == "admin" {
+		username = q
+	}
+	user, err := h.Store.GetByUsername(username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	eligibility, err := store.CheckEligibility(h.Store, user, time.Now())
+	if err != nil {
+		http.Error(w, "Error checking eligibility", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(eligibility)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type LoanPolicyHandler struct {
+	Store store.Store
+}
+
+func NewLoanPolicyHandler(store store.Store) *LoanPolicyHandler {
+	return &LoanPolicyHandler{Store: store}
+}
+
+// validatePolicy memeriksa isi kebijakan dan mengembalikan pesan error (kosong jika valid).
+func (h *LoanPolicyHandler) validatePolicy(p *models.LoanPolicy) string {
+	validRoles := map[string]bool{
+		"admin":     true,
+		"mahasiswa": true,
+		"guru":      true,
+		"karyawan":  true,
+	}
+	if !validRoles[p.Role] {
+		return "Invalid role. Must be one of: admin, mahasiswa, guru, karyawan"
+	}
+
+	p.Category = strings.TrimSpace(p.Category)
+	if p.Category != "" {
+		cats, err := h.Store.GetAllCategories()
+		if err != nil {
+			return "Error checking categories"
+		}
+		found := false
+		for _, c := range cats {
+			if strings.EqualFold(c.Name, p.Category) {
+				p.Category = c.Name
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "Category not found"
+		}
+	}
+
+	if p.MaxLoanBooks < 1 || p.LoanDuration < 1 {
+		return "max_loan_books dan loan_duration minimal 1"
+	}
+	if p.FinePerDay < 0 || p.MaxRenewals < 0 {
+		return "fine_per_day dan max_renewals tidak boleh negatif"
+	}
+	return ""
+}
+
+// writePolicyError memetakan error store kebijakan ke respon HTTP.
+func writePolicyError(w http.ResponseWriter, err error) {
+	switch err {
+	case store.ErrPolicyNotFound:
+		http.Error(w, "Policy not found", http.StatusNotFound)
+	case store.ErrPolicyExists:
+		http.Error(w, "Kebijakan untuk role dan kategori ini sudah ada", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetPolicies endpoint (khusus admin).
+// Mengambil daftar kebijakan peminjaman beserta pengaturan global sebagai cadangan.
+func (h *LoanPolicyHandler) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.Store.GetLoanPolicies()
+	if err != nil {
+		http.Error(w, "Error fetching policies", http.StatusInternalServerError)
+		return
+	}
+	if policies == nil {
+		policies = []models.LoanPolicy{}
+	}
+	settings, err := h.Store.GetSettings()
+	if err != nil {
+		settings = store.DefaultSettings()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"policies": policies,
+		"default":  store.ResolveLoanPolicy(nil, settings, "", ""),
+	})
+}
+
+// CreatePolicy endpoint (khusus admin).
+// Menambahkan kebijakan untuk role (dan opsional kategori) tertentu.
+func (h *LoanPolicyHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	var p models.LoanPolicy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if msg := h.validatePolicy(&p); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.CreateLoanPolicy(&p); err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(p)
+}
+
+// UpdatePolicy endpoint (khusus admin).
+// Memperbarui kebijakan (?id=).
+func (h *LoanPolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var p models.LoanPolicy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	p.ID = id
+	if msg := h.validatePolicy(&p); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.UpdateLoanPolicy(&p); err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(p)
+}
+
+// DeletePolicy endpoint (khusus admin).
+// Menghapus kebijakan (?id=); role tersebut kembali memakai pengaturan global.
+func (h *LoanPolicyHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.DeleteLoanPolicy(id); err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Policy deleted"})
+}
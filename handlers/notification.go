@@ -2,24 +2,30 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"latihan_cloud8/middleware"
+	"latihan_cloud8/models"
 	"latihan_cloud8/store"
 	"latihan_cloud8/utils"
 )
 
 type NotificationHandler struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
-func NewNotificationHandler(store *store.MySQLStore) *NotificationHandler {
+func NewNotificationHandler(store store.Store) *NotificationHandler {
 	return &NotificationHandler{Store: store}
 }
 
 // GetNotifications endpoint.
 // Mengambil daftar notifikasi untuk pengguna yang login.
+// Filter opsional: ?category= dan ?read=true|false.
 func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
 	v := r.Context().Value(middleware.UserCtxKey)
 	claims := v.(*utils.Claims)
@@ -30,7 +36,22 @@ func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	notifs, err := h.Store.GetNotifications(user.ID)
+	var filter models.NotificationFilter
+	q := r.URL.Query()
+	if filter.Category = q.Get("category"); filter.Category != "" && !store.IsNotificationCategory(filter.Category) {
+		http.Error(w, "Invalid category", http.StatusBadRequest)
+		return
+	}
+	if v := q.Get("read"); v != "" {
+		read, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "Invalid read filter", http.StatusBadRequest)
+			return
+		}
+		filter.IsRead = &read
+	}
+
+	notifs, err := h.Store.GetNotifications(user.ID, filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -40,6 +61,168 @@ func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Re
 	json.NewEncoder(w).Encode(notifs)
 }
 
+// Preferences endpoint.
+// GET mengambil preferensi kategori notifikasi pengguna yang login, PUT menyimpannya.
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var prefs []models.NotificationPreference
+		if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+			http.Error(w, "Invalid payload", http.StatusBadRequest)
+			return
+		}
+		for _, p := range prefs {
+			if !store.IsNotificationCategory(p.Category) {
+				http.Error(w, "Kategori tidak dikenal: "+p.Category, http.StatusBadRequest)
+				return
+			}
+			if !store.FindNotificationPreference(store.NotificationCategories, p.Category).Mutable && (!p.Enabled || !p.Email) {
+				http.Error(w, "Kategori "+p.Category+" tidak dapat dimatikan", http.StatusBadRequest)
+				return
+			}
+		}
+		if err := h.Store.SetNotificationPreferences(user.ID, prefs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prefs, err := h.Store.GetNotificationPreferences(user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// Socket endpoint.
+// Membuka koneksi WebSocket untuk menerima notifikasi baru secara real-time.
+// Setiap tab membuka koneksinya sendiri; klien cukup memuat ulang daftar saat pesan masuk.
+func (h *NotificationHandler) Socket(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	if err := utils.NotificationHub.ServeWS(w, r, user.ID); err != nil {
+		log.Println("WebSocket upgrade error:", err)
+	}
+}
+
+// sseKeepAlive adalah interval komentar kosong agar proxy tidak memutus stream yang diam.
+const sseKeepAlive = 25 * time.Second
+
+// Stream endpoint.
+// Server-Sent Events sebagai cadangan untuk jaringan yang memblokir WebSocket.
+// ID event adalah ID notifikasi, sehingga saat browser tersambung ulang dengan header
+// Last-Event-ID notifikasi yang terlewat dikirim lebih dulu.
+func (h *NotificationHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	lastID := 0
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		if lastID, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Stream berumur panjang, WriteTimeout server tidak berlaku untuk request ini
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Berlangganan sebelum membaca notifikasi yang terlewat agar tidak ada yang hilang di antaranya
+	client := utils.NotificationHub.Subscribe(user.ID)
+	defer utils.NotificationHub.Unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Nonaktifkan buffering nginx
+	fmt.Fprint(w, "retry: 5000\n\n")
+
+	if lastID > 0 {
+		missed, err := h.Store.GetNotificationsSince(user.ID, lastID)
+		if err != nil {
+			log.Println("SSE backlog error:", err)
+			return
+		}
+		for _, n := range missed {
+			writeSSE(w, n)
+			lastID = n.ID
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-client.Send:
+			if !ok {
+				return // Diputus hub karena terlalu lambat, browser akan menyambung ulang
+			}
+			var push struct {
+				Notification models.Notification `json:"notification"`
+			}
+			if err := json.Unmarshal(msg, &push); err != nil || push.Notification.ID <= lastID {
+				continue
+			}
+			writeSSE(w, push.Notification)
+			lastID = push.Notification.ID
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSE menulis satu notifikasi sebagai event "notification" dengan ID notifikasi.
+func writeSSE(w io.Writer, n models.Notification) {
+	data, _ := json.Marshal(n)
+	fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", n.ID, data)
+}
+
 // MarkRead endpoint.
 // Menandai notifikasi sebagai sudah dibaca.
 func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
@@ -78,8 +261,9 @@ func (h *NotificationHandler) DeleteNotification(w http.ResponseWriter, r *http.
 // Mengirim notifikasi ke user tertentu atau semua user (broadcast).
 func (h *NotificationHandler) SendNotification(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
-		UserID  string `json:"user_id"` // "all" untuk broadcast
-		Message string `json:"message"`
+		UserID   string `json:"user_id"` // "all" untuk broadcast
+		Message  string `json:"message"`
+		Severity string `json:"severity"` // Opsional, default "info"
 	}
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		http.Error(w, "Invalid payload", http.StatusBadRequest)
@@ -90,15 +274,23 @@ func (h *NotificationHandler) SendNotification(w http.ResponseWriter, r *http.Re
 		http.Error(w, "Message required", http.StatusBadRequest)
 		return
 	}
+	if payload.Severity == "" {
+		payload.Severity = store.SeverityInfo
+	}
+	if !store.IsNotificationSeverity(payload.Severity) {
+		http.Error(w, "Invalid severity", http.StatusBadRequest)
+		return
+	}
 
+	// Pesan dari admin masuk kategori pengumuman, sehingga bisa dimatikan anggota
 	if payload.UserID == "all" {
 		// Logika broadcast
 		users, _ := h.Store.GetAllUsers()
 		for _, u := range users {
-			h.Store.CreateNotification(u.ID, payload.Message)
+			h.Store.CreateNotification(u.ID, store.NotifyBroadcast, payload.Severity, payload.Message)
 		}
 	} else {
-		h.Store.CreateNotification(payload.UserID, payload.Message)
+		h.Store.CreateNotification(payload.UserID, store.NotifyBroadcast, payload.Severity, payload.Message)
 	}
 
 	w.WriteHeader(http.StatusCreated)
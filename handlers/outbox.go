@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"net/http"
+	"strconv"
+)
+
+// outboxListLimit membatasi jumlah pesan yang ditampilkan di daftar outbox.
+const outboxListLimit = 100
+
+type OutboxHandler struct {
+	Store store.Store
+}
+
+func NewOutboxHandler(store store.Store) *OutboxHandler {
+	return &OutboxHandler{Store: store}
+}
+
+// GetOutbox endpoint (khusus admin).
+// Menampilkan antrean pesan notifikasi keluar (email) terbaru, bisa difilter ?status=pending|sending|sent|failed.
+func (h *OutboxHandler) GetOutbox(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", store.OutboxPending, store.OutboxSending, store.OutboxSent, store.OutboxFailed:
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	msgs, err := h.Store.GetOutbox(status, outboxListLimit)
+	if err != nil {
+		http.Error(w, "Error fetching outbox", http.StatusInternalServerError)
+		return
+	}
+	if msgs == nil {
+		msgs = []models.OutboxMessage{}
+	}
+	// Versi HTML tidak perlu ditampilkan di daftar
+	for i := range msgs {
+		msgs[i].BodyHTML = ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msgs)
+}
+
+// RetryOutbox endpoint (khusus admin).
+// Menjadwalkan ulang pesan yang gagal atau masih menunggu agar segera dikirim (?id=).
+func (h *OutboxHandler) RetryOutbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.RetryOutbox(id); err != nil {
+		switch err {
+		case store.ErrOutboxNotFound:
+			http.Error(w, "Outbox message not found", http.StatusNotFound)
+		case store.ErrOutboxSent:
+			http.Error(w, "Pesan sudah terkirim", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Pesan dijadwalkan ulang"})
+}
@@ -11,10 +11,10 @@ import (
 )
 
 type PageHandler struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
-func NewPageHandler(s *store.MySQLStore) *PageHandler {
+func NewPageHandler(s store.Store) *PageHandler {
 	return &PageHandler{Store: s}
 }
 
@@ -151,6 +151,12 @@ func (h *PageHandler) ShowMyLoans(w http.ResponseWriter, r *http.Request) {
 	render(w, r, "member_loans.html", title, "loans")
 }
 
+// ShowMyFines handler.
+// Menampilkan saldo dan riwayat denda anggota.
+func (h *PageHandler) ShowMyFines(w http.ResponseWriter, r *http.Request) {
+	render(w, r, "member_fines.html", "Denda Saya", "fines")
+}
+
 // ShowProfile handler.
 // Menampilkan halaman profil pengguna.
 func (h *PageHandler) ShowProfile(w http.ResponseWriter, r *http.Request) {
@@ -184,3 +190,21 @@ func (h *PageHandler) ShowProfile(w http.ResponseWriter, r *http.Request) {
 func (h *PageHandler) ShowAdminReports(w http.ResponseWriter, r *http.Request) {
 	render(w, r, "admin_reports.html", "Laporan & Statistik", "reports")
 }
+
+// ShowAdminPolicies handler.
+// Menampilkan halaman kebijakan peminjaman per role (admin).
+func (h *PageHandler) ShowAdminPolicies(w http.ResponseWriter, r *http.Request) {
+	render(w, r, "admin_policies.html", "Kebijakan Peminjaman", "policies")
+}
+
+// ShowAdminSettings handler.
+// Menampilkan halaman pengaturan perpustakaan beserta riwayat perubahannya (admin).
+func (h *PageHandler) ShowAdminSettings(w http.ResponseWriter, r *http.Request) {
+	render(w, r, "admin_settings.html", "Pengaturan", "settings")
+}
+
+// ShowAdminJobs handler.
+// Menampilkan halaman job terjadwal beserta riwayat run-nya (admin).
+func (h *PageHandler) ShowAdminJobs(w http.ResponseWriter, r *http.Request) {
+	render(w, r, "admin_jobs.html", "Job Terjadwal", "jobs")
+}
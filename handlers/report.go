@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Batas bawaan laporan.
+const (
+	reportDefaultDays  = 30 // Rentang bawaan jika start_date/end_date tidak diisi
+	reportDefaultLimit = 10
+	reportMaxLimit     = 100
+)
+
+type ReportHandler struct {
+	Store store.Store
+}
+
+func NewReportHandler(store store.Store) *ReportHandler {
+	return &ReportHandler{Store: store}
+}
+
+// reportRange membaca ?start_date= dan ?end_date= (YYYY-MM-DD, keduanya inklusif, zona waktu
+// server). Bawaannya reportDefaultDays hari terakhir termasuk hari ini.
+func reportRange(r *http.Request) (models.ReportRange, bool) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	start := today.AddDate(0, 0, 1-reportDefaultDays)
+	end := today
+
+	var err error
+	if s := r.URL.Query().Get("start_date"); s != "" {
+		if start, err = time.ParseInLocation(store.DateLayout, s, time.Local); err != nil {
+			return models.ReportRange{}, false
+		}
+	}
+	if s := r.URL.Query().Get("end_date"); s != "" {
+		if end, err = time.ParseInLocation(store.DateLayout, s, time.Local); err != nil {
+			return models.ReportRange{}, false
+		}
+	}
+	if end.Before(start) {
+		return models.ReportRange{}, false
+	}
+	return models.ReportRange{Start: start, End: end.AddDate(0, 0, 1)}, true
+}
+
+// reportInterval membaca ?interval= (day, week, month); bawaannya harian.
+func reportInterval(r *http.Request) (string, bool) {
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		return store.ReportDaily, true
+	}
+	return interval, store.IsReportInterval(interval)
+}
+
+// reportLimit membaca ?limit= untuk daftar peringkat, dibatasi reportMaxLimit.
+func reportLimit(r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("limit")
+	if s == "" {
+		return reportDefaultLimit, true
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 {
+		return 0, false
+	}
+	if limit > reportMaxLimit {
+		limit = reportMaxLimit
+	}
+	return limit, true
+}
+
+// reportParams membaca parameter laporan dan menulis 400 jika ada yang tidak valid.
+func reportParams(w http.ResponseWriter, r *http.Request) (rng models.ReportRange, interval string, limit int, ok bool) {
+	if rng, ok = reportRange(r); !ok {
+		http.Error(w, "Format tanggal harus YYYY-MM-DD dan start_date tidak boleh setelah end_date", http.StatusBadRequest)
+		return
+	}
+	if interval, ok = reportInterval(r); !ok {
+		http.Error(w, "Interval harus day, week atau month", http.StatusBadRequest)
+		return
+	}
+	if limit, ok = reportLimit(r); !ok {
+		http.Error(w, "Limit harus berupa angka positif", http.StatusBadRequest)
+		return
+	}
+	return rng, interval, limit, true
+}
+
+func writeReport(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		http.Error(w, "Error building report", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// LoanVolume endpoint (khusus admin).
+// Jumlah peminjaman dan pengembalian per periode (?interval=day|week|month).
+func (h *ReportHandler) LoanVolume(w http.ResponseWriter, r *http.Request) {
+	rng, interval, _, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	volume, err := h.Store.GetLoanVolume(rng, interval)
+	writeReport(w, volume, err)
+}
+
+// TopTitles endpoint (khusus admin).
+// Judul yang paling sering dipinjam (?limit=).
+func (h *ReportHandler) TopTitles(w http.ResponseWriter, r *http.Request) {
+	rng, _, limit, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	titles, err := h.Store.GetTopTitles(rng, limit)
+	writeReport(w, titles, err)
+}
+
+// TopCategories endpoint (khusus admin).
+// Kategori yang paling sering dipinjam (?limit=).
+func (h *ReportHandler) TopCategories(w http.ResponseWriter, r *http.Request) {
+	rng, _, limit, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	categories, err := h.Store.GetTopCategories(rng, limit)
+	writeReport(w, categories, err)
+}
+
+// TopBorrowers endpoint (khusus admin).
+// Anggota dengan peminjaman terbanyak (?limit=).
+func (h *ReportHandler) TopBorrowers(w http.ResponseWriter, r *http.Request) {
+	rng, _, limit, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	borrowers, err := h.Store.GetTopBorrowers(rng, limit)
+	writeReport(w, borrowers, err)
+}
+
+// Fines endpoint (khusus admin).
+// Denda ditagihkan, dibayar dan dibebaskan per periode beserta denda berjalan saat ini.
+func (h *ReportHandler) Fines(w http.ResponseWriter, r *http.Request) {
+	rng, interval, _, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	report, err := h.Store.GetFineReport(rng, interval)
+	writeReport(w, report, err)
+}
+
+// Overdue endpoint (khusus admin).
+// Tingkat keterlambatan pinjaman yang jatuh tempo dalam rentang.
+func (h *ReportHandler) Overdue(w http.ResponseWriter, r *http.Request) {
+	rng, _, _, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	report, err := h.Store.GetOverdueReport(rng, time.Now())
+	writeReport(w, report, err)
+}
+
+// Roles endpoint (khusus admin).
+// Ringkasan peminjaman per role anggota.
+func (h *ReportHandler) Roles(w http.ResponseWriter, r *http.Request) {
+	rng, _, _, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	roles, err := h.Store.GetRoleReport(rng, time.Now())
+	writeReport(w, roles, err)
+}
+
+// buildReportSummary menghitung semua laporan untuk satu rentang (dipakai juga ekspor laporan).
+func buildReportSummary(st store.Store, rng models.ReportRange, interval string, limit int) (*models.ReportSummary, error) {
+	now := time.Now()
+	sum := &models.ReportSummary{Range: rng, Interval: interval}
+	var err error
+	if sum.Volume, err = st.GetLoanVolume(rng, interval); err != nil {
+		return nil, err
+	}
+	if sum.TopTitles, err = st.GetTopTitles(rng, limit); err != nil {
+		return nil, err
+	}
+	if sum.TopCategories, err = st.GetTopCategories(rng, limit); err != nil {
+		return nil, err
+	}
+	if sum.TopBorrowers, err = st.GetTopBorrowers(rng, limit); err != nil {
+		return nil, err
+	}
+	if sum.Fines, err = st.GetFineReport(rng, interval); err != nil {
+		return nil, err
+	}
+	if sum.Overdue, err = st.GetOverdueReport(rng, now); err != nil {
+		return nil, err
+	}
+	if sum.Roles, err = st.GetRoleReport(rng, now); err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// Summary endpoint (khusus admin).
+// Semua laporan sekaligus dengan parameter yang sama (start_date, end_date, interval, limit).
+func (h *ReportHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	rng, interval, limit, ok := reportParams(w, r)
+	if !ok {
+		return
+	}
+	sum, err := buildReportSummary(h.Store, rng, interval, limit)
+	writeReport(w, sum, err)
+}
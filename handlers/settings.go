@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"latihan_cloud8/middleware"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type SettingsHandler struct {
+	Store store.Store
+}
+
+func NewSettingsHandler(store store.Store) *SettingsHandler {
+	return &SettingsHandler{Store: store}
+}
+
+// validateSettings memeriksa nilai pengaturan dan mengembalikan pesan error (kosong jika valid).
+func validateSettings(s *models.Settings) string {
+	if s.MaxLoanBooks < 1 {
+		return "max_loan_books minimal 1"
+	}
+	if s.LoanDuration < 1 || s.LoanDuration > 365 {
+		return "loan_duration harus antara 1 dan 365 hari"
+	}
+	if s.FinePerDay < 0 {
+		return "fine_per_day tidak boleh negatif"
+	}
+	if s.HoldPickupDays < 1 {
+		return "hold_pickup_days minimal 1"
+	}
+	if s.MaxRenewals < 0 {
+		return "max_renewals tidak boleh negatif"
+	}
+	if s.ClosedWeekdays < 0 || s.ClosedWeekdays >= store.AllWeekdaysClosed {
+		return "closed_weekdays tidak valid, perpustakaan harus buka minimal satu hari"
+	}
+	if s.MaxOverdueLoans < -1 || s.MaxOutstandingFine < -1 {
+		return "max_overdue_loans dan max_outstanding_fine minimal -1 (-1 = tidak memblokir)"
+	}
+	return ""
+}
+
+// Settings endpoint (khusus admin).
+// GET mengambil pengaturan aktif, PUT menggantinya dan mencatat perubahan ke audit trail.
+func (h *SettingsHandler) Settings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetSettings(w, r)
+	case http.MethodPut:
+		h.UpdateSettings(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetSettings endpoint (khusus admin).
+// Mengambil pengaturan aplikasi yang sedang berlaku.
+func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.Store.GetSettings()
+	if err != nil {
+		http.Error(w, "Error fetching settings", http.StatusInternalServerError)
+		return
+	}
+	settings.ID = 1
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings endpoint (khusus admin).
+// Mengganti seluruh nilai pengaturan; field yang berubah dicatat beserta admin pengubahnya.
+func (h *SettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	user, err := h.Store.GetByUsername(claims.Username)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	var payload models.Settings
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if msg := validateSettings(&payload); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	payload.ID = 1
+
+	changes, err := h.Store.UpdateSettings(&payload, user.ID, user.Username)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if changes == nil {
+		changes = []models.SettingsChange{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"settings": payload,
+		"changes":  changes,
+	})
+}
+
+// SettingsHistory endpoint (khusus admin).
+// Menampilkan audit trail perubahan pengaturan, terbaru lebih dulu (?limit=, default 50).
+func (h *SettingsHandler) SettingsHistory(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	changes, err := h.Store.GetSettingsHistory(limit)
+	if err != nil {
+		http.Error(w, "Error fetching settings history", http.StatusInternalServerError)
+		return
+	}
+	if changes == nil {
+		changes = []models.SettingsChange{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// validateLetterhead merapikan isian kop surat dan mengembalikan pesan error (kosong jika valid).
+func validateLetterhead(lh *models.Letterhead) string {
+	fields := []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"library_name", &lh.LibraryName, 255},
+		{"institution", &lh.Institution, 255},
+		{"address", &lh.Address, 500},
+		{"city", &lh.City, 100},
+		{"phone", &lh.Phone, 50},
+		{"email", &lh.Email, 255},
+		{"website", &lh.Website, 255},
+		{"head_name", &lh.HeadName, 255},
+		{"head_nip", &lh.HeadNIP, 50},
+	}
+	for _, f := range fields {
+		*f.value = strings.TrimSpace(*f.value)
+		if utf8.RuneCountInString(*f.value) > f.max {
+			return fmt.Sprintf("%s maksimal %d karakter", f.name, f.max)
+		}
+	}
+	if lh.LibraryName == "" {
+		return "library_name wajib diisi"
+	}
+	if lh.Email != "" && !strings.Contains(lh.Email, "@") {
+		return "email tidak valid"
+	}
+	return ""
+}
+
+// Letterhead endpoint (khusus admin).
+// GET mengambil kop surat dokumen PDF, PUT menggantinya.
+func (h *SettingsHandler) Letterhead(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		lh, err := h.Store.GetLetterhead()
+		if err != nil {
+			http.Error(w, "Error fetching letterhead", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(lh)
+	case http.MethodPut:
+		h.UpdateLetterhead(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// UpdateLetterhead endpoint (khusus admin).
+// Mengganti seluruh isian kop surat; berlaku untuk dokumen yang dicetak sesudahnya.
+func (h *SettingsHandler) UpdateLetterhead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserCtxKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var payload models.Letterhead
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if msg := validateLetterhead(&payload); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	payload.UpdatedBy = claims.Username
+
+	if err := h.Store.UpdateLetterhead(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payload)
+}
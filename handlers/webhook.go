@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/webhook"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// deliveryListLimit membatasi jumlah log pengiriman yang ditampilkan per webhook.
+const deliveryListLimit = 50
+
+type WebhookHandler struct {
+	Store store.Store
+}
+
+func NewWebhookHandler(store store.Store) *WebhookHandler {
+	return &WebhookHandler{Store: store}
+}
+
+// webhookPayload adalah payload pembuatan dan perubahan webhook.
+type webhookPayload struct {
+	ID           int      `json:"id"`
+	URL          string   `json:"url"`
+	Events       []string `json:"events"`
+	Description  string   `json:"description"`
+	Active       *bool    `json:"active"`        // Default aktif saat dibuat
+	Secret       string   `json:"secret"`        // Opsional saat dibuat, dibuat otomatis jika kosong
+	RotateSecret bool     `json:"rotate_secret"` // Perubahan: buat secret baru
+}
+
+// validateWebhook memeriksa URL dan daftar event, mengembalikan pesan error atau string kosong.
+func validateWebhook(p *webhookPayload) string {
+	p.URL = strings.TrimSpace(p.URL)
+	u, err := url.Parse(p.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "URL harus berupa alamat http:// atau https://"
+	}
+	if len(p.Events) == 0 {
+		return "Pilih minimal satu event"
+	}
+	for _, e := range p.Events {
+		if !webhook.ValidEvent(e) {
+			return "Event tidak dikenal: " + e
+		}
+	}
+	p.Description = strings.TrimSpace(p.Description)
+	return ""
+}
+
+// GetWebhooks endpoint (khusus admin).
+// Menampilkan semua langganan webhook tanpa secret-nya.
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.Store.GetWebhooks()
+	if err != nil {
+		http.Error(w, "Error fetching webhooks", http.StatusInternalServerError)
+		return
+	}
+	if hooks == nil {
+		hooks = []models.Webhook{}
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hooks)
+}
+
+// CreateWebhook endpoint (khusus admin).
+// Mendaftarkan URL penerima untuk event tertentu. Secret untuk verifikasi tanda tangan
+// hanya ditampilkan pada respon ini.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var payload webhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if msg := validateWebhook(&payload); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	hook := &models.Webhook{
+		URL:         payload.URL,
+		Events:      payload.Events,
+		Description: payload.Description,
+		Active:      payload.Active == nil || *payload.Active,
+		Secret:      strings.TrimSpace(payload.Secret),
+	}
+	if hook.Secret == "" {
+		hook.Secret = webhook.NewSecret()
+	}
+	if err := h.Store.CreateWebhook(hook); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// UpdateWebhook endpoint (khusus admin).
+// Mengubah URL, event, deskripsi atau status aktif webhook. Dengan rotate_secret=true
+// secret baru dibuat dan dikembalikan pada respon.
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var payload webhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	current, err := h.Store.GetWebhook(payload.ID)
+	if err == store.ErrWebhookNotFound {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if msg := validateWebhook(&payload); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	hook := &models.Webhook{
+		ID:          payload.ID,
+		URL:         payload.URL,
+		Events:      payload.Events,
+		Description: payload.Description,
+		Active:      current.Active,
+		CreatedAt:   current.CreatedAt,
+	}
+	if payload.Active != nil {
+		hook.Active = *payload.Active
+	}
+	if payload.RotateSecret {
+		hook.Secret = webhook.NewSecret()
+	}
+	if err := h.Store.UpdateWebhook(hook); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hook)
+}
+
+// DeleteWebhook endpoint (khusus admin).
+// Menghapus webhook beserta log pengirimannya (?id=).
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.DeleteWebhook(id); err != nil {
+		if err == store.ErrWebhookNotFound {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Webhook deleted"})
+}
+
+// Deliveries endpoint (khusus admin).
+// Menampilkan log pengiriman terbaru sebuah webhook (?id=) beserta status dan percobaannya.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.Store.GetWebhook(id); err != nil {
+		if err == store.ErrWebhookNotFound {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := h.Store.GetWebhookDeliveries(id, deliveryListLimit)
+	if err != nil {
+		http.Error(w, "Error fetching deliveries", http.StatusInternalServerError)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// Redeliver endpoint (khusus admin).
+// Mengirim ulang payload sebuah pengiriman (?id=) sebagai pengiriman baru dengan event ID yang sama.
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.Store.RedeliverWebhook(id)
+	if err != nil {
+		if err == store.ErrDeliveryNotFound {
+			http.Error(w, "Delivery not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
@@ -0,0 +1,72 @@
+// Package health mencatat heartbeat worker latar belakang untuk probe kesiapan (/readyz).
+// Worker memanggil Expect saat mulai lalu Beat di setiap putaran; worker yang tidak
+// berdetak lebih lama dari batasnya dianggap macet.
+package health
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type heartbeat struct {
+	last   time.Time
+	maxAge time.Duration
+}
+
+var (
+	mu         sync.Mutex
+	heartbeats = make(map[string]*heartbeat)
+)
+
+// Expect mendaftarkan worker yang wajib berdetak paling lambat setiap maxAge.
+// Waktu pendaftaran dihitung sebagai detak pertama.
+func Expect(name string, maxAge time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	heartbeats[name] = &heartbeat{last: time.Now(), maxAge: maxAge}
+}
+
+// Beat mencatat bahwa worker masih berjalan.
+func Beat(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if hb, ok := heartbeats[name]; ok {
+		hb.last = time.Now()
+	}
+}
+
+// Stop melepas worker yang berhenti dengan sengaja agar tidak dianggap macet.
+func Stop(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(heartbeats, name)
+}
+
+// Stale mengembalikan nama worker yang detak terakhirnya melewati batas, terurut nama.
+func Stale(now time.Time) []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var stale []string
+	for name, hb := range heartbeats {
+		if now.Sub(hb.last) > hb.maxAge {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// Workers mengembalikan nama semua worker yang terdaftar, terurut nama.
+func Workers() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(heartbeats))
+	for name := range heartbeats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
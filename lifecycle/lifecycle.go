@@ -0,0 +1,107 @@
+// Package lifecycle mengatur siklus hidup proses server: menjalankan worker latar belakang,
+// menunggu sinyal berhenti (SIGINT/SIGTERM) lalu mematikan aplikasi dengan urutan:
+//
+//  1. HTTP server berhenti menerima koneksi baru dan request yang sedang berjalan diselesaikan
+//     (http.Server.Shutdown, dibatasi timeout). Fungsi yang didaftarkan lewat
+//     http.Server.RegisterOnShutdown dipanggil di awal langkah ini; pakai untuk memutus
+//     koneksi berumur panjang (WebSocket, SSE) yang tidak akan selesai sendiri,
+//  2. context worker dibatalkan dan putaran yang sedang berjalan ditunggu selesai,
+//  3. fungsi penutup yang didaftarkan lewat OnStop dijalankan sesuai urutan pendaftaran
+//     (mis. store, yang masih dipakai worker sampai langkah 2 selesai).
+//
+// Sinyal kedua selama proses berhenti langsung mengakhiri proses.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// stopHook adalah fungsi penutup beserta namanya untuk log.
+type stopHook struct {
+	name string
+	fn   func() error
+}
+
+type Manager struct {
+	signals    context.Context // Dibatalkan saat sinyal berhenti diterima
+	stopSignal context.CancelFunc
+	workers    context.Context // Dibatalkan setelah HTTP server selesai berhenti
+	stopWork   context.CancelFunc
+	wg         sync.WaitGroup
+	hooks      []stopHook
+}
+
+// New membuat Manager yang mulai mendengarkan sinyal SIGINT dan SIGTERM.
+func New() *Manager {
+	signals, stopSignal := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	workers, stopWork := context.WithCancel(context.Background())
+	return &Manager{signals: signals, stopSignal: stopSignal, workers: workers, stopWork: stopWork}
+}
+
+// Go menjalankan worker di goroutine tersendiri. fn harus kembali setelah ctx dibatalkan;
+// Serve menunggu semua worker kembali sebelum menjalankan fungsi penutup.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		fn(m.workers)
+		log.Printf("Lifecycle: %s stopped", name)
+	}()
+}
+
+// OnStop mendaftarkan fungsi penutup yang dijalankan setelah semua worker berhenti.
+func (m *Manager) OnStop(name string, fn func() error) {
+	m.hooks = append(m.hooks, stopHook{name: name, fn: fn})
+}
+
+// Serve menjalankan srv sampai sinyal berhenti diterima atau server gagal, lalu mematikan
+// aplikasi (lihat dokumentasi package). timeout membatasi lama menunggu request yang belum
+// selesai; setelah itu koneksi yang tersisa diputus paksa.
+func (m *Manager) Serve(srv *http.Server, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// Server gagal berjalan (mis. port sudah dipakai), worker tetap dihentikan dengan rapi
+	case <-m.signals.Done():
+		log.Println("🛑 Shutdown signal received, stopping server...")
+	}
+	// Kembalikan perilaku bawaan sinyal: sinyal berikutnya langsung mengakhiri proses
+	m.stopSignal()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if shutdownErr := srv.Shutdown(ctx); shutdownErr != nil {
+		log.Printf("Lifecycle: HTTP shutdown incomplete: %v", shutdownErr)
+		srv.Close()
+	}
+	if err == nil {
+		err = <-serveErr
+	}
+
+	m.stopWork()
+	m.wg.Wait()
+
+	for _, h := range m.hooks {
+		if hookErr := h.fn(); hookErr != nil {
+			log.Printf("Lifecycle: %s close error: %v", h.name, hookErr)
+		}
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"fmt"
 	"latihan_cloud8/handlers"
+	"latihan_cloud8/lifecycle"
+	"latihan_cloud8/metrics"
 	"latihan_cloud8/middleware"
+	"latihan_cloud8/notify"
+	"latihan_cloud8/scheduler"
 	"latihan_cloud8/store"
+	"latihan_cloud8/utils"
 	"latihan_cloud8/workers" // Import workers
 	"log"
 	"net/http"
@@ -11,50 +17,53 @@ import (
 	"time"
 )
 
+// shutdownTimeout membatasi lama menunggu request yang sedang berjalan saat aplikasi dihentikan.
+const shutdownTimeout = 15 * time.Second
+
 // function main adalah titik masuk aplikasi.
 func main() {
-	// Konfigurasi Database
-	dbUser := os.Getenv("DB_USER")
-	if dbUser == "" {
-		dbUser = "root"
-	}
-
-	dbPass := os.Getenv("DB_PASS")
-	if dbPass == "" {
-		dbPass = ""
-	}
-
-	dbHost := os.Getenv("DB_HOST")
-	if dbHost == "" {
-		dbHost = "localhost"
-	}
-
-	dbPort := os.Getenv("DB_PORT")
-	if dbPort == "" {
-		dbPort = "3306"
-	}
-
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		dbName = "jwt_auth_db"
-	}
-
-	// Format DSN: user:password@tcp(host:port)/dbname?parseTime=true
-	dsn := dbUser + ":" + dbPass + "@tcp(" + dbHost + ":" + dbPort + ")/" + dbName + "?parseTime=true"
-
-	// Inisialisasi koneksi MySQL
-	st, err := store.NewMySQLStore(dsn)
+	// Inisialisasi penyimpanan data (MySQL atau in-memory)
+	st, err := openStore()
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer st.Close()
+
+	// Perintah CLI: `simpus migrate status|up|down [n]`
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(st, os.Args[2:])
+		st.Close()
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
 
 	// Inisialisasi Skema Database (Tabel SIMPUS)
-	if err := st.InitSchema(); err != nil {
+	// Set AUTO_MIGRATE=false agar migrasi hanya dijalankan manual lewat perintah migrate.
+	if os.Getenv("AUTO_MIGRATE") == "false" {
+		if m, ok := st.(store.Migrator); ok {
+			pending, err := store.PendingMigrations(m)
+			if err != nil {
+				log.Fatalf("Failed to check migrations: %v", err)
+			}
+			if pending > 0 {
+				log.Fatalf("Database has %d pending migration(s), run `migrate up` first", pending)
+			}
+		}
+	} else if err := st.InitSchema(); err != nil {
 		log.Fatalf("Failed to upload schema: %v", err)
 	}
 
-	log.Println("✅ Successfully connected to MySQL database")
+	log.Println("✅ Successfully connected to database")
+
+	// Notifikasi baru langsung diteruskan ke browser yang terhubung lewat WebSocket,
+	// dan ke outbox kanal luar (email) untuk pengguna yang mengaktifkannya, lalu dihitung di /metrics
+	channels := notificationChannels()
+	st.SetNotificationPublisher(notify.Publishers{utils.NotificationHub, notify.NewDispatcher(st, channels...), metrics.NotificationPublisher{}})
+	// Statistik pool koneksi hanya ada untuk store berbasis database
+	if hc, ok := st.(store.HealthChecker); ok {
+		metrics.RegisterDBStats(hc.DBStats)
+	}
 
 	// Inisialisasi Handlers
 	// ============================================
@@ -62,14 +71,36 @@ func main() {
 	authHandler := handlers.NewAuthHandler(st)
 	bookHandler := handlers.NewBookHandler(st)
 	loanHandler := handlers.NewLoanHandler(st)
+	holdHandler := handlers.NewHoldHandler(st)
+	policyHandler := handlers.NewLoanPolicyHandler(st)
+	settingsHandler := handlers.NewSettingsHandler(st)
+	holidayHandler := handlers.NewHolidayHandler(st)
+	fineHandler := handlers.NewFineHandler(st)
+	outboxHandler := handlers.NewOutboxHandler(st)
+	webhookHandler := handlers.NewWebhookHandler(st)
+	reportHandler := handlers.NewReportHandler(st)
+	exportHandler := handlers.NewExportHandler(st)
+	documentHandler := handlers.NewDocumentHandler(st)
 
 	categoryHandler := handlers.NewCategoryHandler(st)
 	pageHandler := handlers.NewPageHandler(st)          // Inject store
 	notifHandler := handlers.NewNotificationHandler(st) // Init Notification Handler
 
-	// Mulai Worker Background (untuk notifikasi denda otomatis)
+	// Job terjadwal (pemeriksaan keterlambatan dan batas reservasi) dijalankan scheduler
+	// sesuai jadwal cron; jadwal bawaan bisa diganti lewat env JOB_OVERDUE_SCHEDULE dan JOB_HOLDS_SCHEDULE
 	notifier := workers.NewNotifier(st)
-	notifier.Start()
+	sched := scheduler.New(st)
+	if err := registerJobs(sched, notifier); err != nil {
+		log.Fatalf("Invalid job schedule: %v", err)
+	}
+	jobHandler := handlers.NewJobHandler(sched)
+
+	// Worker background (scheduler, pengiriman email dan webhook) berjalan sampai aplikasi
+	// dihentikan; lifecycle menunggu putaran yang sedang berjalan sebelum store ditutup
+	lc := lifecycle.New()
+	lc.Go("scheduler", sched.Run)
+	lc.Go("outbox worker", workers.NewOutboxWorker(st, channels...).Run)
+	lc.Go("webhook worker", workers.NewWebhookWorker(st).Run)
 
 	// Pengaturan Routing
 	mux := http.NewServeMux()
@@ -104,20 +135,34 @@ func main() {
 	mux.Handle("/admin/members", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(pageHandler.ShowAdminMembers))))
 	mux.Handle("/admin/transactions", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(pageHandler.ShowAdminTransactions))))
 	mux.Handle("/admin/reports", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(pageHandler.ShowAdminReports))))
+	mux.Handle("/admin/policies", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(pageHandler.ShowAdminPolicies))))
+	mux.Handle("/admin/settings", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(pageHandler.ShowAdminSettings))))
+	mux.Handle("/admin/jobs", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(pageHandler.ShowAdminJobs))))
 
 	// Member UI
 	mux.Handle("/catalog", middleware.AuthMiddleware(http.HandlerFunc(pageHandler.ShowCatalog)))
 	mux.Handle("/loans", middleware.AuthMiddleware(http.HandlerFunc(pageHandler.ShowMyLoans)))
+	mux.Handle("/fines", middleware.AuthMiddleware(http.HandlerFunc(pageHandler.ShowMyFines)))
 
 	// Route API (JSON)
 	mux.Handle("/api/profile", middleware.AuthMiddleware(http.HandlerFunc(authHandler.Profile)))
 	mux.Handle("/api/users", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(authHandler.GetUsers))))
 	mux.Handle("/api/users/update", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(authHandler.UpdateUser))))
 	mux.Handle("/api/users/delete", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(authHandler.DeleteUser))))
+	mux.Handle("/api/users/block", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(authHandler.BlockUser))))
+	mux.Handle("/api/users/unblock", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(authHandler.UnblockUser))))
+	mux.Handle("/api/users/membership", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(authHandler.SetMembership))))
 
 	mux.Handle("/api/books/create", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.CreateBook))))
 	mux.Handle("/api/books/update", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.UpdateBook))))
 	mux.Handle("/api/books/delete", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.DeleteBook))))
+	mux.Handle("/api/books/import", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.ImportBooks))))
+	mux.Handle("/api/books/import/marc", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.ImportMARC))))
+	mux.Handle("/api/books/items", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.GetItems))))
+	mux.Handle("/api/books/items/lookup", middleware.AuthMiddleware(http.HandlerFunc(bookHandler.LookupItem)))
+	mux.Handle("/api/books/items/create", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.CreateItem))))
+	mux.Handle("/api/books/items/update", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.UpdateItem))))
+	mux.Handle("/api/books/items/delete", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(bookHandler.DeleteItem))))
 
 	mux.Handle("/api/categories", middleware.AuthMiddleware(http.HandlerFunc(categoryHandler.GetCategories)))
 	mux.Handle("/api/categories/create", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(categoryHandler.CreateCategory))))
@@ -131,16 +176,101 @@ func main() {
 		}
 	})))
 	mux.Handle("/api/loans/return", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(loanHandler.Return))))
+	mux.Handle("/api/loans/renew", middleware.AuthMiddleware(http.HandlerFunc(loanHandler.Renew)))
+	mux.Handle("/api/loans/renewals", middleware.AuthMiddleware(http.HandlerFunc(loanHandler.Renewals)))
+	mux.Handle("/api/loans/eligibility", middleware.AuthMiddleware(http.HandlerFunc(loanHandler.Eligibility)))
+	mux.Handle("/api/loans/slip", middleware.AuthMiddleware(http.HandlerFunc(documentHandler.LoanSlip)))
+	mux.Handle("/api/loans/receipt", middleware.AuthMiddleware(http.HandlerFunc(documentHandler.ReturnReceipt)))
+
+	// Route Reservasi
+	mux.Handle("/api/holds", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			holdHandler.PlaceHold(w, r)
+		} else {
+			holdHandler.ListHolds(w, r)
+		}
+	})))
+	mux.Handle("/api/holds/cancel", middleware.AuthMiddleware(http.HandlerFunc(holdHandler.CancelHold)))
+	mux.Handle("/api/holds/expire", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(holdHandler.ExpireHolds))))
+
+	// Route Denda
+	mux.Handle("/api/fines", middleware.AuthMiddleware(http.HandlerFunc(fineHandler.ListFines)))
+	mux.Handle("/api/fines/balance", middleware.AuthMiddleware(http.HandlerFunc(fineHandler.Balance)))
+	mux.Handle("/api/fines/outstanding", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(fineHandler.Outstanding))))
+	mux.Handle("/api/fines/pay", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(fineHandler.RecordPayment))))
+	mux.Handle("/api/fines/waive", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(fineHandler.WaiveFine))))
+	mux.Handle("/api/fines/receipt", middleware.AuthMiddleware(http.HandlerFunc(documentHandler.FineReceipt)))
+
+	// Route Laporan Sirkulasi (?start_date=&end_date=&interval=&limit=)
+	mux.Handle("/api/reports", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.Summary))))
+	mux.Handle("/api/reports/loans", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.LoanVolume))))
+	mux.Handle("/api/reports/top-titles", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.TopTitles))))
+	mux.Handle("/api/reports/top-categories", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.TopCategories))))
+	mux.Handle("/api/reports/top-borrowers", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.TopBorrowers))))
+	mux.Handle("/api/reports/fines", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.Fines))))
+	mux.Handle("/api/reports/overdue", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.Overdue))))
+	mux.Handle("/api/reports/roles", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(reportHandler.Roles))))
+	mux.Handle("/api/reports/monthly", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(documentHandler.MonthlyReport))))
+
+	// Route Ekspor Berkas (?format=csv|xlsx)
+	mux.Handle("/api/export/loans", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(exportHandler.Loans))))
+	mux.Handle("/api/export/members", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(exportHandler.Members))))
+	mux.Handle("/api/export/catalog", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(exportHandler.Catalog))))
+	mux.Handle("/api/export/catalog/marc", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(exportHandler.CatalogMARC))))
+	mux.Handle("/api/export/report", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(exportHandler.Report))))
+
+	// Route Kebijakan Peminjaman
+	mux.Handle("/api/policies", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(policyHandler.GetPolicies))))
+	mux.Handle("/api/policies/create", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(policyHandler.CreatePolicy))))
+	mux.Handle("/api/policies/update", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(policyHandler.UpdatePolicy))))
+	mux.Handle("/api/policies/delete", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(policyHandler.DeletePolicy))))
+
+	// Route Pengaturan
+	mux.Handle("/api/settings", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(settingsHandler.Settings))))
+	mux.Handle("/api/settings/history", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(settingsHandler.SettingsHistory))))
+	mux.Handle("/api/settings/letterhead", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(settingsHandler.Letterhead))))
+
+	// Route Kalender Libur
+	mux.Handle("/api/holidays", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(holidayHandler.GetHolidays))))
+	mux.Handle("/api/holidays/create", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(holidayHandler.CreateHoliday))))
+	mux.Handle("/api/holidays/delete", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(holidayHandler.DeleteHoliday))))
+	mux.Handle("/api/holidays/import", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(holidayHandler.ImportHolidays))))
+
+	// Route Webhook
+	mux.Handle("/api/webhooks", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(webhookHandler.GetWebhooks))))
+	mux.Handle("/api/webhooks/create", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(webhookHandler.CreateWebhook))))
+	mux.Handle("/api/webhooks/update", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(webhookHandler.UpdateWebhook))))
+	mux.Handle("/api/webhooks/delete", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(webhookHandler.DeleteWebhook))))
+	mux.Handle("/api/webhooks/deliveries", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(webhookHandler.Deliveries))))
+	mux.Handle("/api/webhooks/redeliver", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(webhookHandler.Redeliver))))
+
+	// Route Job Terjadwal
+	mux.Handle("/api/jobs", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(jobHandler.GetJobs))))
+	mux.Handle("/api/jobs/runs", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(jobHandler.GetJobRuns))))
+	mux.Handle("/api/jobs/run", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(jobHandler.RunJob))))
 
 	// Route Notifikasi
 	mux.Handle("/notifications", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.ShowNotificationsPage)))
 	mux.Handle("/api/notifications", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.GetNotifications)))
+	mux.Handle("/api/notifications/preferences", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.Preferences)))
 	mux.Handle("/api/notifications/read", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.MarkRead)))
 	mux.Handle("/api/notifications/delete", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.DeleteNotification)))
+	mux.Handle("/api/notifications/stream", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.Stream)))
+	mux.Handle("/ws/notifications", middleware.AuthMiddleware(http.HandlerFunc(notifHandler.Socket)))
 	mux.Handle("/api/notifications/send", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(notifHandler.SendNotification))))
+	mux.Handle("/api/outbox", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(outboxHandler.GetOutbox))))
+	mux.Handle("/api/outbox/retry", middleware.AuthMiddleware(middleware.RequireRole("admin")(http.HandlerFunc(outboxHandler.RetryOutbox))))
+
+	// Terapkan Middleware Logging dan metrik secara Global
+	handler := middleware.Metrics(mux, middleware.Logging(mux))
 
-	// Terapkan Middleware Logging secara Global
-	handler := middleware.Logging(mux)
+	// Probe load balancer dan scrape Prometheus dilayani di luar logging dan metrik HTTP
+	healthHandler := handlers.NewHealthHandler(st)
+	root := http.NewServeMux()
+	root.HandleFunc("/healthz", healthHandler.Healthz)
+	root.HandleFunc("/readyz", healthHandler.Readyz)
+	root.Handle("/metrics", metrics.Handler())
+	root.Handle("/", handler)
 
 	// Konfigurasi Server
 	port := os.Getenv("PORT")
@@ -150,7 +280,7 @@ func main() {
 
 	srv := &http.Server{
 		Addr:         ":" + port,
-		Handler:      handler,
+		Handler:      root,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -158,5 +288,124 @@ func main() {
 	log.Printf("🚀 Server running on http://localhost:%s\n", port)
 	log.Printf("🔗 Login: http://localhost:%s/\n", port)
 	log.Printf("📊 Admin: http://localhost:%s/admin (after login)\n", port)
-	log.Fatal(srv.ListenAndServe())
+
+	// Koneksi WebSocket dan stream SSE diputus di awal shutdown: stream SSE adalah request
+	// biasa yang akan menahan Shutdown sampai timeout, sedangkan WebSocket tidak dilacak server
+	srv.RegisterOnShutdown(utils.NotificationHub.Close)
+	lc.OnStop("store", st.Close)
+
+	if err := lc.Serve(srv, shutdownTimeout); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+	log.Println("👋 Server stopped")
+}
+
+// registerJobs mendaftarkan job terjadwal aplikasi ke scheduler.
+func registerJobs(sched *scheduler.Scheduler, notifier *workers.Notifier) error {
+	overdueSchedule := os.Getenv("JOB_OVERDUE_SCHEDULE")
+	if overdueSchedule == "" {
+		overdueSchedule = "0 7 * * *" // Setiap hari pukul 07:00
+	}
+
+	holdsSchedule := os.Getenv("JOB_HOLDS_SCHEDULE")
+	if holdsSchedule == "" {
+		holdsSchedule = "0 * * * *" // Setiap awal jam
+	}
+
+	jobs := []scheduler.Job{
+		{
+			Name:        "overdue_check",
+			Description: "Tandai pinjaman terlambat, perbarui denda berjalan, kirim pengingat dan peringatan",
+			Schedule:    overdueSchedule,
+			Run:         notifier.Check,
+		},
+		{
+			Name:        "hold_expiry",
+			Description: "Akhiri reservasi yang melewati batas pengambilan",
+			Schedule:    holdsSchedule,
+			Run:         notifier.CheckHolds,
+		},
+	}
+	for _, job := range jobs {
+		if err := sched.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openStore memilih implementasi penyimpanan berdasarkan env DB_DRIVER.
+// Nilai yang didukung: "mysql" (default), "sqlite" (satu file, path dari DB_PATH)
+// dan "memory" (tanpa database, data hilang saat restart).
+func openStore() (store.Store, error) {
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = store.DriverMySQL
+	}
+
+	switch driver {
+	case "memory":
+		log.Println("⚠️  Using in-memory store, data will be lost on restart")
+		return store.NewMemoryStore(), nil
+	case store.DriverMySQL:
+		return store.NewMySQLStore(mysqlDSN())
+	case store.DriverSQLite:
+		dbPath := os.Getenv("DB_PATH")
+		if dbPath == "" {
+			dbPath = "simpus.db"
+		}
+		return store.NewSQLiteStore(dbPath)
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q", driver)
+	}
+}
+
+// notificationChannels menyiapkan kanal notifikasi luar yang dikonfigurasi lewat environment.
+// Kanal email aktif jika SMTP_HOST diisi (lihat notify.SMTPConfigFromEnv); APP_URL dipakai
+// untuk tautan di dalam email.
+func notificationChannels() []notify.Channel {
+	var channels []notify.Channel
+
+	cfg, ok := notify.SMTPConfigFromEnv()
+	if !ok {
+		log.Println("ℹ️  SMTP_HOST not set, email notifications disabled")
+		return channels
+	}
+	email, err := notify.NewEmailChannel(cfg, os.Getenv("APP_URL"))
+	if err != nil {
+		log.Fatalf("Failed to set up email channel: %v", err)
+	}
+	log.Printf("📧 Email notifications via %s:%s", cfg.Host, cfg.Port)
+	return append(channels, email)
+}
+
+// mysqlDSN menyusun DSN MySQL dari environment variable.
+func mysqlDSN() string {
+	dbUser := os.Getenv("DB_USER")
+	if dbUser == "" {
+		dbUser = "root"
+	}
+
+	dbPass := os.Getenv("DB_PASS")
+	if dbPass == "" {
+		dbPass = ""
+	}
+
+	dbHost := os.Getenv("DB_HOST")
+	if dbHost == "" {
+		dbHost = "localhost"
+	}
+
+	dbPort := os.Getenv("DB_PORT")
+	if dbPort == "" {
+		dbPort = "3306"
+	}
+
+	dbName := os.Getenv("DB_NAME")
+	if dbName == "" {
+		dbName = "jwt_auth_db"
+	}
+
+	// Format DSN: user:password@tcp(host:port)/dbname?parseTime=true
+	return dbUser + ":" + dbPass + "@tcp(" + dbHost + ":" + dbPort + ")/" + dbName + "?parseTime=true"
 }
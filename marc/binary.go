@@ -0,0 +1,201 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Karakter pemisah ISO 2709.
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+// Batas panjang ISO 2709: panjang rekaman 5 digit, panjang field 4 digit.
+const (
+	maxRecordLength = 99999
+	maxFieldLength  = 9999
+)
+
+type binaryReader struct {
+	r *bufio.Reader
+	n int // Nomor rekaman terakhir yang dibaca, untuk pesan kesalahan
+}
+
+// NewBinaryReader membuat Reader MARC21 biner (ISO 2709). Rekaman dipisahkan oleh record
+// terminator; baris baru di antara rekaman (umum pada berkas hasil unduhan) diabaikan.
+func NewBinaryReader(r io.Reader) Reader {
+	return &binaryReader{r: bufio.NewReader(r)}
+}
+
+func (br *binaryReader) Read() (*Record, error) {
+	data, err := br.r.ReadBytes(recordTerminator)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	data = bytes.TrimLeft(data, "\ufeff\r\n\t ")
+	if len(data) == 0 && err == io.EOF {
+		return nil, io.EOF
+	}
+	br.n++
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: rekaman %d tidak diakhiri record terminator", ErrInvalidRecord, br.n)
+	}
+	rec, perr := parseBinary(data)
+	if perr != nil {
+		return nil, fmt.Errorf("%w: rekaman %d: %s", ErrInvalidRecord, br.n, perr)
+	}
+	return rec, nil
+}
+
+// parseBinary mengurai satu rekaman ISO 2709 lengkap (termasuk record terminator).
+// Posisi data dibaca dari directory, bukan dari panjang di leader, agar rekaman dengan
+// panjang leader yang salah hitung (sering dari sistem lama) tetap terbaca.
+func parseBinary(data []byte) (*Record, error) {
+	if len(data) < LeaderLength+1 {
+		return nil, fmt.Errorf("rekaman terlalu pendek")
+	}
+	rec := &Record{Leader: string(data[:LeaderLength])}
+	base, err := strconv.Atoi(string(data[12:17]))
+	if err != nil || base <= LeaderLength || base > len(data) {
+		return nil, fmt.Errorf("alamat awal data tidak valid")
+	}
+	directory := data[LeaderLength : base-1]
+	if len(directory)%12 != 0 || data[base-1] != fieldTerminator {
+		return nil, fmt.Errorf("directory tidak valid")
+	}
+	// Rekaman tanpa penanda UTF-8 (leader/09) umumnya MARC-8; bagian ASCII-nya identik,
+	// sisanya dibaca sebagai Latin-1 agar tidak menghasilkan UTF-8 rusak.
+	utf8Record := data[9] == 'a'
+
+	for i := 0; i < len(directory); i += 12 {
+		entry := directory[i : i+12]
+		tag := string(entry[:3])
+		length, err1 := strconv.Atoi(string(entry[3:7]))
+		start, err2 := strconv.Atoi(string(entry[7:12]))
+		if err1 != nil || err2 != nil || !validTag(tag) {
+			return nil, fmt.Errorf("entri directory %q tidak valid", entry)
+		}
+		if base+start+length > len(data) || length == 0 {
+			return nil, fmt.Errorf("field %s melewati akhir rekaman", tag)
+		}
+		value := bytes.TrimSuffix(data[base+start:base+start+length], []byte{fieldTerminator})
+
+		if IsControlTag(tag) {
+			rec.Fields = append(rec.Fields, Field{Tag: tag, Value: decode(value, utf8Record)})
+			continue
+		}
+		if len(value) < 2 {
+			return nil, fmt.Errorf("field %s tidak memiliki indikator", tag)
+		}
+		f := Field{Tag: tag, Ind1: indicator(value[0]), Ind2: indicator(value[1])}
+		for j, part := range bytes.Split(value[2:], []byte{subfieldDelimiter}) {
+			// Bagian sebelum delimiter pertama kosong pada rekaman yang benar
+			if j == 0 || len(part) == 0 {
+				continue
+			}
+			f.Subfields = append(f.Subfields, Subfield{Code: part[0], Value: decode(part[1:], utf8Record)})
+		}
+		rec.Fields = append(rec.Fields, f)
+	}
+	return rec, nil
+}
+
+// decode mengubah isi field menjadi string UTF-8.
+func decode(b []byte, utf8Record bool) string {
+	if utf8Record || utf8.Valid(b) {
+		return string(b)
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+type binaryWriter struct {
+	w io.Writer
+}
+
+// NewBinaryWriter membuat Writer MARC21 biner (ISO 2709) berkode karakter UTF-8.
+func NewBinaryWriter(w io.Writer) Writer {
+	return &binaryWriter{w: w}
+}
+
+func (bw *binaryWriter) Write(rec *Record) error {
+	data, err := encodeBinary(rec)
+	if err != nil {
+		return err
+	}
+	_, err = bw.w.Write(data)
+	return err
+}
+
+func (bw *binaryWriter) Close() error {
+	return nil
+}
+
+// encodeBinary menyusun leader, directory dan isi field. Panjang rekaman, alamat awal data
+// dan kode karakter di leader selalu dihitung ulang.
+func encodeBinary(rec *Record) ([]byte, error) {
+	var directory, body bytes.Buffer
+	for _, f := range rec.Fields {
+		if !validTag(f.Tag) {
+			return nil, fmt.Errorf("%w: tag %q", ErrInvalidRecord, f.Tag)
+		}
+		start := body.Len()
+		if IsControlTag(f.Tag) {
+			body.WriteString(clean(f.Value))
+		} else {
+			body.WriteByte(indicator(f.Ind1))
+			body.WriteByte(indicator(f.Ind2))
+			for _, sf := range f.Subfields {
+				body.WriteByte(subfieldDelimiter)
+				body.WriteByte(sf.Code)
+				body.WriteString(clean(sf.Value))
+			}
+		}
+		body.WriteByte(fieldTerminator)
+		length := body.Len() - start
+		if length > maxFieldLength || start > maxRecordLength {
+			return nil, fmt.Errorf("%w: field %s", ErrRecordTooLong, f.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", f.Tag, length, start)
+	}
+
+	base := LeaderLength + directory.Len() + 1
+	total := base + body.Len() + 1
+	if total > maxRecordLength {
+		return nil, ErrRecordTooLong
+	}
+	ldr := []byte(leader(rec.Leader))
+	copy(ldr[0:5], fmt.Sprintf("%05d", total))
+	ldr[9] = 'a'
+	ldr[10], ldr[11] = '2', '2'
+	copy(ldr[12:17], fmt.Sprintf("%05d", base))
+	copy(ldr[20:24], "4500")
+
+	out := make([]byte, 0, total)
+	out = append(out, ldr...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, fieldTerminator)
+	out = append(out, body.Bytes()...)
+	out = append(out, recordTerminator)
+	return out, nil
+}
+
+// clean membuang karakter pemisah ISO 2709 dari nilai agar tidak merusak struktur rekaman.
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == subfieldDelimiter || r == fieldTerminator || r == recordTerminator {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,213 @@
+// Package marc membaca dan menulis rekaman bibliografis MARC21 dalam format biner ISO 2709
+// (.mrc) dan MARCXML, format pertukaran katalog antarperpustakaan (Perpusnas, SLiMS).
+// Paket ini hanya menangani struktur rekaman; pemetaan ke models.Book ada di paket catalog.
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Format berkas MARC.
+const (
+	FormatMARC = "mrc" // MARC21 biner (ISO 2709)
+	FormatXML  = "xml" // MARCXML
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown MARC format")
+	// ErrInvalidRecord dikembalikan jika struktur rekaman tidak sesuai ISO 2709 atau MARCXML.
+	ErrInvalidRecord = errors.New("marc: invalid record")
+	// ErrRecordTooLong dikembalikan jika rekaman melebihi batas panjang ISO 2709.
+	ErrRecordTooLong = errors.New("marc: record too long")
+)
+
+// Reader membaca rekaman satu per satu; Read mengembalikan io.EOF setelah rekaman terakhir.
+type Reader interface {
+	Read() (*Record, error)
+}
+
+// Writer menulis rekaman satu per satu. Close wajib dipanggil untuk menyelesaikan berkas.
+type Writer interface {
+	Write(rec *Record) error
+	Close() error
+}
+
+// NewReader membuat Reader untuk format yang diminta.
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatMARC:
+		return NewBinaryReader(r), nil
+	case FormatXML:
+		return NewXMLReader(r), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// NewWriter membuat Writer untuk format yang diminta.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatMARC:
+		return NewBinaryWriter(w), nil
+	case FormatXML:
+		return NewXMLWriter(w), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType mengembalikan MIME type berkas untuk format.
+func ContentType(format string) string {
+	if format == FormatXML {
+		return "application/marcxml+xml"
+	}
+	return "application/marc"
+}
+
+// Detect menebak format dari awal isi berkas: MARCXML diawali "<" (setelah BOM dan spasi),
+// MARC biner diawali lima digit panjang rekaman.
+func Detect(head []byte) string {
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\ufeff")), " \t\r\n")
+	if len(head) > 0 && head[0] == '<' {
+		return FormatXML
+	}
+	return FormatMARC
+}
+
+// ReadAll membaca semua rekaman dari r.
+func ReadAll(r Reader) ([]*Record, error) {
+	var records []*Record
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// LeaderLength adalah panjang leader rekaman MARC21.
+const LeaderLength = 24
+
+// Subfield adalah satu subfield ($a, $b, ...) pada field data.
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// Field adalah satu field rekaman. Field kontrol (tag 001–009) hanya memiliki Value;
+// field data memiliki dua indikator dan daftar subfield.
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// IsControl melaporkan apakah field merupakan field kontrol (tag 001–009).
+func (f *Field) IsControl() bool {
+	return IsControlTag(f.Tag)
+}
+
+// Subfield mengembalikan nilai subfield code pertama, atau "" jika tidak ada.
+func (f *Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// Record adalah satu rekaman MARC21.
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// NewRecord membuat rekaman bibliografis kosong (buku, monograf) berkode karakter UTF-8.
+func NewRecord() *Record {
+	return &Record{Leader: "00000nam a2200000 c 4500"}
+}
+
+// Field mengembalikan field tag pertama, atau nil jika tidak ada.
+func (r *Record) Field(tag string) *Field {
+	for i := range r.Fields {
+		if r.Fields[i].Tag == tag {
+			return &r.Fields[i]
+		}
+	}
+	return nil
+}
+
+// FieldsOf mengembalikan semua field dengan tag tersebut sesuai urutan rekaman.
+func (r *Record) FieldsOf(tag string) []*Field {
+	var fields []*Field
+	for i := range r.Fields {
+		if r.Fields[i].Tag == tag {
+			fields = append(fields, &r.Fields[i])
+		}
+	}
+	return fields
+}
+
+// AddControl menambahkan field kontrol.
+func (r *Record) AddControl(tag, value string) {
+	r.Fields = append(r.Fields, Field{Tag: tag, Value: value})
+}
+
+// AddData menambahkan field data. Subfield bernilai kosong dilewati dan field tanpa subfield
+// tidak ditambahkan, sehingga data buku yang kosong tidak menghasilkan field kosong.
+func (r *Record) AddData(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	f := Field{Tag: tag, Ind1: ind1, Ind2: ind2}
+	for _, sf := range subfields {
+		if sf.Value = strings.TrimSpace(sf.Value); sf.Value != "" {
+			f.Subfields = append(f.Subfields, sf)
+		}
+	}
+	if len(f.Subfields) > 0 {
+		r.Fields = append(r.Fields, f)
+	}
+}
+
+// IsControlTag melaporkan apakah tag termasuk field kontrol (001–009).
+func IsControlTag(tag string) bool {
+	return len(tag) == 3 && tag[0] == '0' && tag[1] == '0'
+}
+
+// validTag memeriksa tag tiga karakter alfanumerik.
+func validTag(tag string) bool {
+	if len(tag) != 3 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		c := tag[i]
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// indicator mengganti indikator kosong atau tidak valid dengan spasi (tidak didefinisikan).
+func indicator(b byte) byte {
+	if b < 0x20 || b > 0x7E {
+		return ' '
+	}
+	return b
+}
+
+// leader menormalkan leader menjadi tepat 24 karakter.
+func leader(s string) string {
+	if len(s) >= LeaderLength {
+		return s[:LeaderLength]
+	}
+	return s + strings.Repeat(" ", LeaderLength-len(s))
+}
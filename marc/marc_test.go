@@ -0,0 +1,212 @@
+package marc_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"latihan_cloud8/catalog"
+	"latihan_cloud8/marc"
+	"latihan_cloud8/models"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func testBooks() []models.Book {
+	created := time.Date(2026, 9, 20, 9, 0, 0, 0, time.UTC)
+	return []models.Book{
+		{ID: 1, Title: "Laskar Pelangi", Author: "Hirata, Andrea", Category: "Fiksi", ISBN: "9789793062792",
+			Publisher: "Bentang Pustaka", PublishedYear: 2005, CreatedAt: created},
+		{ID: 2, Title: "Bumi Manusia — Tetralogi Buru", Author: "Toer, Pramoedya Ananta", Category: "Sastra",
+			ISBN: "9789799731234", Publisher: "Lentera Dipantara", PublishedYear: 1980, CreatedAt: created},
+		{ID: 3, Title: "Pemrograman Go", Author: "Smith, J.", Category: "Komputer", ISBN: "0306406152",
+			Publisher: "Informatika", PublishedYear: 2021, CreatedAt: created},
+	}
+}
+
+// encode menulis buku sebagai rekaman MARC dalam format yang diminta.
+func encode(t *testing.T, format string, books []models.Book) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := marc.NewWriter(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range books {
+		if err := w.Write(catalog.MARCRecord(&books[i])); err != nil {
+			t.Fatalf("write %q: %v", books[i].Title, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// decode membaca kembali rekaman MARC menjadi rekaman impor katalog.
+func decode(t *testing.T, format string, data []byte) []catalog.Record {
+	t.Helper()
+	r, err := marc.NewReader(format, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recs, err := marc.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	records := make([]catalog.Record, len(recs))
+	for i, rec := range recs {
+		records[i] = catalog.RecordFromMARC(rec, i+1)
+	}
+	return records
+}
+
+func testRoundTrip(t *testing.T, format string) {
+	books := testBooks()
+	records := decode(t, format, encode(t, format, books))
+	if len(records) != len(books) {
+		t.Fatalf("got %d records, want %d", len(records), len(books))
+	}
+	for i, b := range books {
+		rec := records[i]
+		checks := []struct{ field, got, want string }{
+			{"title", rec.Title, b.Title},
+			{"author", rec.Author, b.Author},
+			{"isbn", rec.ISBN, b.ISBN},
+			{"publisher", rec.Publisher, b.Publisher},
+			{"year", rec.PublishedYear, strconv.Itoa(b.PublishedYear)},
+			{"category", rec.Category, b.Category},
+		}
+		for _, c := range checks {
+			if c.got != c.want {
+				t.Errorf("record %d %s = %q, want %q", i+1, c.field, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	testRoundTrip(t, marc.FormatMARC)
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	testRoundTrip(t, marc.FormatXML)
+}
+
+func TestDetect(t *testing.T) {
+	books := testBooks()
+	for _, format := range []string{marc.FormatMARC, marc.FormatXML} {
+		if got := marc.Detect(encode(t, format, books)); got != format {
+			t.Errorf("Detect(%s) = %q", format, got)
+		}
+	}
+}
+
+func TestBinaryLeaderAndDirectory(t *testing.T) {
+	book := testBooks()[1]
+	rec := catalog.MARCRecord(&book)
+	data := encode(t, marc.FormatMARC, []models.Book{book})
+
+	if got, _ := strconv.Atoi(string(data[0:5])); got != len(data) {
+		t.Errorf("leader record length = %d, want %d", got, len(data))
+	}
+	if data[9] != 'a' {
+		t.Errorf("leader/09 = %q, want 'a' (UTF-8)", data[9])
+	}
+	if string(data[20:24]) != "4500" {
+		t.Errorf("leader entry map = %q, want 4500", data[20:24])
+	}
+	base, _ := strconv.Atoi(string(data[12:17]))
+	if want := marc.LeaderLength + 12*len(rec.Fields) + 1; base != want {
+		t.Fatalf("base address = %d, want %d", base, want)
+	}
+	if data[base-1] != 0x1E {
+		t.Errorf("directory not terminated by field terminator")
+	}
+	if data[len(data)-1] != 0x1D {
+		t.Errorf("record not terminated by record terminator")
+	}
+
+	// Setiap entri directory menunjuk field yang diakhiri field terminator, berurutan tanpa celah
+	next := 0
+	for i, f := range rec.Fields {
+		entry := string(data[marc.LeaderLength+12*i : marc.LeaderLength+12*(i+1)])
+		length, _ := strconv.Atoi(entry[3:7])
+		start, _ := strconv.Atoi(entry[7:12])
+		if entry[:3] != f.Tag {
+			t.Errorf("directory entry %d tag = %q, want %q", i, entry[:3], f.Tag)
+		}
+		if start != next {
+			t.Errorf("field %s starts at %d, want %d", f.Tag, start, next)
+		}
+		if data[base+start+length-1] != 0x1E {
+			t.Errorf("field %s length %d does not end at field terminator", f.Tag, length)
+		}
+		next = start + length
+	}
+	if base+next+1 != len(data) {
+		t.Errorf("fields end at %d, record length %d", base+next+1, len(data))
+	}
+}
+
+func TestBinaryTruncatedRecord(t *testing.T) {
+	data := encode(t, marc.FormatMARC, testBooks()[:1])
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"missing terminator", data[:len(data)-10]},
+		{"leader only", append(append([]byte{}, data[:marc.LeaderLength]...), 0x1D)},
+		{"short directory", append(append([]byte{}, data[:marc.LeaderLength+20]...), 0x1D)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := marc.NewBinaryReader(bytes.NewReader(tt.data)).Read()
+			if !errors.Is(err, marc.ErrInvalidRecord) {
+				t.Fatalf("err = %v, want ErrInvalidRecord", err)
+			}
+		})
+	}
+}
+
+func TestBinaryReaderEOF(t *testing.T) {
+	r := marc.NewBinaryReader(bytes.NewReader(encode(t, marc.FormatMARC, testBooks()[:1])))
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("second Read err = %v, want io.EOF", err)
+	}
+}
+
+func TestImportExportDuplicates(t *testing.T) {
+	existing := testBooks()
+	categories := []models.Category{{ID: 1, Name: "Fiksi"}, {ID: 2, Name: "Sastra"}, {ID: 3, Name: "Komputer"}}
+
+	for _, format := range []string{marc.FormatMARC, marc.FormatXML} {
+		t.Run(format, func(t *testing.T) {
+			records := decode(t, format, encode(t, format, existing))
+
+			// Judul di katalog diubah agar duplikat hanya bisa dikenali dari ISBN
+			catalogBooks := testBooks()
+			for i := range catalogBooks {
+				catalogBooks[i].Title += " (Edisi Revisi)"
+			}
+			result := catalog.Plan(records, catalogBooks, categories, catalog.Options{DefaultStock: 1})
+
+			if result.Duplicates != len(existing) || result.Valid != 0 || result.Invalid != 0 {
+				t.Fatalf("valid=%d invalid=%d duplicates=%d, want all %d duplicate",
+					result.Valid, result.Invalid, result.Duplicates, len(existing))
+			}
+			for i, row := range result.Rows {
+				if row.Status != catalog.RowDuplicate || row.DuplicateOf != existing[i].ID {
+					t.Errorf("row %d status=%s duplicate_of=%d, want duplicate of %d",
+						row.Line, row.Status, row.DuplicateOf, existing[i].ID)
+				}
+			}
+			if len(catalog.Books(result)) != 0 {
+				t.Errorf("re-import would create %d books", len(catalog.Books(result)))
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Namespace adalah namespace MARCXML (MARC 21 slim).
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+// xmlRecord mengikuti skema MARC 21 slim. Nama elemen dicocokkan tanpa memperhatikan prefix
+// namespace sehingga <marc:record> juga terbaca.
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlReader struct {
+	d *xml.Decoder
+	n int
+}
+
+// NewXMLReader membuat Reader MARCXML. Berkas boleh berisi <collection> dengan banyak
+// <record> atau satu <record> saja.
+func NewXMLReader(r io.Reader) Reader {
+	d := xml.NewDecoder(r)
+	// Beberapa ekspor lama mendeklarasikan encoding selain UTF-8; isinya tetap dibaca apa adanya
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return &xmlReader{d: d}
+}
+
+func (xr *xmlReader) Read() (*Record, error) {
+	for {
+		tok, err := xr.d.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		xr.n++
+		var xrec xmlRecord
+		if err := xr.d.DecodeElement(&xrec, &start); err != nil {
+			return nil, fmt.Errorf("%w: rekaman %d: %s", ErrInvalidRecord, xr.n, err)
+		}
+		rec, err := fromXML(&xrec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rekaman %d: %s", ErrInvalidRecord, xr.n, err)
+		}
+		return rec, nil
+	}
+}
+
+func fromXML(xrec *xmlRecord) (*Record, error) {
+	rec := &Record{Leader: leader(strings.TrimSpace(xrec.Leader))}
+	for _, cf := range xrec.ControlFields {
+		if !validTag(cf.Tag) {
+			return nil, fmt.Errorf("tag controlfield %q tidak valid", cf.Tag)
+		}
+		rec.Fields = append(rec.Fields, Field{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, df := range xrec.DataFields {
+		if !validTag(df.Tag) {
+			return nil, fmt.Errorf("tag datafield %q tidak valid", df.Tag)
+		}
+		f := Field{Tag: df.Tag, Ind1: xmlIndicator(df.Ind1), Ind2: xmlIndicator(df.Ind2)}
+		for _, sf := range df.Subfields {
+			if len(sf.Code) != 1 {
+				return nil, fmt.Errorf("kode subfield %q pada field %s tidak valid", sf.Code, df.Tag)
+			}
+			f.Subfields = append(f.Subfields, Subfield{Code: sf.Code[0], Value: sf.Value})
+		}
+		rec.Fields = append(rec.Fields, f)
+	}
+	return rec, nil
+}
+
+func xmlIndicator(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return indicator(s[0])
+}
+
+type xmlWriter struct {
+	w       io.Writer
+	e       *xml.Encoder
+	started bool
+}
+
+// NewXMLWriter membuat Writer MARCXML yang menulis semua rekaman di dalam satu <collection>.
+func NewXMLWriter(w io.Writer) Writer {
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	return &xmlWriter{w: w, e: e}
+}
+
+var collection = xml.StartElement{
+	Name: xml.Name{Local: "collection"},
+	Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}},
+}
+
+// start menulis deklarasi XML dan tag pembuka <collection> sekali saja.
+func (xw *xmlWriter) start() error {
+	if xw.started {
+		return nil
+	}
+	xw.started = true
+	if _, err := io.WriteString(xw.w, xml.Header); err != nil {
+		return err
+	}
+	return xw.e.EncodeToken(collection)
+}
+
+func (xw *xmlWriter) Write(rec *Record) error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	xrec := xmlRecord{Leader: leader(rec.Leader)}
+	for _, f := range rec.Fields {
+		if !validTag(f.Tag) {
+			return fmt.Errorf("%w: tag %q", ErrInvalidRecord, f.Tag)
+		}
+		if f.IsControl() {
+			xrec.ControlFields = append(xrec.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+		df := xmlDataField{Tag: f.Tag, Ind1: string(indicator(f.Ind1)), Ind2: string(indicator(f.Ind2))}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		xrec.DataFields = append(xrec.DataFields, df)
+	}
+	// Leader MARCXML juga menandai UTF-8; panjang dan alamat data tidak bermakna di XML
+	ldr := []byte(xrec.Leader)
+	ldr[9] = 'a'
+	xrec.Leader = string(ldr)
+	return xw.e.Encode(xrec)
+}
+
+func (xw *xmlWriter) Close() error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	if err := xw.e.EncodeToken(collection.End()); err != nil {
+		return err
+	}
+	if err := xw.e.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(xw.w, "\n")
+	return err
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+)
+
+// Metrik aplikasi SIMPUS.
+var (
+	HTTPRequests = NewCounter("simpus_http_requests_total",
+		"Jumlah request HTTP per route, method dan status.", "route", "method", "status")
+	HTTPDuration = NewHistogram("simpus_http_request_duration_seconds",
+		"Durasi request HTTP per route dan method.", HTTPBuckets, "route", "method")
+
+	LoansCreated  = NewCounter("simpus_loans_created_total", "Jumlah peminjaman yang dibuat.")
+	LoansReturned = NewCounter("simpus_loans_returned_total", "Jumlah pengembalian buku.")
+
+	NotificationsSent = NewCounter("simpus_notifications_sent_total",
+		"Jumlah notifikasi terkirim per kanal (app = disimpan di aplikasi, email = terkirim lewat SMTP).", "channel")
+
+	WorkerDuration = NewHistogram("simpus_worker_run_duration_seconds",
+		"Durasi satu putaran worker atau satu run job terjadwal.", WorkerBuckets, "worker")
+	JobRuns = NewCounter("simpus_job_runs_total", "Jumlah run job terjadwal per hasil.", "job", "status")
+)
+
+// RegisterDBStats mendaftarkan statistik pool koneksi database yang dibaca dari stats saat scrape.
+func RegisterDBStats(stats func() sql.DBStats) {
+	NewGaugeFunc("simpus_db_max_open_connections", "Batas koneksi database terbuka.",
+		func() float64 { return float64(stats().MaxOpenConnections) })
+	NewGaugeFunc("simpus_db_open_connections", "Koneksi database yang sedang terbuka.",
+		func() float64 { return float64(stats().OpenConnections) })
+	NewGaugeFunc("simpus_db_in_use_connections", "Koneksi database yang sedang dipakai.",
+		func() float64 { return float64(stats().InUse) })
+	NewGaugeFunc("simpus_db_idle_connections", "Koneksi database yang menganggur.",
+		func() float64 { return float64(stats().Idle) })
+	NewCounterFunc("simpus_db_wait_count_total", "Jumlah permintaan koneksi yang harus menunggu.",
+		func() float64 { return float64(stats().WaitCount) })
+	NewCounterFunc("simpus_db_wait_duration_seconds_total", "Total waktu menunggu koneksi database.",
+		func() float64 { return stats().WaitDuration.Seconds() })
+}
+
+// NotificationPublisher menghitung notifikasi aplikasi yang dibuat; pasang di rantai
+// notify.Publishers bersama hub dan dispatcher.
+type NotificationPublisher struct{}
+
+func (NotificationPublisher) PublishNotification(n models.Notification) {
+	if n.ID == 0 {
+		return // Tidak tersimpan di aplikasi (kategori dimatikan penerima)
+	}
+	NotificationsSent.Inc("app")
+}
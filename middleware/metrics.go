@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"latihan_cloud8/metrics"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusRecorder menyimpan status respon untuk metrik. Unwrap dan Hijack dipertahankan
+// agar streaming SSE (http.ResponseController) dan upgrade WebSocket tetap berfungsi.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+// Metrics mencatat jumlah dan durasi request per route. Route diambil dari pola mux yang
+// cocok (mis. "/api/books" atau "/static/"), bukan path mentah, agar jumlah series terbatas.
+func Metrics(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(status))
+		metrics.HTTPDuration.Observe(time.Since(start).Seconds(), route, r.Method)
+	})
+}
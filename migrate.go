@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"latihan_cloud8/store"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// runMigrate menjalankan perintah `migrate status|up|down [n]`.
+func runMigrate(st store.Store, args []string) error {
+	m, ok := st.(store.Migrator)
+	if !ok {
+		return fmt.Errorf("store does not support migrations")
+	}
+
+	cmd := "status"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	switch cmd {
+	case "status":
+		statuses, err := m.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			status, appliedAt := "pending", "-"
+			if s.Applied {
+				status = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+		}
+		return tw.Flush()
+	case "up":
+		n, err := m.MigrateUp()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", n)
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			v, err := strconv.Atoi(args[1])
+			if err != nil || v < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = v
+		}
+		n, err := m.MigrateDown(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", n)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q (use status, up or down [n])", cmd)
+	}
+}
@@ -8,6 +8,8 @@ type Book struct {
 	Title         string    `json:"title" db:"title"`
 	Author        string    `json:"author" db:"author"`
 	Category      string    `json:"category" db:"category"`
+	ISBN          string    `json:"isbn" db:"isbn"` // ISBN-10/13 tanpa tanda hubung, kosong jika tidak ada
+	Publisher     string    `json:"publisher" db:"publisher"`
 	Stock         int       `json:"stock" db:"stock"`
 	ImageURL      string    `json:"image_url" db:"image_url"`
 	PublishedYear int       `json:"published_year" db:"published_year"`
@@ -19,6 +21,8 @@ type BookRequest struct {
 	Title         string `json:"title"`
 	Author        string `json:"author"`
 	Category      string `json:"category"`
+	ISBN          string `json:"isbn"`
+	Publisher     string `json:"publisher"`
 	Stock         int    `json:"stock"`
 	PublishedYear int    `json:"published_year"`
 }
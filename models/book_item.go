@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// BookItem merepresentasikan satu eksemplar fisik dari sebuah buku.
+type BookItem struct {
+	ID              int       `json:"id" db:"id"`
+	BookID          int       `json:"book_id" db:"book_id"`
+	Book            *Book     `json:"book,omitempty"`
+	CopyNo          int       `json:"copy_no" db:"copy_no"` // Nomor urut eksemplar per judul
+	Barcode         string    `json:"barcode" db:"barcode"`
+	AccessionNumber string    `json:"accession_number" db:"accession_number"` // Nomor induk/registrasi
+	Condition       string    `json:"condition" db:"item_condition"`          // "good", "fair", "damaged"
+	ShelfLocation   string    `json:"shelf_location" db:"shelf_location"`
+	Status          string    `json:"status" db:"status"` // "available", "borrowed", "maintenance", "lost"
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// BookItemRequest adalah payload untuk menambah atau mengubah eksemplar.
+type BookItemRequest struct {
+	BookID          int    `json:"book_id"`
+	Barcode         string `json:"barcode"`
+	AccessionNumber string `json:"accession_number"`
+	Condition       string `json:"condition"`
+	ShelfLocation   string `json:"shelf_location"`
+	Status          string `json:"status"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// BlockReason menjelaskan satu alasan anggota tidak boleh meminjam buku.
+type BlockReason struct {
+	Code    string `json:"code"` // "overdue", "fines", "membership_expired", "blocked"
+	Message string `json:"message"`
+}
+
+// Eligibility adalah hasil pemeriksaan kelayakan meminjam seorang anggota.
+type Eligibility struct {
+	Eligible bool          `json:"eligible"`
+	Reasons  []BlockReason `json:"reasons"`
+}
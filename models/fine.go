@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// FineEntry merepresentasikan satu baris buku besar denda: tagihan dari pengembalian
+// terlambat, pembayaran, atau pembebasan (waiver). Amount selalu positif.
+type FineEntry struct {
+	ID             int       `json:"id" db:"id"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	LoanID         int       `json:"loan_id,omitempty" db:"loan_id"` // Kosong jika tidak terkait pinjaman tertentu
+	Type           string    `json:"type" db:"entry_type"`           // "charge", "payment", "waiver"
+	Amount         int       `json:"amount" db:"amount"`
+	Method         string    `json:"method,omitempty" db:"method"`         // Khusus pembayaran: "cash", "transfer"
+	ReceiptNo      string    `json:"receipt_no,omitempty" db:"receipt_no"` // Nomor kuitansi pembayaran
+	Reason         string    `json:"reason,omitempty" db:"reason"`         // Alasan pembebasan denda
+	RecordedBy     string    `json:"recorded_by,omitempty" db:"recorded_by"`
+	RecordedByName string    `json:"recorded_by_name,omitempty"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	User           *User     `json:"user,omitempty"`
+	Book           *Book     `json:"book,omitempty"` // Buku pada pinjaman terkait
+}
+
+// FineBalance merangkum saldo denda seorang anggota.
+type FineBalance struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	Fullname    string `json:"fullname"`
+	Charged     int    `json:"charged"`
+	Paid        int    `json:"paid"`
+	Waived      int    `json:"waived"`
+	Outstanding int    `json:"outstanding"`
+	Accruing    int    `json:"accruing"` // Denda berjalan pinjaman terlambat yang belum dikembalikan, belum masuk buku besar
+}
+
+// FinePaymentRequest adalah payload pencatatan pembayaran atau pembebasan denda.
+type FinePaymentRequest struct {
+	UserID    string `json:"user_id"`
+	LoanID    int    `json:"loan_id"` // Opsional: batasi ke tagihan satu pinjaman
+	Amount    int    `json:"amount"`
+	Method    string `json:"method"`     // Pembayaran: "cash" atau "transfer"
+	ReceiptNo string `json:"receipt_no"` // Pembayaran: opsional, dibuat otomatis jika kosong
+	Reason    string `json:"reason"`     // Pembebasan: wajib diisi
+}
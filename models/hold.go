@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Hold merepresentasikan reservasi (antrean) buku yang stoknya habis.
+type Hold struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	BookID    int        `json:"book_id" db:"book_id"`
+	ItemID    int        `json:"item_id,omitempty" db:"item_id"` // Eksemplar yang disisihkan saat status "ready"
+	User      *User      `json:"user,omitempty"`
+	Book      *Book      `json:"book,omitempty"`
+	Item      *BookItem  `json:"item,omitempty"`
+	Status    string     `json:"status" db:"status"` // "waiting", "ready", "fulfilled", "expired", "cancelled"
+	Position  int        `json:"position,omitempty"` // Posisi antrean (hanya untuk status "waiting")
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ReadyAt   *time.Time `json:"ready_at" db:"ready_at"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"` // Batas pengambilan
+	ClosedAt  *time.Time `json:"closed_at" db:"closed_at"`
+}
+
+// HoldRequest adalah payload untuk membuat reservasi.
+type HoldRequest struct {
+	BookID int `json:"book_id"`
+}
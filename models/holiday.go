@@ -0,0 +1,8 @@
+package models
+
+// Holiday merepresentasikan tanggal libur (perpustakaan tutup).
+type Holiday struct {
+	ID   int    `json:"id" db:"id"`
+	Date string `json:"date" db:"holiday_date"` // Format 2006-01-02
+	Name string `json:"name" db:"name"`
+}
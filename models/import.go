@@ -0,0 +1,24 @@
+package models
+
+// ImportRow adalah hasil validasi satu baris impor katalog.
+type ImportRow struct {
+	Line          int      `json:"line"`   // Nomor baris/rekaman pada berkas (baris judul CSV = 1)
+	Status        string   `json:"status"` // "valid", "invalid", "duplicate"
+	Book          Book     `json:"book"`
+	Errors        []string `json:"errors,omitempty"`
+	DuplicateOf   int      `json:"duplicate_of,omitempty"`   // ID buku yang sudah ada di katalog
+	DuplicateLine int      `json:"duplicate_line,omitempty"` // Baris sebelumnya pada berkas yang sama
+}
+
+// ImportResult merangkum impor katalog, baik dry-run maupun yang sudah disimpan.
+type ImportResult struct {
+	DryRun        bool        `json:"dry_run"`
+	Committed     bool        `json:"committed"` // true jika buku sudah tersimpan
+	Total         int         `json:"total"`
+	Valid         int         `json:"valid"` // Baris yang akan/telah dibuat
+	Invalid       int         `json:"invalid"`
+	Duplicates    int         `json:"duplicates"` // Dilewati, tidak dianggap error
+	Copies        int         `json:"copies"`     // Jumlah eksemplar dari baris valid
+	NewCategories []string    `json:"new_categories"`
+	Rows          []ImportRow `json:"rows"`
+}
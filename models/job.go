@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Job adalah tugas terjadwal beserta ringkasan run terakhirnya.
+type Job struct {
+	Name           string     `json:"name" db:"name"`
+	Description    string     `json:"description" db:"-"`
+	Schedule       string     `json:"schedule" db:"-"`                        // Ekspresi cron 5 kolom, waktu lokal server
+	NextRunAt      *time.Time `json:"next_run_at,omitempty" db:"-"`           // Kosong jika job tidak terdaftar di instance ini
+	Running        bool       `json:"running" db:"-"`                         // Sedang dijalankan oleh instance ini
+	LastRunAt      *time.Time `json:"last_run_at,omitempty" db:"last_run_at"` // Waktu mulai run terakhir yang selesai
+	LastStatus     string     `json:"last_status,omitempty" db:"last_status"` // "success", "failed"
+	LastDurationMs int64      `json:"last_duration_ms" db:"last_duration_ms"` // Lama run terakhir dalam milidetik
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`   // Pesan kesalahan run terakhir yang gagal
+}
+
+// JobRun mencatat satu kali eksekusi job.
+type JobRun struct {
+	ID          int        `json:"id" db:"id"`
+	JobName     string     `json:"job_name" db:"job_name"`
+	ScheduledAt time.Time  `json:"scheduled_at" db:"scheduled_at"` // Slot jadwal; unik per job sehingga satu slot hanya dijalankan sekali
+	Trigger     string     `json:"trigger" db:"trigger_type"`      // "schedule" atau "manual"
+	TriggeredBy string     `json:"triggered_by,omitempty" db:"triggered_by"`
+	Instance    string     `json:"instance" db:"instance"` // Host dan PID proses yang menjalankan
+	Status      string     `json:"status" db:"status"`     // "running", "success", "failed"
+	StartedAt   time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	DurationMs  int64      `json:"duration_ms" db:"duration_ms"`
+	Error       string     `json:"error,omitempty" db:"error_message"`
+}
@@ -4,20 +4,41 @@ import "time"
 
 // Loan merepresentasikan data peminjaman buku.
 type Loan struct {
-	ID         int        `json:"id" db:"id"`
-	UserID     string     `json:"user_id" db:"user_id"`
-	BookID     int        `json:"book_id" db:"book_id"`
-	User       *User      `json:"user,omitempty"`
-	Book       *Book      `json:"book,omitempty"`
-	LoanDate   time.Time  `json:"loan_date" db:"loan_date"`
-	DueDate    time.Time  `json:"due_date" db:"due_date"`
-	ReturnDate *time.Time `json:"return_date" db:"return_date"`
-	Status     string     `json:"status" db:"status"` // "borrowed", "returned", "late"
-	Fine       int        `json:"fine" db:"fine"`
+	ID          int        `json:"id" db:"id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	BookID      int        `json:"book_id" db:"book_id"`
+	ItemID      int        `json:"item_id,omitempty" db:"item_id"` // Eksemplar fisik yang dipinjam
+	User        *User      `json:"user,omitempty"`
+	Book        *Book      `json:"book,omitempty"`
+	Item        *BookItem  `json:"item,omitempty"`
+	LoanDate    time.Time  `json:"loan_date" db:"loan_date"`
+	DueDate     time.Time  `json:"due_date" db:"due_date"`
+	ReturnDate  *time.Time `json:"return_date" db:"return_date"`
+	Status      string     `json:"status" db:"status"`               // "borrowed", "returned", "late"
+	Fine        int        `json:"fine" db:"fine"`                   // Denda final saat dikembalikan
+	AccruedFine int        `json:"accrued_fine" db:"accrued_fine"`   // Denda berjalan pinjaman terlambat, diperbarui setiap hari
+	Renewals    int        `json:"renewal_count" db:"renewal_count"` // Jumlah perpanjangan
 }
 
 // LoanRequest adalah payload untuk membuat peminjaman baru.
 type LoanRequest struct {
-	BookID   int `json:"book_id"`
-	Duration int `json:"duration"` // Durasi pinjam (hari)
+	BookID   int    `json:"book_id"`
+	Barcode  string `json:"barcode,omitempty"` // Opsional: pinjam eksemplar tertentu
+	Duration int    `json:"duration"`          // Durasi pinjam (hari)
+}
+
+// LoanRenewal merepresentasikan satu kali perpanjangan jatuh tempo peminjaman.
+type LoanRenewal struct {
+	ID          int       `json:"id" db:"id"`
+	LoanID      int       `json:"loan_id" db:"loan_id"`
+	RenewedBy   string    `json:"renewed_by" db:"renewed_by"` // ID user yang memperpanjang (anggota atau admin)
+	RenewedName string    `json:"renewed_by_name"`
+	OldDueDate  time.Time `json:"old_due_date" db:"old_due_date"`
+	NewDueDate  time.Time `json:"new_due_date" db:"new_due_date"`
+	RenewedAt   time.Time `json:"renewed_at" db:"renewed_at"`
+}
+
+// RenewRequest adalah payload untuk memperpanjang peminjaman.
+type RenewRequest struct {
+	LoanID int `json:"loan_id"`
 }
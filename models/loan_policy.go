@@ -0,0 +1,13 @@
+package models
+
+// LoanPolicy merepresentasikan aturan peminjaman untuk satu role,
+// opsional dipersempit ke satu kategori buku.
+type LoanPolicy struct {
+	ID           int    `json:"id" db:"id"`
+	Role         string `json:"role" db:"role"`
+	Category     string `json:"category" db:"category"` // Kosong berarti semua kategori
+	MaxLoanBooks int    `json:"max_loan_books" db:"max_loan_books"`
+	LoanDuration int    `json:"loan_duration" db:"loan_duration"` // Dalam hari
+	FinePerDay   int    `json:"fine_per_day" db:"fine_per_day"`
+	MaxRenewals  int    `json:"max_renewals" db:"max_renewals"`
+}
@@ -6,7 +6,25 @@ import "time"
 type Notification struct {
 	ID        int       `json:"id" db:"id"`
 	UserID    string    `json:"user_id" db:"user_id"`
+	Category  string    `json:"category" db:"category"` // "reminder", "overdue", "loan", "hold", "fine", "account", "broadcast", "general"
+	Severity  string    `json:"severity" db:"severity"` // "info", "warning", "critical"
 	Message   string    `json:"message" db:"message"`
 	IsRead    bool      `json:"is_read" db:"is_read"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+// NotificationFilter membatasi daftar notifikasi yang diambil. Field kosong berarti tidak difilter.
+type NotificationFilter struct {
+	Category string
+	IsRead   *bool
+}
+
+// NotificationPreference adalah pilihan pengguna untuk satu kategori notifikasi.
+// Kategori tanpa pilihan tersimpan dianggap aktif di aplikasi dan email.
+type NotificationPreference struct {
+	Category string `json:"category" db:"category"`
+	Label    string `json:"label" db:"-"`
+	Mutable  bool   `json:"mutable" db:"-"`       // false = kategori wajib, tidak bisa dimatikan
+	Enabled  bool   `json:"enabled" db:"enabled"` // Diterima di aplikasi (daftar notifikasi, WebSocket/SSE)
+	Email    bool   `json:"email" db:"email"`     // Juga dikirim lewat email jika notifikasi email aktif
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// OutboxMessage adalah pesan notifikasi yang menunggu dikirim lewat kanal luar
+// (misalnya email). Pesan yang gagal dikirim dicoba ulang sampai batas percobaan.
+type OutboxMessage struct {
+	ID             int        `json:"id" db:"id"`
+	NotificationID int        `json:"notification_id,omitempty" db:"notification_id"`
+	Message        string     `json:"message,omitempty" db:"message"` // Isi notifikasi asli, untuk deduplikasi
+	UserID         string     `json:"user_id" db:"user_id"`
+	Channel        string     `json:"channel" db:"channel"`     // "email"
+	Recipient      string     `json:"recipient" db:"recipient"` // Alamat tujuan pada kanal tersebut (tanpa nama)
+	Subject        string     `json:"subject" db:"subject"`
+	BodyText       string     `json:"body_text,omitempty" db:"body_text"`
+	BodyHTML       string     `json:"body_html,omitempty" db:"body_html"`
+	Status         string     `json:"status" db:"status"` // "pending", "sending", "sent", "failed"
+	Attempts       int        `json:"attempts" db:"attempts"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	SentAt         *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
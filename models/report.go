@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// ReportRange adalah rentang waktu laporan: Start inklusif, End eksklusif.
+type ReportRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// LoanVolume adalah jumlah peminjaman dan pengembalian dalam satu periode.
+type LoanVolume struct {
+	Period   string `json:"period"`   // Awal periode: "2006-01-02" (hari/minggu) atau "2006-01" (bulan)
+	Loans    int    `json:"loans"`    // Pinjaman yang dibuat dalam periode
+	Returned int    `json:"returned"` // Pengembalian yang terjadi dalam periode
+}
+
+// TitleCount adalah judul buku beserta jumlah peminjamannya.
+type TitleCount struct {
+	BookID   int    `json:"book_id"`
+	Title    string `json:"title"`
+	Author   string `json:"author"`
+	Category string `json:"category"`
+	Loans    int    `json:"loans"`
+}
+
+// CategoryCount adalah kategori buku beserta jumlah peminjamannya.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Loans    int    `json:"loans"`
+}
+
+// BorrowerCount adalah anggota beserta jumlah peminjaman dan denda pengembaliannya.
+type BorrowerCount struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Fullname string `json:"fullname"`
+	Role     string `json:"role"`
+	Loans    int    `json:"loans"`
+	Fines    int    `json:"fines"`
+}
+
+// FinePeriod adalah mutasi buku besar denda dalam satu periode.
+type FinePeriod struct {
+	Period    string `json:"period"`
+	Charged   int    `json:"charged"`
+	Collected int    `json:"collected"`
+	Waived    int    `json:"waived"`
+}
+
+// FineReport membandingkan denda yang ditagihkan dengan yang dibayar dalam rentang laporan.
+type FineReport struct {
+	Charged   int          `json:"charged"`
+	Collected int          `json:"collected"`
+	Waived    int          `json:"waived"`
+	Accruing  int          `json:"accruing"` // Denda berjalan saat ini, tidak dibatasi rentang
+	Periods   []FinePeriod `json:"periods"`
+}
+
+// OverdueReport adalah tingkat keterlambatan pinjaman yang jatuh tempo dalam rentang laporan.
+type OverdueReport struct {
+	Due         int     `json:"due"`          // Pinjaman yang jatuh temponya sudah lewat dalam rentang
+	Overdue     int     `json:"overdue"`      // Dikembalikan setelah jatuh tempo atau masih belum kembali
+	StillOpen   int     `json:"still_open"`   // Bagian dari Overdue yang belum dikembalikan
+	OverdueRate float64 `json:"overdue_rate"` // Overdue / Due, 0 jika Due 0
+}
+
+// RoleReport merangkum peminjaman per role anggota.
+type RoleReport struct {
+	Role      string `json:"role"`
+	Borrowers int    `json:"borrowers"` // Anggota berbeda yang meminjam
+	Loans     int    `json:"loans"`
+	Returned  int    `json:"returned"`
+	Overdue   int    `json:"overdue"`
+	Fines     int    `json:"fines"` // Denda final pinjaman yang sudah dikembalikan
+}
+
+// ReportSummary adalah gabungan semua laporan untuk satu rentang, dipakai halaman laporan admin,
+// ekspor laporan dan laporan bulanan PDF.
+type ReportSummary struct {
+	Range         ReportRange     `json:"range"`
+	Interval      string          `json:"interval"`
+	Volume        []LoanVolume    `json:"volume"`
+	TopTitles     []TitleCount    `json:"top_titles"`
+	TopCategories []CategoryCount `json:"top_categories"`
+	TopBorrowers  []BorrowerCount `json:"top_borrowers"`
+	Fines         *FineReport     `json:"fines"`
+	Overdue       *OverdueReport  `json:"overdue"`
+	Roles         []RoleReport    `json:"roles"`
+}
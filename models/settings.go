@@ -1,9 +1,44 @@
 package models
 
+import "time"
+
 // Settings merepresentasikan pengaturan global aplikasi.
 type Settings struct {
-	ID           int `json:"id" db:"id"`
-	MaxLoanBooks int `json:"max_loan_books" db:"max_loan_books"`
-	LoanDuration int `json:"loan_duration" db:"loan_duration"` // Dalam hari
-	FinePerDay   int `json:"fine_per_day" db:"fine_per_day"`
+	ID             int `json:"id" db:"id"`
+	MaxLoanBooks   int `json:"max_loan_books" db:"max_loan_books"`
+	LoanDuration   int `json:"loan_duration" db:"loan_duration"` // Dalam hari
+	FinePerDay     int `json:"fine_per_day" db:"fine_per_day"`
+	HoldPickupDays int `json:"hold_pickup_days" db:"hold_pickup_days"` // Batas hari pengambilan reservasi
+	MaxRenewals    int `json:"max_renewals" db:"max_renewals"`         // Batas perpanjangan per pinjaman
+	ClosedWeekdays int `json:"closed_weekdays" db:"closed_weekdays"`   // Bitmask hari tutup mingguan, bit 0 = Minggu
+
+	MaxOverdueLoans    int `json:"max_overdue_loans" db:"max_overdue_loans"`       // Pinjaman terlambat yang masih ditoleransi, -1 = tidak memblokir
+	MaxOutstandingFine int `json:"max_outstanding_fine" db:"max_outstanding_fine"` // Sisa denda yang masih ditoleransi, -1 = tidak memblokir
+}
+
+// SettingsChange merepresentasikan satu perubahan nilai pengaturan (audit trail).
+type SettingsChange struct {
+	ID            int       `json:"id" db:"id"`
+	ChangedBy     string    `json:"changed_by" db:"changed_by"`           // ID admin yang mengubah
+	ChangedByName string    `json:"changed_by_name" db:"changed_by_name"` // Username saat perubahan dilakukan
+	Field         string    `json:"field" db:"field"`
+	OldValue      int       `json:"old_value" db:"old_value"`
+	NewValue      int       `json:"new_value" db:"new_value"`
+	ChangedAt     time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// Letterhead adalah kop surat perpustakaan yang dicetak pada dokumen PDF (slip peminjaman,
+// bukti pengembalian, kuitansi denda dan laporan bulanan).
+type Letterhead struct {
+	LibraryName string    `json:"library_name" db:"library_name"`
+	Institution string    `json:"institution" db:"institution"` // Instansi induk, mis. nama sekolah
+	Address     string    `json:"address" db:"address"`
+	City        string    `json:"city" db:"city"` // Kota pada tanggal tanda tangan laporan
+	Phone       string    `json:"phone" db:"phone"`
+	Email       string    `json:"email" db:"email"`
+	Website     string    `json:"website" db:"website"`
+	HeadName    string    `json:"head_name" db:"head_name"` // Kepala perpustakaan penanda tangan laporan
+	HeadNIP     string    `json:"head_nip" db:"head_nip"`
+	UpdatedBy   string    `json:"updated_by,omitempty" db:"updated_by"` // Username admin pengubah terakhir
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
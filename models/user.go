@@ -12,6 +12,12 @@ type User struct {
 	NIP       string    `json:"nip" db:"nip"`         // Nomor Induk (NPM/NIP)
 	Contact   string    `json:"contact" db:"contact"` // HP/Email
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	MembershipExpiresAt *time.Time `json:"membership_expires_at,omitempty" db:"membership_expires_at"` // Tanggal terakhir keanggotaan berlaku, kosong = tanpa batas
+	BlockedReason       string     `json:"blocked_reason,omitempty" db:"blocked_reason"`               // Terisi jika diblokir manual oleh admin
+
+	Email              string `json:"email" db:"email"`                             // Alamat email notifikasi, kosong = pakai Contact jika berupa email
+	EmailNotifications bool   `json:"email_notifications" db:"email_notifications"` // Opt-in pengiriman notifikasi lewat email
 }
 
 // LoginRequest adalah payload untuk login.
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook adalah langganan event yang dikirim ke URL eksternal sebagai JSON bertanda tangan HMAC.
+type Webhook struct {
+	ID          int       `json:"id" db:"id"`
+	URL         string    `json:"url" db:"url"`
+	Secret      string    `json:"secret,omitempty" db:"secret"` // Kunci HMAC, hanya ditampilkan saat dibuat atau diganti
+	Events      []string  `json:"events" db:"events"`           // Mis. "loan.created", "*" untuk semua event
+	Description string    `json:"description" db:"description"`
+	Active      bool      `json:"active" db:"active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookEvent adalah isi (body) yang dikirim ke setiap webhook.
+type WebhookEvent struct {
+	ID        string          `json:"id"` // Sama untuk semua webhook dan setiap pengiriman ulang
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery mencatat satu pengiriman event ke satu webhook beserta hasil percobaannya.
+type WebhookDelivery struct {
+	ID            int        `json:"id" db:"id"`
+	WebhookID     int        `json:"webhook_id" db:"webhook_id"`
+	EventID       string     `json:"event_id" db:"event_id"`
+	Event         string     `json:"event" db:"event"`
+	Payload       string     `json:"payload" db:"payload"`
+	Status        string     `json:"status" db:"status"` // "pending", "sending", "delivered", "failed"
+	Attempts      int        `json:"attempts" db:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty" db:"response_code"` // Status HTTP percobaan terakhir
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+}
@@ -0,0 +1,270 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"latihan_cloud8/models"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// ChannelEmail adalah nama kanal email pada outbox.
+const ChannelEmail = "email"
+
+const (
+	// Batas waktu membuka koneksi dan menyelesaikan satu pengiriman SMTP
+	smtpDialTimeout = 10 * time.Second
+	smtpSendTimeout = 30 * time.Second
+	// Panjang maksimal ringkasan pesan pada subjek email
+	subjectMaxRunes = 70
+)
+
+// SMTPConfig berisi konfigurasi server SMTP.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // Kosong = tanpa autentikasi (misalnya SMTP lokal untuk pengujian)
+	Password string
+	From     string // Alamat pengirim, boleh dengan nama: "SIMPUS <noreply@contoh.ac.id>"
+}
+
+// SMTPConfigFromEnv membaca konfigurasi SMTP dari environment variable
+// SMTP_HOST, SMTP_PORT (default 25), SMTP_USERNAME, SMTP_PASSWORD dan SMTP_FROM.
+// ok bernilai false jika SMTP_HOST tidak diisi (kanal email tidak aktif).
+func SMTPConfigFromEnv() (cfg SMTPConfig, ok bool) {
+	cfg = SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "25"
+	}
+	if cfg.From == "" {
+		cfg.From = "SIMPUS <noreply@" + cfg.Host + ">"
+	}
+	return cfg, cfg.Host != ""
+}
+
+// emailData adalah data yang tersedia untuk template email.
+type emailData struct {
+	Name      string
+	Message   string
+	CreatedAt time.Time
+	Link      string // URL halaman notifikasi, kosong jika APP_URL tidak diatur
+}
+
+// EmailChannel mengirim notifikasi sebagai email multipart (teks biasa dan HTML) lewat SMTP.
+type EmailChannel struct {
+	Config SMTPConfig
+	AppURL string // URL dasar aplikasi untuk tautan di email, misalnya "https://simpus.contoh.ac.id"
+
+	from     *mail.Address
+	htmlTmpl *htmltemplate.Template
+	textTmpl *texttemplate.Template
+}
+
+// NewEmailChannel membuat kanal email dan memuat template dari direktori "templates/email".
+func NewEmailChannel(cfg SMTPConfig, appURL string) (*EmailChannel, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM: %v", err)
+	}
+	htmlTmpl, err := htmltemplate.ParseFiles(filepath.Join("templates", "email", "notification.html"))
+	if err != nil {
+		return nil, err
+	}
+	textTmpl, err := texttemplate.ParseFiles(filepath.Join("templates", "email", "notification.txt"))
+	if err != nil {
+		return nil, err
+	}
+	return &EmailChannel{
+		Config:   cfg,
+		AppURL:   strings.TrimRight(appURL, "/"),
+		from:     from,
+		htmlTmpl: htmlTmpl,
+		textTmpl: textTmpl,
+	}, nil
+}
+
+// Name mengembalikan nama kanal.
+func (c *EmailChannel) Name() string {
+	return ChannelEmail
+}
+
+// EmailAddress menentukan alamat email notifikasi user: field Email, atau Contact
+// jika berisi alamat email. Kosong jika tidak ada alamat yang valid.
+func EmailAddress(user *models.User) string {
+	for _, candidate := range []string{user.Email, user.Contact} {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		if addr, err := mail.ParseAddress(candidate); err == nil {
+			return addr.Address
+		}
+	}
+	return ""
+}
+
+// Prepare merender email notifikasi untuk user yang mengaktifkan notifikasi email.
+func (c *EmailChannel) Prepare(user *models.User, n models.Notification) (*models.OutboxMessage, error) {
+	if !user.EmailNotifications {
+		return nil, nil
+	}
+	addr := EmailAddress(user)
+	if addr == "" {
+		return nil, nil
+	}
+
+	data := emailData{Name: user.Fullname, Message: n.Message, CreatedAt: n.CreatedAt}
+	if data.Name == "" {
+		data.Name = user.Username
+	}
+	if c.AppURL != "" {
+		data.Link = c.AppURL + "/notifications"
+	}
+
+	var text, html bytes.Buffer
+	if err := c.textTmpl.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := c.htmlTmpl.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxMessage{
+		Recipient: addr,
+		Subject:   "[SIMPUS] " + summarize(n.Message, subjectMaxRunes),
+		BodyText:  text.String(),
+		BodyHTML:  html.String(),
+	}, nil
+}
+
+// Send mengirim pesan lewat server SMTP. STARTTLS dipakai jika ditawarkan server.
+func (c *EmailChannel) Send(msg *models.OutboxMessage) error {
+	to, err := mail.ParseAddress(msg.Recipient)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %v", err)
+	}
+	body, err := c.buildMessage(msg, to)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(c.Config.Host, c.Config.Port), smtpDialTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	client, err := smtp.NewClient(conn, c.Config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.Config.Host}); err != nil {
+			return err
+		}
+	}
+	if c.Config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := client.Auth(smtp.PlainAuth("", c.Config.Username, c.Config.Password, c.Config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage menyusun email MIME multipart/alternative berisi versi teks dan HTML.
+// Message-ID diturunkan dari ID outbox agar pengiriman ulang dapat dikenali penerima.
+func (c *EmailChannel) buildMessage(msg *models.OutboxMessage, to *mail.Address) ([]byte, error) {
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	for _, p := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", msg.BodyText},
+		{"text/html; charset=UTF-8", msg.BodyHTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(p.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	domain := c.from.Address[strings.LastIndex(c.from.Address, "@")+1:]
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", c.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("UTF-8", msg.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<simpus-outbox-%d@%s>", msg.ID, domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/alternative; boundary="` + mw.Boundary() + `"`},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+// summarize memotong pesan menjadi satu baris sepanjang maksimal max karakter.
+func summarize(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
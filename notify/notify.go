@@ -0,0 +1,98 @@
+// Package notify meneruskan notifikasi ke kanal di luar aplikasi web (misalnya email).
+// Notifikasi dirender menjadi pesan outbox saat dibuat, lalu dikirim oleh worker outbox
+// sehingga kegagalan kanal tidak menghambat request dan pesan yang gagal dapat dicoba ulang.
+package notify
+
+import (
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"log"
+	"time"
+)
+
+// MaxAttempts adalah batas percobaan kirim sebelum pesan outbox dinyatakan gagal.
+const MaxAttempts = 6
+
+// Channel adalah satu kanal pengiriman notifikasi.
+type Channel interface {
+	// Name adalah nama kanal yang disimpan pada kolom outbox channel.
+	Name() string
+	// Prepare merender notifikasi untuk user. Mengembalikan nil jika user tidak
+	// memilih menerima notifikasi lewat kanal ini.
+	Prepare(user *models.User, n models.Notification) (*models.OutboxMessage, error)
+	// Send mengirim pesan outbox yang sudah dirender.
+	Send(msg *models.OutboxMessage) error
+}
+
+// RetryDelay menghitung jeda sebelum percobaan berikutnya setelah attempts kali gagal:
+// 1, 2, 4, 8, 16 menit, dan seterusnya.
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return time.Minute << (attempts - 1)
+}
+
+// Dispatcher memasukkan setiap notifikasi baru ke outbox untuk kanal yang dipilih penerimanya,
+// dengan memperhatikan preferensi kategori notifikasi. Email mengikuti preferensi Email saja,
+// sehingga tetap dikirim walaupun kategori dimatikan di aplikasi (notifikasi ber-ID 0).
+// Dipasang sebagai store.NotificationPublisher.
+type Dispatcher struct {
+	Store    store.Store
+	Channels []Channel
+}
+
+// NewDispatcher membuat Dispatcher untuk kanal-kanal yang aktif.
+func NewDispatcher(store store.Store, channels ...Channel) *Dispatcher {
+	return &Dispatcher{Store: store, Channels: channels}
+}
+
+// PublishNotification merender notifikasi untuk setiap kanal lalu menyimpannya ke outbox.
+func (d *Dispatcher) PublishNotification(n models.Notification) {
+	if len(d.Channels) == 0 {
+		return
+	}
+	user, err := d.Store.GetUserByID(n.UserID)
+	if err != nil {
+		log.Println("Notify error (user):", err)
+		return
+	}
+	prefs, err := d.Store.GetNotificationPreferences(user.ID)
+	if err != nil {
+		log.Println("Notify error (preferences):", err)
+		return
+	}
+	pref := store.FindNotificationPreference(prefs, n.Category)
+
+	for _, ch := range d.Channels {
+		if ch.Name() == ChannelEmail && !pref.Email {
+			continue // User mematikan email untuk kategori ini
+		}
+		msg, err := ch.Prepare(user, n)
+		if err != nil {
+			log.Printf("Notify error (%s): %v", ch.Name(), err)
+			continue
+		}
+		if msg == nil {
+			continue // User tidak memilih kanal ini
+		}
+		msg.UserID = user.ID
+		msg.NotificationID = n.ID
+		msg.Message = n.Message
+		msg.Channel = ch.Name()
+		if err := d.Store.EnqueueOutbox(msg); err != nil {
+			log.Printf("Notify error (%s outbox): %v", ch.Name(), err)
+		}
+	}
+}
+
+// Publishers meneruskan setiap notifikasi ke beberapa publisher secara berurutan,
+// misalnya Hub WebSocket dan Dispatcher.
+type Publishers []store.NotificationPublisher
+
+// PublishNotification memanggil PublishNotification pada setiap publisher.
+func (ps Publishers) PublishNotification(n models.Notification) {
+	for _, p := range ps {
+		p.PublishNotification(n)
+	}
+}
@@ -0,0 +1,190 @@
+// Package pdf adalah penulis PDF minimal untuk dokumen cetak SIMPUS: halaman dengan teks
+// berfont standar Helvetica (tidak perlu menyematkan berkas font), garis dan kotak.
+//
+// Koordinat dalam point (1/72 inci) dengan titik asal di pojok kiri atas halaman dan y
+// bertambah ke bawah; y pada fungsi teks adalah garis dasar (baseline). Teks dikodekan
+// WinAnsi, sehingga karakter di luar Latin-1 (dan beberapa tanda baca umum) menjadi "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ukuran kertas dalam point.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+	A5Width  = 419.53
+	A5Height = 595.28
+)
+
+// Font adalah salah satu font standar PDF yang dipakai dokumen.
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+	Italic
+)
+
+var fontNames = [...]string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// Document adalah dokumen PDF yang disusun di memori lalu ditulis dengan WriteTo.
+type Document struct {
+	Title   string
+	Author  string
+	Subject string
+
+	width, height float64
+	pages         []*Page
+}
+
+// New membuat dokumen kosong dengan ukuran halaman width x height.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Width dan Height mengembalikan ukuran halaman dokumen.
+func (d *Document) Width() float64  { return d.width }
+func (d *Document) Height() float64 { return d.height }
+
+// AddPage menambah halaman baru di akhir dokumen.
+func (d *Document) AddPage() *Page {
+	p := &Page{height: d.height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Pages mengembalikan halaman dokumen sesuai urutan.
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Page adalah satu halaman; setiap pemanggilan menambah operator ke content stream-nya.
+type Page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+// Text menulis teks rata kiri mulai dari x.
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font+1, num(size), num(x), num(p.height-y), escape(encode(s)))
+}
+
+// TextRight menulis teks rata kanan yang berakhir di x.
+func (p *Page) TextRight(x, y float64, font Font, size float64, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// TextCenter menulis teks yang titik tengahnya di x.
+func (p *Page) TextCenter(x, y float64, font Font, size float64, s string) {
+	p.Text(x-TextWidth(font, size, s)/2, y, font, size, s)
+}
+
+// Line menggambar garis hitam setebal width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(p.height-y1), num(x2), num(p.height-y2))
+}
+
+// Rect menggambar garis tepi kotak dengan pojok kiri atas (x, y).
+func (p *Page) Rect(x, y, w, h, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n",
+		num(width), num(x), num(p.height-y-h), num(w), num(h))
+}
+
+// FillRect mengisi kotak dengan warna abu-abu (0 = hitam, 1 = putih).
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "q %s g %s %s %s %s re f Q\n",
+		num(gray), num(x), num(p.height-y-h), num(w), num(h))
+}
+
+// WriteTo menulis dokumen sebagai berkas PDF 1.4.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objek tetap: 1 katalog, 2 daftar halaman, 3-5 font, 6 info; lalu pasangan halaman dan isinya
+	const firstPage = 7
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range fontNames {
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	obj(fmt.Sprintf("<< /Title (%s) /Author (%s) /Subject (%s) /Producer (SIMPUS) /CreationDate (%s) >>",
+		escape(encode(d.Title)), escape(encode(d.Author)), escape(encode(d.Subject)), pdfDate(time.Now())))
+
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), firstPage+2*i+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(p.content.Bytes())
+		zw.Close()
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", len(offsets), z.Len())
+		buf.Write(z.Bytes())
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// num memformat angka dengan paling banyak dua desimal.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// pdfDate memformat waktu sesuai format tanggal PDF (D:YYYYMMDDHHmmSS+HH'mm').
+func pdfDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("D:%s%c%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
+
+// escape meloloskan karakter khusus string literal PDF.
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n', '\r', '\t':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
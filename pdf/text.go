@@ -0,0 +1,143 @@
+package pdf
+
+import "strings"
+
+// winAnsiExtra memetakan karakter di luar Latin-1 yang punya kode di WinAnsiEncoding (0x80-0x9F).
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, // Euro
+	'‚': 0x82,
+	'ƒ': 0x83,
+	'„': 0x84,
+	'…': 0x85, // Elipsis
+	'†': 0x86,
+	'‡': 0x87,
+	'ˆ': 0x88,
+	'‰': 0x89,
+	'Š': 0x8a,
+	'‹': 0x8b,
+	'Œ': 0x8c,
+	'Ž': 0x8e,
+	'‘': 0x91, // Tanda kutip lengkung
+	'’': 0x92,
+	'“': 0x93,
+	'”': 0x94,
+	'•': 0x95, // Bullet
+	'–': 0x96, // En dash
+	'—': 0x97, // Em dash
+	'˜': 0x98,
+	'™': 0x99,
+	'š': 0x9a,
+	'›': 0x9b,
+	'œ': 0x9c,
+	'ž': 0x9e,
+	'Ÿ': 0x9f,
+}
+
+// encode mengubah teks UTF-8 menjadi byte WinAnsi; karakter yang tidak terwakili menjadi "?".
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b = append(b, byte(r))
+		case r == '\n', r == '\r', r == '\t':
+			b = append(b, ' ')
+		default:
+			if c, ok := winAnsiExtra[r]; ok {
+				b = append(b, c)
+			} else {
+				b = append(b, '?')
+			}
+		}
+	}
+	return b
+}
+
+// Lebar glyph ASCII 32-126 (per 1000 unit em) dari metrik AFM standar Adobe.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [...]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// defaultWidth dipakai untuk karakter di luar ASCII (perkiraan lebar huruf rata-rata).
+const defaultWidth = 556
+
+// TextWidth menghitung lebar teks dalam point untuk font dan ukuran tertentu.
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := helveticaWidths[:]
+	if font == Bold {
+		widths = helveticaBoldWidths[:]
+	}
+	total := 0
+	for _, c := range encode(s) {
+		if c >= 32 && int(c-32) < len(widths) {
+			total += widths[c-32]
+		} else {
+			total += defaultWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Fit memotong teks agar muat dalam lebar width, diakhiri "..." jika dipotong.
+func Fit(font Font, size, width float64, s string) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 {
+		r = r[:len(r)-1]
+		if t := strings.TrimRight(string(r), " ") + "..."; TextWidth(font, size, t) <= width {
+			return t
+		}
+	}
+	return ""
+}
+
+// Wrap memecah teks menjadi baris-baris yang muat dalam lebar width. Pemisahan dilakukan di
+// spasi; kata yang lebih panjang dari satu baris dipotong paksa.
+func Wrap(font Font, size, width float64, s string) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if TextWidth(font, size, candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// Kata yang terlalu panjang dipecah per karakter
+			for TextWidth(font, size, word) > width {
+				r := []rune(word)
+				n := len(r) - 1
+				for n > 1 && TextWidth(font, size, string(r[:n])) > width {
+					n--
+				}
+				lines = append(lines, string(r[:n]))
+				word = string(r[n:])
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule adalah ekspresi cron 5 kolom: menit jam tanggal bulan hari-dalam-minggu.
+// Setiap kolom menerima "*", angka, rentang "a-b", daftar "a,b" dan langkah "*/n" atau "a-b/n".
+// Hari-dalam-minggu 0-7 (0 dan 7 = Minggu). Seperti cron biasa, jika kolom tanggal dan
+// hari-dalam-minggu sama-sama dibatasi, jadwal cocok bila salah satunya cocok; kolom yang
+// diawali "*" (termasuk "*/n") tidak dihitung sebagai dibatasi.
+// Langkah pada hari-dalam-minggu memilih hari tetap dihitung dari Minggu, bukan "setiap n hari":
+// "*/2" berarti Minggu, Selasa, Kamis dan Sabtu, sehingga Sabtu dan Minggu berikutnya berurutan.
+// Singkatan @hourly, @daily, @weekly dan @monthly juga didukung.
+type Schedule struct {
+	spec                         string
+	minute, hour, dom, month     uint64
+	dow                          uint64
+	domRestricted, dowRestricted bool
+}
+
+// cronAliases memetakan singkatan ke ekspresi lengkapnya.
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// cronField adalah batas nilai satu kolom cron.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"menit", 0, 59},
+	{"jam", 0, 23},
+	{"tanggal", 1, 31},
+	{"bulan", 1, 12},
+	{"hari", 0, 7},
+}
+
+// ParseSchedule mengurai ekspresi cron 5 kolom.
+func ParseSchedule(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	expr := spec
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("jadwal %q harus terdiri dari 5 kolom", spec)
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("jadwal %q: %v", spec, err)
+		}
+		bits[i] = b
+	}
+	// 7 dan 0 sama-sama hari Minggu
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		spec:          spec,
+		minute:        bits[0],
+		hour:          bits[1],
+		dom:           bits[2],
+		month:         bits[3],
+		dow:           bits[4],
+		domRestricted: !strings.HasPrefix(parts[2], "*"),
+		dowRestricted: !strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseCronField mengubah satu kolom cron menjadi bitset nilai yang cocok.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("langkah %s tidak valid: %q", f.name, item)
+			}
+			rangePart, step = item[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("rentang %s tidak valid: %q", f.name, item)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("nilai %s tidak valid: %q", f.name, item)
+			}
+			lo = n
+			// "5/10" berarti mulai 5 lalu setiap 10
+			if step == 1 {
+				hi = n
+			}
+		}
+		if lo < f.min || hi > f.max {
+			return 0, fmt.Errorf("nilai %s harus di antara %d dan %d: %q", f.name, f.min, f.max, item)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// String mengembalikan ekspresi asli jadwal.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// dayMatches memeriksa kolom tanggal dan hari-dalam-minggu untuk tanggal t.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+// Next mengembalikan waktu jadwal berikutnya setelah t (resolusi menit, zona waktu t).
+// Waktu nol dikembalikan jika tidak ada waktu yang cocok dalam lima tahun (mis. 30 Februari).
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
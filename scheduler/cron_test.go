@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name, spec, from, want string
+	}{
+		{"minute step", "*/15 * * * *", "2026-10-16 10:07", "2026-10-16 10:15"},
+		{"minute step rolls over hour", "*/15 * * * *", "2026-10-16 10:45", "2026-10-16 11:00"},
+		{"step from start value", "5/20 * * * *", "2026-10-16 10:26", "2026-10-16 10:45"},
+		{"stepped hour range", "0 9-17/4 * * *", "2026-10-16 10:00", "2026-10-16 13:00"},
+		{"stepped hour range rolls over day", "0 9-17/4 * * *", "2026-10-16 17:00", "2026-10-17 09:00"},
+		{"list", "0 6,18 * * *", "2026-10-16 06:00", "2026-10-16 18:00"},
+		{"strictly after from", "30 2 * * *", "2026-10-16 02:30", "2026-10-17 02:30"},
+		{"first of next month", "0 0 1 * *", "2026-10-16 08:00", "2026-11-01 00:00"},
+		{"skips months without the day", "0 0 31 * *", "2026-10-31 00:00", "2026-12-31 00:00"},
+		{"year rollover", "0 0 1 1 *", "2026-10-16 08:00", "2027-01-01 00:00"},
+		{"month field", "0 12 * 2 *", "2026-10-16 08:00", "2027-02-01 12:00"},
+		{"leap day", "0 0 29 2 *", "2026-10-16 08:00", "2028-02-29 00:00"},
+		{"weekday range", "0 8 * * 1-5", "2026-10-16 09:00", "2026-10-19 08:00"},
+		{"sunday as 7", "0 0 * * 7", "2026-10-16 08:00", "2026-10-18 00:00"},
+		{"weekly alias", "@weekly", "2026-10-16 08:00", "2026-10-18 00:00"},
+		{"weekday step counts from sunday", "0 0 * * */2", "2026-10-17 00:00", "2026-10-18 00:00"},
+		// Tanggal dan hari sama-sama dibatasi: cocok bila salah satunya cocok
+		{"dom or dow matches dow", "0 0 13 * 1", "2026-10-16 08:00", "2026-10-19 00:00"},
+		{"dom or dow matches dom", "0 0 13 * 1", "2026-11-10 08:00", "2026-11-13 00:00"},
+		// Kolom hari diawali "*" tidak dihitung dibatasi, jadi keduanya harus cocok
+		{"star step dow is not restricted", "0 0 1 * */2", "2026-11-02 08:00", "2026-12-01 00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format("2006-01-02 15:04 Mon"), tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextImpossible(t *testing.T) {
+	s, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(at("2026-10-16 08:00")); !got.IsZero() {
+		t.Errorf("Next = %s, want zero time", got)
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+		"@yearly",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) accepted", spec)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Package scheduler menjalankan job latar belakang sesuai jadwal cron (lihat Schedule).
+//
+// Setiap run dicatat di tabel job_runs lewat store.JobStore. Sebelum menjalankan job,
+// instance mengklaim slot jadwalnya (nama job + waktu jadwal); karena slot bersifat unik
+// di database, job hanya dijalankan satu kali per slot walaupun aplikasi berjalan di
+// beberapa instance sekaligus. Instance yang kalah klaim melewati slot tersebut.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"latihan_cloud8/health"
+	"latihan_cloud8/metrics"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// schedulerHeartbeat adalah batas detak pemeriksaan jadwal (setiap menit) sebelum dianggap macet.
+const schedulerHeartbeat = 3 * time.Minute
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+// Job adalah tugas yang dijalankan sesuai jadwal. Run menerima context yang dibatalkan
+// saat scheduler dihentikan; kesalahan yang dikembalikan dicatat sebagai run gagal.
+type Job struct {
+	Name        string
+	Description string
+	Schedule    string
+	Run         func(ctx context.Context) error
+}
+
+// entry adalah job terdaftar beserta jadwal terurai dan waktu run berikutnya.
+type entry struct {
+	job      Job
+	schedule *Schedule
+	next     time.Time
+	running  bool
+}
+
+type Scheduler struct {
+	Store    store.Store
+	Instance string // Pengenal instance di riwayat run, bawaan host:pid
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	ctx     context.Context
+	wg      sync.WaitGroup // Run yang sedang berjalan, ditunggu saat scheduler berhenti
+}
+
+// New membuat scheduler kosong; daftarkan job dengan Register lalu jalankan Run.
+func New(store store.Store) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		Store:    store,
+		Instance: fmt.Sprintf("%s:%d", host, os.Getpid()),
+		entries:  make(map[string]*entry),
+		ctx:      context.Background(),
+	}
+}
+
+// Register mendaftarkan job. Jadwal yang tidak valid ditolak.
+func (s *Scheduler) Register(job Job) error {
+	sched, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[job.Name] = &entry{job: job, schedule: sched, next: sched.Next(time.Now())}
+	return nil
+}
+
+// Run memeriksa jadwal setiap pergantian menit sampai ctx dibatalkan, lalu menunggu run
+// yang sedang berjalan selesai. Context yang sama diteruskan ke job sehingga job panjang bisa
+// berhenti lebih awal. Job tidak dijalankan saat mulai; slot yang terlewat selama aplikasi
+// mati tidak dikejar.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+	health.Expect("scheduler", schedulerHeartbeat)
+	defer health.Stop("scheduler")
+
+	for {
+		// Tidur sampai awal menit berikutnya agar jadwal tepat waktu
+		now := time.Now()
+		wait := now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-time.After(wait):
+		}
+		s.tick(time.Now())
+		health.Beat("scheduler")
+	}
+}
+
+// tick menjalankan job yang waktu jadwalnya sudah tiba.
+func (s *Scheduler) tick(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if e.next.IsZero() || now.Before(e.next) {
+			continue
+		}
+		slot := e.next
+		e.next = e.schedule.Next(now)
+		if e.running {
+			log.Printf("Scheduler: %s still running, slot %s skipped", e.job.Name, slot.Format("2006-01-02 15:04"))
+			continue
+		}
+		e.running = true
+		s.wg.Add(1)
+		go s.run(e, slot)
+	}
+}
+
+// Trigger menjalankan job secara manual di latar belakang. Slot run manual adalah menit picu,
+// kunci yang sama dengan run terjadwal, sehingga run manual dan run terjadwal (atau run manual
+// dari instance lain) pada menit yang sama tidak berjalan dua kali; ErrJobClaimed jika slot
+// tersebut sudah dipakai run lain.
+func (s *Scheduler) Trigger(name, triggeredBy string) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	if !ok {
+		s.mu.Unlock()
+		return ErrJobNotFound
+	}
+	if e.running {
+		s.mu.Unlock()
+		return ErrJobRunning
+	}
+	e.running = true
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	run, err := s.claim(e, time.Now().Truncate(time.Minute), store.TriggerManual, triggeredBy)
+	if err != nil {
+		s.done(e)
+		return err
+	}
+	go s.execute(e, run)
+	return nil
+}
+
+// run mengklaim slot jadwal lalu menjalankan job. Slot yang sudah diklaim instance lain dilewati.
+func (s *Scheduler) run(e *entry, slot time.Time) {
+	run, err := s.claim(e, slot, store.TriggerSchedule, "")
+	if err != nil {
+		if err == store.ErrJobClaimed {
+			log.Printf("Scheduler: %s slot %s claimed by another instance", e.job.Name, slot.Format("2006-01-02 15:04"))
+		} else {
+			log.Printf("Scheduler Error (%s): %v", e.job.Name, err)
+		}
+		s.done(e)
+		return
+	}
+	s.execute(e, run)
+}
+
+// claim mencatat run baru berstatus "running" untuk slot tersebut (lihat store.JobStore.StartJobRun).
+func (s *Scheduler) claim(e *entry, slot time.Time, trigger, triggeredBy string) (*models.JobRun, error) {
+	run := &models.JobRun{
+		JobName:     e.job.Name,
+		ScheduledAt: slot,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Instance:    s.Instance,
+		StartedAt:   time.Now(),
+	}
+	if err := s.Store.StartJobRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// execute menjalankan job yang slotnya sudah diklaim dan mencatat hasilnya.
+func (s *Scheduler) execute(e *entry, run *models.JobRun) {
+	defer s.done(e)
+
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	err := safeRun(ctx, e.job)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = store.JobSuccess
+	if err != nil {
+		run.Status = store.JobFailed
+		run.Error = err.Error()
+		log.Printf("Scheduler: %s failed after %dms: %v", e.job.Name, run.DurationMs, err)
+	} else {
+		log.Printf("Scheduler: %s finished in %dms", e.job.Name, run.DurationMs)
+	}
+	metrics.WorkerDuration.Observe(finished.Sub(run.StartedAt).Seconds(), e.job.Name)
+	metrics.JobRuns.Inc(e.job.Name, run.Status)
+	if err := s.Store.FinishJobRun(run); err != nil {
+		log.Printf("Scheduler Error (%s): %v", e.job.Name, err)
+	}
+}
+
+// done menandai job tidak lagi berjalan di instance ini.
+func (s *Scheduler) done(e *entry) {
+	s.mu.Lock()
+	e.running = false
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+// safeRun menjalankan job dan mengubah panic menjadi error agar run tetap tercatat.
+func safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Jobs menggabungkan job yang terdaftar dengan ringkasan run terakhir dari database.
+// Job yang pernah tercatat tetapi tidak terdaftar di instance ini tetap ditampilkan.
+func (s *Scheduler) Jobs() ([]models.Job, error) {
+	summaries, err := s.Store.GetJobs()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.Job)
+	for _, j := range summaries {
+		byName[j.Name] = j
+	}
+
+	s.mu.Lock()
+	for name, e := range s.entries {
+		j := byName[name]
+		j.Name = name
+		j.Description = e.job.Description
+		j.Schedule = e.schedule.String()
+		j.Running = e.running
+		if !e.next.IsZero() {
+			next := e.next
+			j.NextRunAt = &next
+		}
+		byName[name] = j
+	}
+	s.mu.Unlock()
+
+	jobs := make([]models.Job, 0, len(byName))
+	for _, j := range byName {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
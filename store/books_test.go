@@ -0,0 +1,40 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"testing"
+)
+
+func TestImportBooksRechecksISBN(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		existing := &models.Book{Title: "Laskar Pelangi", Author: "Andrea Hirata", Category: "Fiksi", ISBN: "9789793062792", Stock: 1}
+		if err := s.CreateBook(existing); err != nil {
+			t.Fatal(err)
+		}
+
+		// Buku kedua sudah ada di katalog, misalnya ditambahkan setelah berkas divalidasi
+		books := []models.Book{
+			{Title: "Bumi Manusia", Author: "Pramoedya", Category: "Sastra", ISBN: "9789799731234", Stock: 2},
+			{Title: "Laskar Pelangi", Author: "Andrea Hirata", Category: "Fiksi", ISBN: "9789793062792", Stock: 1},
+		}
+		if err := s.ImportBooks(books, []string{"Sastra"}); err != ErrISBNExists {
+			t.Fatalf("ImportBooks err = %v, want ErrISBNExists", err)
+		}
+		if n, _ := s.CountBooks(); n != 1 {
+			t.Errorf("%d books after rejected import, want 1", n)
+		}
+		categories, _ := s.GetAllCategories()
+		for _, c := range categories {
+			if c.Name == "Sastra" {
+				t.Error("category created by rejected import")
+			}
+		}
+
+		if err := s.ImportBooks(books[:1], []string{"Sastra"}); err != nil {
+			t.Fatal(err)
+		}
+		if books[0].ID == 0 || bookStock(t, s, books[0].ID) != 2 {
+			t.Errorf("imported book = %+v", books[0])
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"time"
+)
+
+// DateLayout adalah format tanggal libur yang disimpan (YYYY-MM-DD).
+const DateLayout = "2006-01-02"
+
+// AllWeekdaysClosed adalah bitmask ketujuh hari; perpustakaan tidak boleh tutup setiap hari.
+const AllWeekdaysClosed = 1<<7 - 1
+
+// maxClosedRun membatasi jumlah hari tutup berturut-turut yang dilewati saat menggeser
+// jatuh tempo, agar kalender yang keliru tidak membuat perulangan tanpa akhir.
+const maxClosedRun = 366
+
+// Calendar adalah kalender hari tutup perpustakaan: hari mingguan (bitmask, bit 0 = Minggu
+// sesuai time.Weekday) dan tanggal libur. Tanggal dibandingkan dalam zona waktu lokal server.
+// Calendar nil berarti perpustakaan buka setiap hari.
+type Calendar struct {
+	ClosedWeekdays int
+	Holidays       map[string]bool
+}
+
+// NewCalendar membuat kalender dari bitmask hari tutup mingguan dan daftar tanggal libur.
+func NewCalendar(closedWeekdays int, holidays []models.Holiday) *Calendar {
+	c := &Calendar{ClosedWeekdays: closedWeekdays, Holidays: make(map[string]bool, len(holidays))}
+	for _, h := range holidays {
+		c.Holidays[h.Date] = true
+	}
+	return c
+}
+
+// IsClosed memeriksa apakah perpustakaan tutup pada tanggal t.
+func (c *Calendar) IsClosed(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	t = t.Local()
+	if c.ClosedWeekdays&(1<<uint(t.Weekday())) != 0 {
+		return true
+	}
+	return c.Holidays[t.Format(DateLayout)]
+}
+
+// RollForward menggeser t ke hari buka berikutnya (jam tidak berubah).
+func (c *Calendar) RollForward(t time.Time) time.Time {
+	for i := 0; i < maxClosedRun && c.IsClosed(t); i++ {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// DueDate menghitung jatuh tempo days hari setelah from; jika jatuh pada hari tutup,
+// jatuh tempo digeser ke hari buka berikutnya.
+func (c *Calendar) DueDate(from time.Time, days int) time.Time {
+	return c.RollForward(from.AddDate(0, 0, days))
+}
+
+// ClosedDaysAfter menghitung hari tutup di antara days hari setelah tanggal from.
+func (c *Calendar) ClosedDaysAfter(from time.Time, days int) int {
+	if c == nil {
+		return 0
+	}
+	closed := 0
+	for i := 1; i <= days; i++ {
+		if c.IsClosed(from.AddDate(0, 0, i)) {
+			closed++
+		}
+	}
+	return closed
+}
+
+// LoadCalendar menyusun kalender dari pengaturan dan tanggal libur di store.
+func LoadCalendar(s Store) (*Calendar, error) {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.GetHolidays()
+	if err != nil {
+		return nil, err
+	}
+	return NewCalendar(settings.ClosedWeekdays, holidays), nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"fmt"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// Kode alasan blokir peminjaman.
+const (
+	BlockOverdue           = "overdue"
+	BlockFines             = "fines"
+	BlockMembershipExpired = "membership_expired"
+	BlockManual            = "blocked"
+)
+
+// CheckEligibility mengevaluasi apakah pengguna boleh meminjam buku baru pada waktu now.
+// Semua aturan diperiksa sekaligus agar anggota melihat seluruh alasan blokir:
+// blokir manual, keanggotaan kedaluwarsa, pinjaman terlambat di atas ambang, dan
+// sisa denda di atas ambang (ambang -1 pada pengaturan menonaktifkan aturan tersebut).
+func CheckEligibility(s Store, user *models.User, now time.Time) (*models.Eligibility, error) {
+	settings, err := s.GetSettings()
+	if err != nil {
+		settings = DefaultSettings()
+	}
+
+	result := &models.Eligibility{Reasons: []models.BlockReason{}}
+	block := func(code, msg string) {
+		result.Reasons = append(result.Reasons, models.BlockReason{Code: code, Message: msg})
+	}
+
+	if user.BlockedReason != "" {
+		block(BlockManual, "Akun diblokir oleh petugas: "+user.BlockedReason)
+	}
+	// Keanggotaan masih berlaku sampai akhir hari tanggal berakhirnya
+	if user.MembershipExpiresAt != nil && !now.Before(user.MembershipExpiresAt.AddDate(0, 0, 1)) {
+		block(BlockMembershipExpired, fmt.Sprintf("Keanggotaan berakhir pada %s, silakan perpanjang keanggotaan",
+			user.MembershipExpiresAt.Format("02 Jan 2006")))
+	}
+
+	if settings.MaxOverdueLoans >= 0 {
+		overdue, err := s.GetOverdueLoans(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(overdue) > settings.MaxOverdueLoans {
+			block(BlockOverdue, fmt.Sprintf("Terdapat %d buku terlambat dikembalikan", len(overdue)))
+		}
+	}
+
+	if settings.MaxOutstandingFine >= 0 {
+		balance, err := s.GetFineBalance(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Outstanding > settings.MaxOutstandingFine {
+			block(BlockFines, fmt.Sprintf("Sisa denda Rp %d melebihi batas Rp %d", balance.Outstanding, settings.MaxOutstandingFine))
+		}
+	}
+
+	result.Eligible = len(result.Reasons) == 0
+	return result, nil
+}
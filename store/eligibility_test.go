@@ -0,0 +1,84 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"testing"
+	"time"
+)
+
+func hasReason(e *models.Eligibility, code string) bool {
+	for _, r := range e.Reasons {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEligibility(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		user := createTestUser(t, s, "eka")
+		late := createTestBook(t, s, "Cantik Itu Luka", 1)
+		fined := createTestBook(t, s, "Saman", 1)
+
+		// Satu pinjaman terlambat yang belum kembali dan satu denda yang belum dibayar
+		if _, err := s.BorrowBook(user.ID, late.ID, -2); err != nil {
+			t.Fatal(err)
+		}
+		returnLate(t, s, user, fined, 1)
+		now := time.Now()
+
+		check := func() *models.Eligibility {
+			t.Helper()
+			u, err := s.GetUserByID(user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := CheckEligibility(s, u, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return e
+		}
+
+		// Ambang bawaan -1: tidak ada aturan yang memblokir
+		if e := check(); !e.Eligible {
+			t.Fatalf("default settings blocked: %+v", e.Reasons)
+		}
+
+		settings, err := s.GetSettings()
+		if err != nil {
+			t.Fatal(err)
+		}
+		settings.MaxOverdueLoans = 0
+		settings.MaxOutstandingFine = 4000
+		if _, err := s.UpdateSettings(settings, "admin", "admin"); err != nil {
+			t.Fatalf("update settings: %v", err)
+		}
+		e := check()
+		if e.Eligible || !hasReason(e, BlockOverdue) || !hasReason(e, BlockFines) {
+			t.Fatalf("eligibility = %+v, want blocked for overdue and fines", e)
+		}
+
+		settings.MaxOverdueLoans = 1
+		settings.MaxOutstandingFine = 5000
+		if _, err := s.UpdateSettings(settings, "admin", "admin"); err != nil {
+			t.Fatal(err)
+		}
+		if e := check(); !e.Eligible {
+			t.Fatalf("within thresholds blocked: %+v", e.Reasons)
+		}
+
+		if err := s.SetUserBlock(user.ID, "Kartu hilang"); err != nil {
+			t.Fatal(err)
+		}
+		expired := now.AddDate(0, 0, -2)
+		if err := s.SetMembershipExpiry(user.ID, &expired); err != nil {
+			t.Fatal(err)
+		}
+		e = check()
+		if e.Eligible || !hasReason(e, BlockManual) || !hasReason(e, BlockMembershipExpired) {
+			t.Fatalf("eligibility = %+v, want blocked manually and expired", e)
+		}
+	})
+}
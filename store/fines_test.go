@@ -0,0 +1,114 @@
+package store
+
+import (
+	"errors"
+	"latihan_cloud8/models"
+	"testing"
+)
+
+// returnLate membuat pinjaman yang jatuh tempo days hari lalu lalu mengembalikannya, sehingga
+// dikenai denda days × FinePerDay bawaan.
+func returnLate(t *testing.T, s Store, user *models.User, book *models.Book, days int) *models.Loan {
+	t.Helper()
+	loan, err := s.BorrowBook(user.ID, book.ID, -days)
+	if err != nil {
+		t.Fatalf("borrow: %v", err)
+	}
+	returned, err := s.ReturnBook(loan.ID)
+	if err != nil {
+		t.Fatalf("return: %v", err)
+	}
+	if want := days * DefaultSettings().FinePerDay; returned.Fine != want {
+		t.Fatalf("fine = %d, want %d", returned.Fine, want)
+	}
+	return returned
+}
+
+func fineOutstanding(t *testing.T, s Store, userID string) int {
+	t.Helper()
+	balance, err := s.GetFineBalance(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance.Outstanding
+}
+
+func TestFineLedger(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		user := createTestUser(t, s, "citra")
+		book := createTestBook(t, s, "Bumi Manusia", 1)
+		loan := returnLate(t, s, user, book, 3) // Rp 15.000
+
+		if got := fineOutstanding(t, s, user.ID); got != 15000 {
+			t.Fatalf("outstanding = %d, want 15000", got)
+		}
+
+		tests := []struct {
+			name  string
+			entry models.FineEntry
+			err   error
+		}{
+			{"zero amount", models.FineEntry{UserID: user.ID, Amount: 0, Method: "cash"}, ErrInvalidAmount},
+			{"exceeds balance", models.FineEntry{UserID: user.ID, Amount: 20000, Method: "cash"}, ErrExceedsBalance},
+			{"unknown user", models.FineEntry{UserID: "tidak-ada", Amount: 1000, Method: "cash"}, ErrUserNotFound},
+			{"unknown loan", models.FineEntry{UserID: user.ID, LoanID: 9999, Amount: 1000, Method: "cash"}, ErrLoanNotFound},
+		}
+		for _, tt := range tests {
+			entry := tt.entry
+			if err := s.RecordFinePayment(&entry); !errors.Is(err, tt.err) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, tt.err)
+			}
+		}
+
+		pay := models.FineEntry{UserID: user.ID, LoanID: loan.ID, Amount: 10000, Method: "cash", ReceiptNo: "KW-001"}
+		if err := s.RecordFinePayment(&pay); err != nil {
+			t.Fatalf("pay: %v", err)
+		}
+		dup := models.FineEntry{UserID: user.ID, Amount: 1000, Method: "cash", ReceiptNo: "KW-001"}
+		if err := s.RecordFinePayment(&dup); !errors.Is(err, ErrReceiptExists) {
+			t.Fatalf("duplicate receipt err = %v, want ErrReceiptExists", err)
+		}
+
+		waive := models.FineEntry{UserID: user.ID, Amount: 5000, Reason: "Buku rusak bukan karena peminjam"}
+		if err := s.WaiveFine(&waive); err != nil {
+			t.Fatalf("waive: %v", err)
+		}
+		balance, err := s.GetFineBalance(user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Charged != 15000 || balance.Paid != 10000 || balance.Waived != 5000 || balance.Outstanding != 0 {
+			t.Fatalf("balance = %+v, want charged 15000 paid 10000 waived 5000 outstanding 0", balance)
+		}
+
+		entries, err := s.GetFineEntries(user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 3 {
+			t.Fatalf("got %d ledger entries, want 3", len(entries))
+		}
+	})
+}
+
+// Pembayaran per pinjaman tidak boleh melunasi anggota yang saldonya sudah nol, walaupun
+// saldo pinjaman tersebut (tanpa pembayaran umum) masih tersisa.
+func TestFinePaymentChecksMemberBalance(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		user := createTestUser(t, s, "dodi")
+		book := createTestBook(t, s, "Ronggeng Dukuh Paruk", 1)
+		loan := returnLate(t, s, user, book, 2) // Rp 10.000
+
+		general := models.FineEntry{UserID: user.ID, Amount: 10000, Method: "cash"}
+		if err := s.RecordFinePayment(&general); err != nil {
+			t.Fatalf("general payment: %v", err)
+		}
+		perLoan := models.FineEntry{UserID: user.ID, LoanID: loan.ID, Amount: 10000, Method: "cash"}
+		if err := s.RecordFinePayment(&perLoan); !errors.Is(err, ErrExceedsBalance) {
+			t.Fatalf("loan payment after settling member err = %v, want ErrExceedsBalance", err)
+		}
+		if got := fineOutstanding(t, s, user.ID); got != 0 {
+			t.Fatalf("outstanding = %d, want 0", got)
+		}
+	})
+}
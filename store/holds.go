@@ -0,0 +1,49 @@
+package store
+
+import (
+	"fmt"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// ProcessHoldQueue menyisihkan eksemplar yang tersedia untuk antrean reservasi buku
+// (bookID 0 berarti semua buku) lalu memberi tahu setiap anggota yang reservasinya siap diambil.
+// Dipanggil setiap kali ada eksemplar yang kembali tersedia.
+func ProcessHoldQueue(s Store, bookID int) ([]models.Hold, error) {
+	ready, err := s.AssignHolds(bookID)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range ready {
+		msg := fmt.Sprintf("RESERVASI: Buku '%s' siap diambil di perpustakaan. Batas pengambilan: %s.",
+			holdTitle(h), h.ExpiresAt.Format("02 Jan 2006 15:04"))
+		s.CreateNotification(h.UserID, NotifyHold, SeverityInfo, msg)
+	}
+	return ready, nil
+}
+
+// ExpireHoldQueue mengakhiri reservasi yang tidak diambil sampai batas waktunya,
+// memberi tahu pemiliknya, lalu meneruskan eksemplarnya ke antrean berikutnya.
+func ExpireHoldQueue(s Store, now time.Time) ([]models.Hold, error) {
+	expired, err := s.ExpireHolds(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range expired {
+		msg := fmt.Sprintf("Reservasi buku '%s' kedaluwarsa karena tidak diambil sampai %s.",
+			holdTitle(h), h.ExpiresAt.Format("02 Jan 2006 15:04"))
+		s.CreateNotification(h.UserID, NotifyHold, SeverityWarning, msg)
+	}
+	if _, err := ProcessHoldQueue(s, 0); err != nil {
+		return expired, err
+	}
+	return expired, nil
+}
+
+// holdTitle mengembalikan judul buku reservasi untuk pesan notifikasi.
+func holdTitle(h models.Hold) string {
+	if h.Book != nil {
+		return h.Book.Title
+	}
+	return "Buku"
+}
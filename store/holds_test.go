@@ -0,0 +1,90 @@
+package store
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBorrowHoldReturn(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ani := createTestUser(t, s, "ani")
+		budi := createTestUser(t, s, "budi")
+		book := createTestBook(t, s, "Laskar Pelangi", 1)
+
+		loan, err := s.BorrowBook(ani.ID, book.ID, 7)
+		if err != nil {
+			t.Fatalf("borrow: %v", err)
+		}
+		if loan.Status != LoanBorrowed || loan.ItemID == 0 {
+			t.Fatalf("loan status=%s item=%d", loan.Status, loan.ItemID)
+		}
+		if got := bookStock(t, s, book.ID); got != 0 {
+			t.Fatalf("stock after borrow = %d, want 0", got)
+		}
+		if _, err := s.BorrowBook(budi.ID, book.ID, 7); !errors.Is(err, ErrOutOfStock) {
+			t.Fatalf("borrow out of stock err = %v, want ErrOutOfStock", err)
+		}
+		if _, err := s.PlaceHold(ani.ID, book.ID); !errors.Is(err, ErrAlreadyBorrowing) {
+			t.Fatalf("hold by borrower err = %v, want ErrAlreadyBorrowing", err)
+		}
+
+		hold, err := s.PlaceHold(budi.ID, book.ID)
+		if err != nil {
+			t.Fatalf("place hold: %v", err)
+		}
+		if hold.Status != HoldWaiting {
+			t.Fatalf("hold status = %s, want %s", hold.Status, HoldWaiting)
+		}
+		if _, err := s.PlaceHold(budi.ID, book.ID); !errors.Is(err, ErrHoldExists) {
+			t.Fatalf("second hold err = %v, want ErrHoldExists", err)
+		}
+
+		returned, err := s.ReturnBook(loan.ID)
+		if err != nil {
+			t.Fatalf("return: %v", err)
+		}
+		if returned.Status != LoanReturned || returned.Fine != 0 || returned.ReturnDate == nil {
+			t.Fatalf("returned loan status=%s fine=%d return_date=%v", returned.Status, returned.Fine, returned.ReturnDate)
+		}
+		if _, err := s.ReturnBook(loan.ID); !errors.Is(err, ErrAlreadyReturned) {
+			t.Fatalf("second return err = %v, want ErrAlreadyReturned", err)
+		}
+		// Eksemplar yang kembali menjadi hak antrean reservasi
+		if _, err := s.BorrowBook(ani.ID, book.ID, 7); !errors.Is(err, ErrOutOfStock) {
+			t.Fatalf("borrow with waiting hold err = %v, want ErrOutOfStock", err)
+		}
+
+		ready, err := s.AssignHolds(book.ID)
+		if err != nil {
+			t.Fatalf("assign holds: %v", err)
+		}
+		if len(ready) != 1 || ready[0].ID != hold.ID || ready[0].Status != HoldReady || ready[0].ItemID != loan.ItemID {
+			t.Fatalf("assigned holds = %+v, want hold %d ready with item %d", ready, hold.ID, loan.ItemID)
+		}
+		if got := bookStock(t, s, book.ID); got != 0 {
+			t.Fatalf("stock with reserved item = %d, want 0", got)
+		}
+
+		loan2, err := s.BorrowBook(budi.ID, book.ID, 7)
+		if err != nil {
+			t.Fatalf("borrow reserved item: %v", err)
+		}
+		if loan2.ItemID != loan.ItemID {
+			t.Fatalf("borrowed item %d, want reserved item %d", loan2.ItemID, loan.ItemID)
+		}
+		fulfilled, err := s.GetHoldByID(hold.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fulfilled.Status != HoldFulfilled {
+			t.Fatalf("hold status after pickup = %s, want %s", fulfilled.Status, HoldFulfilled)
+		}
+
+		if _, err := s.ReturnBook(loan2.ID); err != nil {
+			t.Fatalf("return second loan: %v", err)
+		}
+		if got := bookStock(t, s, book.ID); got != 1 {
+			t.Fatalf("stock after all returns = %d, want 1", got)
+		}
+	})
+}
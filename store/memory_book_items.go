@@ -0,0 +1,173 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// syncStock menyamakan Book.Stock dengan jumlah eksemplar tersedia.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) syncStock(bookID int) {
+	b, ok := s.books[bookID]
+	if !ok {
+		return
+	}
+	b.Stock = 0
+	for _, item := range s.items {
+		if item.BookID == bookID && item.Status == ItemAvailable {
+			b.Stock++
+		}
+	}
+}
+
+// insertItem menambah eksemplar dengan nomor urut berikutnya untuk bukunya.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) insertItem(item *models.BookItem) error {
+	item.CopyNo = 1
+	for _, it := range s.items {
+		if it.BookID == item.BookID && it.CopyNo >= item.CopyNo {
+			item.CopyNo = it.CopyNo + 1
+		}
+	}
+	if item.Barcode == "" {
+		item.Barcode = GenerateBarcode(item.BookID, item.CopyNo)
+	}
+	if item.Condition == "" {
+		item.Condition = ConditionGood
+	}
+	if item.Status == "" {
+		item.Status = ItemAvailable
+	}
+	for _, it := range s.items {
+		if it.Barcode == item.Barcode {
+			return ErrBarcodeExists
+		}
+	}
+
+	s.nextItemID++
+	item.ID = s.nextItemID
+	item.CreatedAt = time.Now()
+	cp := *item
+	cp.Book = nil
+	s.items[item.ID] = &cp
+	return nil
+}
+
+// itemWithBook mengembalikan salinan eksemplar beserta judul bukunya.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) itemWithBook(item *models.BookItem) *models.BookItem {
+	cp := *item
+	if b, ok := s.books[item.BookID]; ok {
+		cp.Book = &models.Book{ID: b.ID, Title: b.Title}
+	}
+	return &cp
+}
+
+// CreateBookItem menambahkan eksemplar baru untuk sebuah buku.
+func (s *MemoryStore) CreateBookItem(item *models.BookItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.books[item.BookID]; !ok {
+		return ErrBookNotFound
+	}
+	if err := s.insertItem(item); err != nil {
+		return err
+	}
+	s.syncStock(item.BookID)
+	return nil
+}
+
+// GetBookItems mengambil semua eksemplar milik sebuah buku, diurutkan berdasarkan nomor eksemplar.
+func (s *MemoryStore) GetBookItems(bookID int) ([]models.BookItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []models.BookItem
+	for _, item := range s.items {
+		if item.BookID == bookID {
+			items = append(items, *s.itemWithBook(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CopyNo < items[j].CopyNo })
+	return items, nil
+}
+
+// GetBookItemByID mengambil detail eksemplar berdasarkan ID.
+func (s *MemoryStore) GetBookItemByID(id int) (*models.BookItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+	return s.itemWithBook(item), nil
+}
+
+// GetBookItemByBarcode mengambil detail eksemplar berdasarkan barcode.
+func (s *MemoryStore) GetBookItemByBarcode(barcode string) (*models.BookItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range s.items {
+		if item.Barcode == barcode {
+			return s.itemWithBook(item), nil
+		}
+	}
+	return nil, ErrItemNotFound
+}
+
+// UpdateBookItem memperbarui barcode, nomor induk, kondisi, lokasi rak, dan status eksemplar.
+// Status "borrowed" dan "reserved" hanya diatur oleh proses peminjaman dan reservasi.
+func (s *MemoryStore) UpdateBookItem(item *models.BookItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.items[item.ID]
+	if !ok {
+		return ErrItemNotFound
+	}
+	if err := checkItemStatusChange(current.Status, item.Status); err != nil {
+		return err
+	}
+	for _, it := range s.items {
+		if it.ID != item.ID && it.Barcode == item.Barcode {
+			return ErrBarcodeExists
+		}
+	}
+
+	current.Barcode = item.Barcode
+	current.AccessionNumber = item.AccessionNumber
+	current.Condition = item.Condition
+	current.ShelfLocation = item.ShelfLocation
+	current.Status = item.Status
+	s.syncStock(current.BookID)
+	return nil
+}
+
+// DeleteBookItem menghapus eksemplar yang tidak sedang dipinjam dan tidak memiliki histori pinjaman.
+func (s *MemoryStore) DeleteBookItem(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return ErrItemNotFound
+	}
+	if item.Status == ItemBorrowed {
+		return ErrItemBorrowed
+	}
+	if item.Status == ItemReserved {
+		return ErrItemReserved
+	}
+	for _, l := range s.loans {
+		if l.ItemID == id {
+			return ErrItemHasLoans
+		}
+	}
+	delete(s.items, id)
+	s.syncStock(item.BookID)
+	return nil
+}
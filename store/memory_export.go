@@ -0,0 +1,38 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"time"
+)
+
+// each memanggil fn untuk setiap elemen hasil Get*. Data MemoryStore memang sudah berada di
+// memori; fn dipanggil pada salinan di luar kunci sehingga bebas memanggil store kembali.
+func each[T any](items []T, err error, fn func(*T) error) error {
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachLoan membaca pinjaman dengan tanggal pinjam dalam rentang (inklusif) satu per satu.
+func (s *MemoryStore) EachLoan(startDate, endDate time.Time, fn func(*models.Loan) error) error {
+	loans, err := s.GetLoansFiltered(startDate, endDate)
+	return each(loans, err, fn)
+}
+
+// EachUser membaca semua pengguna satu per satu.
+func (s *MemoryStore) EachUser(fn func(*models.User) error) error {
+	users, err := s.GetAllUsers()
+	return each(users, err, fn)
+}
+
+// EachBook membaca semua buku satu per satu.
+func (s *MemoryStore) EachBook(fn func(*models.Book) error) error {
+	books, err := s.GetAllBooks()
+	return each(books, err, fn)
+}
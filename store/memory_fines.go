@@ -0,0 +1,208 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// addFineEntry menyimpan entri buku besar denda baru. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) addFineEntry(e *models.FineEntry) {
+	s.nextFineID++
+	e.ID = s.nextFineID
+	cp := *e
+	s.fines[e.ID] = &cp
+}
+
+// fineBalance menghitung saldo denda user (atau satu pinjamannya jika loanID bukan 0).
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) fineBalance(userID string, loanID int) *models.FineBalance {
+	b := &models.FineBalance{UserID: userID}
+	if u, ok := s.users[userID]; ok {
+		b.Username = u.Username
+		b.Fullname = u.Fullname
+	}
+	for _, f := range s.fines {
+		if f.UserID != userID || (loanID != 0 && f.LoanID != loanID) {
+			continue
+		}
+		switch f.Type {
+		case FineCharge:
+			b.Charged += f.Amount
+		case FinePayment:
+			b.Paid += f.Amount
+		case FineWaiver:
+			b.Waived += f.Amount
+		}
+	}
+	b.Outstanding = b.Charged - b.Paid - b.Waived
+	return b
+}
+
+// GetFineEntries mengambil entri buku besar denda milik user, terbaru lebih dulu.
+// userID kosong berarti semua anggota.
+func (s *MemoryStore) GetFineEntries(userID string) ([]models.FineEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []models.FineEntry
+	for _, f := range s.fines {
+		if userID != "" && f.UserID != userID {
+			continue
+		}
+		if cp, ok := s.fineEntryView(f); ok {
+			entries = append(entries, *cp)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	return entries, nil
+}
+
+// GetFineEntry mengambil satu entri buku besar denda beserta data anggota dan bukunya.
+func (s *MemoryStore) GetFineEntry(id int) (*models.FineEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.fines[id]
+	if !ok {
+		return nil, ErrFineNotFound
+	}
+	cp, ok := s.fineEntryView(f)
+	if !ok {
+		return nil, ErrFineNotFound
+	}
+	return cp, nil
+}
+
+// fineEntryView menyalin entri denda beserta data anggota, judul buku dan pencatatnya
+// (seperti hasil join SQL). false jika anggotanya sudah tidak ada. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) fineEntryView(f *models.FineEntry) (*models.FineEntry, bool) {
+	u, ok := s.users[f.UserID]
+	if !ok {
+		return nil, false
+	}
+	cp := *f
+	cp.User = &models.User{ID: u.ID, Username: u.Username, Fullname: u.Fullname}
+	if l, ok := s.loans[f.LoanID]; ok {
+		if b, ok := s.books[l.BookID]; ok {
+			cp.Book = &models.Book{ID: b.ID, Title: b.Title}
+		}
+	}
+	if r, ok := s.users[f.RecordedBy]; ok {
+		cp.RecordedByName = r.Username
+	}
+	return &cp, true
+}
+
+// GetFineBalance mengambil ringkasan saldo denda seorang anggota.
+func (s *MemoryStore) GetFineBalance(userID string) (*models.FineBalance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, ErrUserNotFound
+	}
+	b := s.fineBalance(userID, 0)
+	b.Accruing = s.accruingFine(userID)
+	return b, nil
+}
+
+// accruingFine menjumlahkan denda berjalan pinjaman user yang belum dikembalikan.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) accruingFine(userID string) int {
+	total := 0
+	for _, l := range s.loans {
+		if l.UserID == userID && IsOpenLoan(l.Status) {
+			total += l.AccruedFine
+		}
+	}
+	return total
+}
+
+// GetOutstandingBalances mengambil anggota yang masih memiliki denda tertunggak atau denda
+// berjalan, tunggakan terbesar lebih dulu.
+func (s *MemoryStore) GetOutstandingBalances() ([]models.FineBalance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userIDs := make(map[string]bool)
+	for _, f := range s.fines {
+		userIDs[f.UserID] = true
+	}
+	for _, l := range s.loans {
+		if l.AccruedFine > 0 && IsOpenLoan(l.Status) {
+			userIDs[l.UserID] = true
+		}
+	}
+
+	var balances []models.FineBalance
+	for userID := range userIDs {
+		if _, ok := s.users[userID]; !ok {
+			continue
+		}
+		b := s.fineBalance(userID, 0)
+		b.Accruing = s.accruingFine(userID)
+		if b.Outstanding > 0 || b.Accruing > 0 {
+			balances = append(balances, *b)
+		}
+	}
+	sortBalances(balances)
+	return balances, nil
+}
+
+// RecordFinePayment mencatat pembayaran denda (penuh atau sebagian) beserta nomor kuitansi.
+func (s *MemoryStore) RecordFinePayment(entry *models.FineEntry) error {
+	entry.Type = FinePayment
+	return s.recordFineCredit(entry)
+}
+
+// WaiveFine mencatat pembebasan denda (penuh atau sebagian) beserta alasannya.
+func (s *MemoryStore) WaiveFine(entry *models.FineEntry) error {
+	entry.Type = FineWaiver
+	entry.Method = ""
+	entry.ReceiptNo = ""
+	return s.recordFineCredit(entry)
+}
+
+// recordFineCredit menyimpan pembayaran atau pembebasan setelah memeriksa saldo tertunggak.
+func (s *MemoryStore) recordFineCredit(entry *models.FineEntry) error {
+	if entry.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[entry.UserID]; !ok {
+		return ErrUserNotFound
+	}
+	if entry.LoanID != 0 {
+		if l, ok := s.loans[entry.LoanID]; !ok || l.UserID != entry.UserID {
+			return ErrLoanNotFound
+		}
+	}
+	if entry.ReceiptNo != "" {
+		for _, f := range s.fines {
+			if f.ReceiptNo == entry.ReceiptNo {
+				return ErrReceiptExists
+			}
+		}
+	}
+	// Saldo anggota selalu diperiksa; pembayaran per pinjaman juga dibatasi saldo pinjamannya
+	if entry.Amount > s.fineBalance(entry.UserID, 0).Outstanding {
+		return ErrExceedsBalance
+	}
+	if entry.LoanID != 0 && entry.Amount > s.fineBalance(entry.UserID, entry.LoanID).Outstanding {
+		return ErrExceedsBalance
+	}
+
+	entry.CreatedAt = time.Now()
+	s.nextFineID++
+	entry.ID = s.nextFineID
+	if entry.Type == FinePayment && entry.ReceiptNo == "" {
+		entry.ReceiptNo = GenerateReceiptNo(entry.CreatedAt, entry.ID)
+	}
+	cp := *entry
+	s.fines[entry.ID] = &cp
+	return nil
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// holdWithRefs mengembalikan salinan reservasi beserta username, judul buku, barcode,
+// dan posisi antrean. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) holdWithRefs(h *models.Hold) models.Hold {
+	cp := *h
+	if u, ok := s.users[h.UserID]; ok {
+		cp.User = &models.User{ID: u.ID, Username: u.Username, Fullname: u.Fullname}
+	}
+	if b, ok := s.books[h.BookID]; ok {
+		cp.Book = &models.Book{ID: b.ID, Title: b.Title}
+	}
+	if item, ok := s.items[h.ItemID]; ok {
+		cp.Item = &models.BookItem{ID: item.ID, BookID: item.BookID, Barcode: item.Barcode}
+	}
+	if h.Status == HoldWaiting {
+		for _, w := range s.holds {
+			if w.BookID == h.BookID && w.Status == HoldWaiting && w.ID <= h.ID {
+				cp.Position++
+			}
+		}
+	}
+	return cp
+}
+
+// filterHolds mengembalikan salinan reservasi yang lolos filter. Seperti JOIN pada SQLStore,
+// reservasi tanpa user/buku dilewati. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) filterHolds(keep func(*models.Hold) bool) []models.Hold {
+	var holds []models.Hold
+	for _, h := range s.holds {
+		if !keep(h) {
+			continue
+		}
+		if _, ok := s.users[h.UserID]; !ok {
+			continue
+		}
+		if _, ok := s.books[h.BookID]; !ok {
+			continue
+		}
+		holds = append(holds, s.holdWithRefs(h))
+	}
+	return holds
+}
+
+// releaseHoldItem mengembalikan eksemplar yang disisihkan untuk reservasi "ready" ke rak.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) releaseHoldItem(h *models.Hold) {
+	if h.Status != HoldReady {
+		return
+	}
+	if item, ok := s.items[h.ItemID]; ok && item.Status == ItemReserved {
+		item.Status = ItemAvailable
+		s.syncStock(h.BookID)
+	}
+}
+
+// PlaceHold menambahkan user ke antrean reservasi sebuah buku.
+func (s *MemoryStore) PlaceHold(userID string, bookID int) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.books[bookID]
+	if !ok {
+		return nil, ErrBookNotFound
+	}
+	if _, ok := s.users[userID]; !ok {
+		return nil, ErrUserNotFound
+	}
+
+	waiting := 0
+	for _, h := range s.holds {
+		if h.BookID != bookID {
+			continue
+		}
+		if h.UserID == userID && (h.Status == HoldWaiting || h.Status == HoldReady) {
+			return nil, ErrHoldExists
+		}
+		if h.Status == HoldWaiting {
+			waiting++
+		}
+	}
+	for _, l := range s.loans {
+		if l.UserID == userID && l.BookID == bookID && IsOpenLoan(l.Status) {
+			return nil, ErrAlreadyBorrowing
+		}
+	}
+	if b.Stock > 0 && waiting == 0 {
+		return nil, ErrBookAvailable
+	}
+
+	s.nextHoldID++
+	h := &models.Hold{
+		ID:        s.nextHoldID,
+		UserID:    userID,
+		BookID:    bookID,
+		Status:    HoldWaiting,
+		CreatedAt: time.Now(),
+	}
+	s.holds[h.ID] = h
+
+	cp := s.holdWithRefs(h)
+	return &cp, nil
+}
+
+// GetHoldByID mengambil detail reservasi berdasarkan ID.
+func (s *MemoryStore) GetHoldByID(id int) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holds := s.filterHolds(func(h *models.Hold) bool { return h.ID == id })
+	if len(holds) == 0 {
+		return nil, ErrHoldNotFound
+	}
+	return &holds[0], nil
+}
+
+// GetHoldsByUser mengambil seluruh reservasi milik user, terbaru lebih dulu.
+func (s *MemoryStore) GetHoldsByUser(userID string) ([]models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holds := s.filterHolds(func(h *models.Hold) bool { return h.UserID == userID })
+	sort.Slice(holds, func(i, j int) bool { return holds[i].ID > holds[j].ID })
+	return holds, nil
+}
+
+// GetActiveHolds mengambil reservasi aktif (ready lalu waiting, urut antrean).
+// bookID 0 berarti semua buku.
+func (s *MemoryStore) GetActiveHolds(bookID int) ([]models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holds := s.filterHolds(func(h *models.Hold) bool {
+		return (bookID == 0 || h.BookID == bookID) && (h.Status == HoldWaiting || h.Status == HoldReady)
+	})
+	sort.Slice(holds, func(i, j int) bool {
+		a, b := holds[i], holds[j]
+		if a.Book.Title != b.Book.Title {
+			return a.Book.Title < b.Book.Title
+		}
+		if (a.Status == HoldReady) != (b.Status == HoldReady) {
+			return a.Status == HoldReady
+		}
+		return a.ID < b.ID
+	})
+	return holds, nil
+}
+
+// CancelHold membatalkan reservasi aktif dan mengembalikan eksemplar yang disisihkan ke rak.
+func (s *MemoryStore) CancelHold(id int) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.holds[id]
+	if !ok {
+		return nil, ErrHoldNotFound
+	}
+	if err := s.closeHold(h, HoldCancelled, time.Now()); err != nil {
+		return nil, err
+	}
+	cp := s.holdWithRefs(h)
+	return &cp, nil
+}
+
+// closeHold menutup reservasi aktif dengan status tertentu. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) closeHold(h *models.Hold, status string, now time.Time) error {
+	if h.Status != HoldWaiting && h.Status != HoldReady {
+		return ErrHoldClosed
+	}
+	s.releaseHoldItem(h)
+	h.Status = status
+	h.ClosedAt = &now
+	return nil
+}
+
+// AssignHolds menyisihkan eksemplar tersedia untuk antrean terdepan (FIFO).
+// bookID 0 berarti semua buku yang memiliki antrean. Mengembalikan reservasi yang baru siap.
+func (s *MemoryStore) AssignHolds(bookID int) ([]models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pickupDays := DefaultSettings().HoldPickupDays
+	if s.settings != nil {
+		pickupDays = s.settings.HoldPickupDays
+	}
+
+	var waiting []*models.Hold
+	for _, h := range s.holds {
+		if h.Status == HoldWaiting && (bookID == 0 || h.BookID == bookID) {
+			waiting = append(waiting, h)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool { return waiting[i].ID < waiting[j].ID })
+
+	var ready []models.Hold
+	for _, h := range waiting {
+		var item *models.BookItem
+		for _, it := range s.items {
+			if it.BookID == h.BookID && it.Status == ItemAvailable && (item == nil || it.CopyNo < item.CopyNo) {
+				item = it
+			}
+		}
+		if item == nil {
+			continue
+		}
+
+		item.Status = ItemReserved
+		s.syncStock(h.BookID)
+		readyAt := time.Now()
+		expiresAt := readyAt.AddDate(0, 0, pickupDays)
+		h.Status = HoldReady
+		h.ItemID = item.ID
+		h.ReadyAt = &readyAt
+		h.ExpiresAt = &expiresAt
+		ready = append(ready, s.holdWithRefs(h))
+	}
+	return ready, nil
+}
+
+// ExpireHolds menandai reservasi "ready" yang melewati batas pengambilan sebagai "expired".
+func (s *MemoryStore) ExpireHolds(now time.Time) ([]models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []models.Hold
+	for _, h := range s.holds {
+		if h.Status == HoldReady && h.ExpiresAt != nil && h.ExpiresAt.Before(now) {
+			s.closeHold(h, HoldExpired, now)
+			expired = append(expired, s.holdWithRefs(h))
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].ID < expired[j].ID })
+	return expired, nil
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+)
+
+// calendar menyusun kalender dari pengaturan dan tanggal libur. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) calendar() *Calendar {
+	closed := 0
+	if s.settings != nil {
+		closed = s.settings.ClosedWeekdays
+	}
+	cal := NewCalendar(closed, nil)
+	for _, h := range s.holidays {
+		cal.Holidays[h.Date] = true
+	}
+	return cal
+}
+
+// holidayExists memeriksa apakah tanggal sudah terdaftar. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) holidayExists(date string) bool {
+	for _, h := range s.holidays {
+		if h.Date == date {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHolidays mengambil semua tanggal libur, urut tanggal.
+func (s *MemoryStore) GetHolidays() ([]models.Holiday, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var holidays []models.Holiday
+	for _, h := range s.holidays {
+		holidays = append(holidays, *h)
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+	return holidays, nil
+}
+
+// CreateHoliday menambahkan satu tanggal libur.
+func (s *MemoryStore) CreateHoliday(h *models.Holiday) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.holidayExists(h.Date) {
+		return ErrHolidayExists
+	}
+	s.nextHolidayID++
+	h.ID = s.nextHolidayID
+	cp := *h
+	s.holidays[h.ID] = &cp
+	return nil
+}
+
+// DeleteHoliday menghapus tanggal libur.
+func (s *MemoryStore) DeleteHoliday(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.holidays[id]; !ok {
+		return ErrHolidayNotFound
+	}
+	delete(s.holidays, id)
+	return nil
+}
+
+// ImportHolidays menambahkan banyak tanggal libur sekaligus; tanggal yang sudah ada dilewati.
+func (s *MemoryStore) ImportHolidays(holidays []models.Holiday) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := 0
+	for _, h := range holidays {
+		if s.holidayExists(h.Date) {
+			continue
+		}
+		s.nextHolidayID++
+		s.holidays[s.nextHolidayID] = &models.Holiday{ID: s.nextHolidayID, Date: h.Date, Name: h.Name}
+		added++
+	}
+	return added, nil
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+)
+
+// StartJobRun mengklaim slot jadwal; ErrJobClaimed jika slot job tersebut sudah tercatat.
+func (s *MemoryStore) StartJobRun(run *models.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.jobRuns {
+		if r.JobName == run.JobName && r.ScheduledAt.Equal(run.ScheduledAt) {
+			return ErrJobClaimed
+		}
+	}
+
+	s.nextJobRunID++
+	run.ID = s.nextJobRunID
+	run.Status = JobRunning
+	cp := *run
+	s.jobRuns[cp.ID] = &cp
+	return nil
+}
+
+// FinishJobRun mencatat hasil run dan memperbarui ringkasan run terakhir job tersebut.
+func (s *MemoryStore) FinishJobRun(run *models.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.jobRuns[run.ID]
+	if !ok {
+		return ErrJobRunNotFound
+	}
+	r.Status = run.Status
+	r.FinishedAt = run.FinishedAt
+	r.DurationMs = run.DurationMs
+	r.Error = run.Error
+
+	startedAt := run.StartedAt
+	s.jobs[run.JobName] = &models.Job{
+		Name:           run.JobName,
+		LastRunAt:      &startedAt,
+		LastStatus:     run.Status,
+		LastDurationMs: run.DurationMs,
+		LastError:      run.Error,
+	}
+	return nil
+}
+
+// GetJobs mengambil ringkasan run terakhir semua job yang pernah dijalankan.
+func (s *MemoryStore) GetJobs() ([]models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []models.Job
+	for _, j := range s.jobs {
+		jobs = append(jobs, *j)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+// GetJobRuns mengambil riwayat run terbaru, difilter nama job jika tidak kosong.
+func (s *MemoryStore) GetJobRuns(name string, limit int) ([]models.JobRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var runs []models.JobRun
+	for _, r := range s.jobRuns {
+		if name == "" || r.JobName == name {
+			runs = append(runs, *r)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].StartedAt.After(runs[j].StartedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"time"
+)
+
+// GetLetterhead mengambil kop surat; DefaultLetterhead jika belum pernah diatur.
+func (s *MemoryStore) GetLetterhead() (*models.Letterhead, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.letterhead == nil {
+		return DefaultLetterhead(), nil
+	}
+	cp := *s.letterhead
+	return &cp, nil
+}
+
+// UpdateLetterhead menyimpan kop surat dan mengisi lh.UpdatedAt.
+func (s *MemoryStore) UpdateLetterhead(lh *models.Letterhead) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lh.UpdatedAt = time.Now()
+	cp := *lh
+	s.letterhead = &cp
+	return nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"strings"
+)
+
+// policyList mengembalikan salinan seluruh kebijakan, diurutkan per role lalu kategori.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) policyList() []models.LoanPolicy {
+	var policies []models.LoanPolicy
+	for _, p := range s.policies {
+		policies = append(policies, *p)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Role != policies[j].Role {
+			return policies[i].Role < policies[j].Role
+		}
+		return strings.ToLower(policies[i].Category) < strings.ToLower(policies[j].Category)
+	})
+	return policies
+}
+
+// policyExists meniru unique key (role, category); kategori dibandingkan tanpa peduli huruf besar.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) policyExists(role, category string, exceptID int) bool {
+	for _, p := range s.policies {
+		if p.ID != exceptID && p.Role == role && strings.EqualFold(p.Category, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLoanPolicies mengambil semua kebijakan peminjaman.
+func (s *MemoryStore) GetLoanPolicies() ([]models.LoanPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.policyList(), nil
+}
+
+// GetLoanPolicyByID mengambil satu kebijakan peminjaman.
+func (s *MemoryStore) GetLoanPolicyByID(id int) (*models.LoanPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.policies[id]
+	if !ok {
+		return nil, ErrPolicyNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+// CreateLoanPolicy menambahkan kebijakan peminjaman baru.
+func (s *MemoryStore) CreateLoanPolicy(p *models.LoanPolicy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.policyExists(p.Role, p.Category, 0) {
+		return ErrPolicyExists
+	}
+	s.nextPolicyID++
+	p.ID = s.nextPolicyID
+	cp := *p
+	s.policies[p.ID] = &cp
+	return nil
+}
+
+// UpdateLoanPolicy memperbarui kebijakan peminjaman.
+func (s *MemoryStore) UpdateLoanPolicy(p *models.LoanPolicy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.policies[p.ID]; !ok {
+		return ErrPolicyNotFound
+	}
+	if s.policyExists(p.Role, p.Category, p.ID) {
+		return ErrPolicyExists
+	}
+	cp := *p
+	s.policies[p.ID] = &cp
+	return nil
+}
+
+// DeleteLoanPolicy menghapus kebijakan peminjaman.
+func (s *MemoryStore) DeleteLoanPolicy(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.policies[id]; !ok {
+		return ErrPolicyNotFound
+	}
+	delete(s.policies, id)
+	return nil
+}
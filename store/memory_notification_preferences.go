@@ -0,0 +1,37 @@
+package store
+
+import "latihan_cloud8/models"
+
+// GetNotificationPreferences mengambil preferensi untuk semua kategori notifikasi.
+func (s *MemoryStore) GetNotificationPreferences(userID string) ([]models.NotificationPreference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.notificationPreferences(userID), nil
+}
+
+// notificationPreferences melengkapi preferensi tersimpan dengan nilai bawaan. Pemanggil memegang s.mu.
+func (s *MemoryStore) notificationPreferences(userID string) []models.NotificationPreference {
+	var saved []models.NotificationPreference
+	for _, p := range s.notifPrefs[userID] {
+		saved = append(saved, p)
+	}
+	return mergeNotificationPreferences(saved)
+}
+
+// SetNotificationPreferences menyimpan preferensi; kategori yang tidak dikirim tidak diubah.
+func (s *MemoryStore) SetNotificationPreferences(userID string, prefs []models.NotificationPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return ErrInvalidReference
+	}
+	if s.notifPrefs[userID] == nil {
+		s.notifPrefs[userID] = make(map[string]models.NotificationPreference)
+	}
+	for _, p := range prefs {
+		s.notifPrefs[userID][p.Category] = models.NotificationPreference{Category: p.Category, Enabled: p.Enabled, Email: p.Email}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// EnqueueOutbox menyimpan pesan baru berstatus pending yang siap dikirim segera.
+func (s *MemoryStore) EnqueueOutbox(msg *models.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[msg.UserID]; !ok {
+		return ErrInvalidReference
+	}
+
+	s.nextOutboxID++
+	msg.ID = s.nextOutboxID
+	msg.Status = OutboxPending
+	msg.Attempts = 0
+	msg.CreatedAt = time.Now()
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = msg.CreatedAt
+	}
+	cp := *msg
+	s.outbox[cp.ID] = &cp
+	return nil
+}
+
+// GetDueOutbox mengambil pesan pending yang jadwal kirimnya sudah tiba, terlama lebih dulu,
+// termasuk pesan sending yang klaimnya sudah kedaluwarsa.
+func (s *MemoryStore) GetDueOutbox(now time.Time, limit int) ([]models.OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var msgs []models.OutboxMessage
+	for _, m := range s.outbox {
+		if (m.Status == OutboxPending || m.Status == OutboxSending) && !m.NextAttemptAt.After(now) {
+			msgs = append(msgs, *m)
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if !msgs[i].NextAttemptAt.Equal(msgs[j].NextAttemptAt) {
+			return msgs[i].NextAttemptAt.Before(msgs[j].NextAttemptAt)
+		}
+		return msgs[i].ID < msgs[j].ID
+	})
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+	return msgs, nil
+}
+
+// GetOutbox mengambil pesan terbaru, difilter status jika tidak kosong.
+func (s *MemoryStore) GetOutbox(status string, limit int) ([]models.OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var msgs []models.OutboxMessage
+	for _, m := range s.outbox {
+		if status == "" || m.Status == status {
+			msgs = append(msgs, *m)
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].ID > msgs[j].ID })
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+	return msgs, nil
+}
+
+// ClaimOutbox menandai pesan sending sebelum dikirim; false jika sudah diklaim.
+func (s *MemoryStore) ClaimOutbox(id int, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.outbox[id]
+	if !ok {
+		return false, ErrOutboxNotFound
+	}
+	if (m.Status != OutboxPending && m.Status != OutboxSending) || m.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	m.Status = OutboxSending
+	m.NextAttemptAt = now.Add(OutboxClaimTimeout)
+	return true, nil
+}
+
+// MarkOutboxSent menandai pesan berhasil terkirim.
+func (s *MemoryStore) MarkOutboxSent(id int, sentAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.outbox[id]
+	if !ok {
+		return ErrOutboxNotFound
+	}
+	m.Status = OutboxSent
+	m.Attempts++
+	m.LastError = ""
+	m.SentAt = &sentAt
+	return nil
+}
+
+// MarkOutboxFailed mencatat percobaan yang gagal; retryAt nil berarti pesan tidak dicoba lagi.
+func (s *MemoryStore) MarkOutboxFailed(id int, lastError string, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.outbox[id]
+	if !ok {
+		return ErrOutboxNotFound
+	}
+	m.Attempts++
+	m.LastError = lastError
+	if retryAt == nil {
+		m.Status = OutboxFailed
+	} else {
+		m.Status = OutboxPending
+		m.NextAttemptAt = *retryAt
+	}
+	return nil
+}
+
+// RetryOutbox menjadwalkan ulang pesan yang belum terkirim agar segera dikirim.
+// Jumlah percobaan direset sehingga pesan mendapat jatah percobaan penuh lagi.
+func (s *MemoryStore) RetryOutbox(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.outbox[id]
+	if !ok {
+		return ErrOutboxNotFound
+	}
+	if m.Status == OutboxSent {
+		return ErrOutboxSent
+	}
+	m.Status = OutboxPending
+	m.Attempts = 0
+	m.NextAttemptAt = time.Now()
+	return nil
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// RenewLoan memperpanjang jatuh tempo pinjaman aktif dan mencatatnya di riwayat perpanjangan.
+func (s *MemoryStore) RenewLoan(loanID int, renewedBy string, days, maxRenewals int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.loans[loanID]
+	if !ok {
+		return nil, ErrLoanNotFound
+	}
+
+	now := time.Now()
+	if l.Status == LoanReturned {
+		return nil, ErrAlreadyReturned
+	}
+	if now.After(l.DueDate) {
+		return nil, ErrLoanOverdue
+	}
+	if l.Renewals >= maxRenewals {
+		return nil, ErrRenewalLimit
+	}
+	for _, h := range s.holds {
+		if h.BookID == l.BookID && h.Status == HoldWaiting {
+			return nil, ErrHoldsPending
+		}
+	}
+
+	newDue := s.calendar().DueDate(l.DueDate, days)
+	s.nextRenewalID++
+	s.renewals[s.nextRenewalID] = &models.LoanRenewal{
+		ID:         s.nextRenewalID,
+		LoanID:     loanID,
+		RenewedBy:  renewedBy,
+		OldDueDate: l.DueDate,
+		NewDueDate: newDue,
+		RenewedAt:  now,
+	}
+	l.DueDate = newDue
+	l.Renewals++
+
+	loans := s.filterLoans(func(x *models.Loan) bool { return x.ID == loanID })
+	if len(loans) == 0 {
+		return nil, ErrLoanNotFound
+	}
+	return &loans[0], nil
+}
+
+// GetLoanRenewals mengambil riwayat perpanjangan sebuah pinjaman, terlama lebih dulu.
+func (s *MemoryStore) GetLoanRenewals(loanID int) ([]models.LoanRenewal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var renewals []models.LoanRenewal
+	for _, r := range s.renewals {
+		if r.LoanID != loanID {
+			continue
+		}
+		cp := *r
+		if u, ok := s.users[r.RenewedBy]; ok {
+			cp.RenewedName = u.Username
+		}
+		renewals = append(renewals, cp)
+	}
+	sort.Slice(renewals, func(i, j int) bool { return renewals[i].ID < renewals[j].ID })
+	return renewals, nil
+}
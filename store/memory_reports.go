@@ -0,0 +1,222 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// GetLoanVolume menghitung jumlah peminjaman dan pengembalian per periode.
+func (s *MemoryStore) GetLoanVolume(r models.ReportRange, interval string) ([]models.LoanVolume, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var loanDates, returnDates []time.Time
+	for _, l := range s.loans {
+		if inRange(r, l.LoanDate) {
+			loanDates = append(loanDates, l.LoanDate)
+		}
+		if l.ReturnDate != nil && inRange(r, *l.ReturnDate) {
+			returnDates = append(returnDates, *l.ReturnDate)
+		}
+	}
+	return loanVolume(r, interval, loanDates, returnDates), nil
+}
+
+// loansInRange mengambil pinjaman yang dibuat dalam rentang. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) loansInRange(r models.ReportRange) []*models.Loan {
+	var loans []*models.Loan
+	for _, l := range s.loans {
+		if inRange(r, l.LoanDate) {
+			loans = append(loans, l)
+		}
+	}
+	return loans
+}
+
+// GetTopTitles mengambil judul yang paling sering dipinjam dalam rentang.
+func (s *MemoryStore) GetTopTitles(r models.ReportRange, limit int) ([]models.TitleCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[int]*models.TitleCount)
+	for _, l := range s.loansInRange(r) {
+		b, ok := s.books[l.BookID]
+		if !ok {
+			continue
+		}
+		t, ok := counts[b.ID]
+		if !ok {
+			t = &models.TitleCount{BookID: b.ID, Title: b.Title, Author: b.Author, Category: b.Category}
+			counts[b.ID] = t
+		}
+		t.Loans++
+	}
+
+	titles := []models.TitleCount{}
+	for _, t := range counts {
+		titles = append(titles, *t)
+	}
+	sort.Slice(titles, func(i, j int) bool {
+		if titles[i].Loans != titles[j].Loans {
+			return titles[i].Loans > titles[j].Loans
+		}
+		return titles[i].Title < titles[j].Title
+	})
+	if len(titles) > limit {
+		titles = titles[:limit]
+	}
+	return titles, nil
+}
+
+// GetTopCategories mengambil kategori yang paling sering dipinjam dalam rentang.
+func (s *MemoryStore) GetTopCategories(r models.ReportRange, limit int) ([]models.CategoryCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, l := range s.loansInRange(r) {
+		if b, ok := s.books[l.BookID]; ok {
+			counts[b.Category]++
+		}
+	}
+
+	categories := []models.CategoryCount{}
+	for name, n := range counts {
+		categories = append(categories, models.CategoryCount{Category: name, Loans: n})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Loans != categories[j].Loans {
+			return categories[i].Loans > categories[j].Loans
+		}
+		return categories[i].Category < categories[j].Category
+	})
+	if len(categories) > limit {
+		categories = categories[:limit]
+	}
+	return categories, nil
+}
+
+// GetTopBorrowers mengambil anggota dengan peminjaman terbanyak dalam rentang beserta
+// total denda final pinjaman tersebut.
+func (s *MemoryStore) GetTopBorrowers(r models.ReportRange, limit int) ([]models.BorrowerCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]*models.BorrowerCount)
+	for _, l := range s.loansInRange(r) {
+		u, ok := s.users[l.UserID]
+		if !ok {
+			continue
+		}
+		b, ok := counts[u.ID]
+		if !ok {
+			b = &models.BorrowerCount{UserID: u.ID, Username: u.Username, Fullname: u.Fullname, Role: u.Role}
+			counts[u.ID] = b
+		}
+		b.Loans++
+		b.Fines += l.Fine
+	}
+
+	borrowers := []models.BorrowerCount{}
+	for _, b := range counts {
+		borrowers = append(borrowers, *b)
+	}
+	sort.Slice(borrowers, func(i, j int) bool {
+		if borrowers[i].Loans != borrowers[j].Loans {
+			return borrowers[i].Loans > borrowers[j].Loans
+		}
+		return borrowers[i].Username < borrowers[j].Username
+	})
+	if len(borrowers) > limit {
+		borrowers = borrowers[:limit]
+	}
+	return borrowers, nil
+}
+
+// GetFineReport menjumlahkan tagihan, pembayaran dan pembebasan denda per periode,
+// ditambah denda berjalan pinjaman yang belum dikembalikan saat ini.
+func (s *MemoryStore) GetFineReport(r models.ReportRange, interval string) (*models.FineReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []models.FineEntry
+	for _, e := range s.fines {
+		if inRange(r, e.CreatedAt) {
+			entries = append(entries, *e)
+		}
+	}
+	report := fineReport(r, interval, entries)
+	for _, l := range s.loans {
+		if IsOpenLoan(l.Status) {
+			report.Accruing += l.AccruedFine
+		}
+	}
+	return report, nil
+}
+
+// GetOverdueReport menghitung tingkat keterlambatan pinjaman yang jatuh tempo dalam rentang
+// dan sebelum now.
+func (s *MemoryStore) GetOverdueReport(r models.ReportRange, now time.Time) (*models.OverdueReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var o models.OverdueReport
+	for _, l := range s.loans {
+		if !inRange(r, l.DueDate) || !l.DueDate.Before(now) {
+			continue
+		}
+		o.Due++
+		if isOverdue(l, now) {
+			o.Overdue++
+		}
+		if l.ReturnDate == nil {
+			o.StillOpen++
+		}
+	}
+	overdueRate(&o)
+	return &o, nil
+}
+
+// GetRoleReport merangkum pinjaman dalam rentang per role anggota, terbanyak lebih dulu.
+func (s *MemoryStore) GetRoleReport(r models.ReportRange, now time.Time) ([]models.RoleReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byRole := make(map[string]*models.RoleReport)
+	borrowers := make(map[string]map[string]bool)
+	for _, l := range s.loansInRange(r) {
+		u, ok := s.users[l.UserID]
+		if !ok {
+			continue
+		}
+		rr, ok := byRole[u.Role]
+		if !ok {
+			rr = &models.RoleReport{Role: u.Role}
+			byRole[u.Role] = rr
+			borrowers[u.Role] = make(map[string]bool)
+		}
+		borrowers[u.Role][u.ID] = true
+		rr.Loans++
+		if l.ReturnDate != nil {
+			rr.Returned++
+			rr.Fines += l.Fine
+		}
+		if isOverdue(l, now) {
+			rr.Overdue++
+		}
+	}
+
+	roles := []models.RoleReport{}
+	for role, rr := range byRole {
+		rr.Borrowers = len(borrowers[role])
+		roles = append(roles, *rr)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].Loans != roles[j].Loans {
+			return roles[i].Loans > roles[j].Loans
+		}
+		return roles[i].Role < roles[j].Role
+	})
+	return roles, nil
+}
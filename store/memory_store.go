@@ -0,0 +1,1043 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore adalah implementasi Store yang menyimpan seluruh data di memori.
+// Cocok untuk pengujian dan menjalankan aplikasi tanpa server database.
+// Semantik setiap method mengikuti SQLStore (termasuk transaksi stok dan denda).
+type MemoryStore struct {
+	mu sync.Mutex
+
+	users         map[string]*models.User
+	books         map[int]*models.Book
+	items         map[int]*models.BookItem
+	loans         map[int]*models.Loan
+	holds         map[int]*models.Hold
+	renewals      map[int]*models.LoanRenewal
+	policies      map[int]*models.LoanPolicy
+	holidays      map[int]*models.Holiday
+	fines         map[int]*models.FineEntry
+	categories    map[int]*models.Category
+	notifications map[int]*models.Notification
+	notifPrefs    map[string]map[string]models.NotificationPreference // user ID -> kategori -> preferensi
+	outbox        map[int]*models.OutboxMessage
+	webhooks      map[int]*models.Webhook
+	deliveries    map[int]*models.WebhookDelivery
+	jobs          map[string]*models.Job
+	jobRuns       map[int]*models.JobRun
+	settings      *models.Settings
+	settingsAudit []models.SettingsChange
+	letterhead    *models.Letterhead
+	publisher     NotificationPublisher
+
+	nextBookID         int
+	nextItemID         int
+	nextLoanID         int
+	nextHoldID         int
+	nextRenewalID      int
+	nextPolicyID       int
+	nextHolidayID      int
+	nextFineID         int
+	nextCategoryID     int
+	nextNotificationID int
+	nextOutboxID       int
+	nextWebhookID      int
+	nextDeliveryID     int
+	nextJobRunID       int
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore membuat penyimpanan in-memory kosong.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:         make(map[string]*models.User),
+		books:         make(map[int]*models.Book),
+		items:         make(map[int]*models.BookItem),
+		loans:         make(map[int]*models.Loan),
+		holds:         make(map[int]*models.Hold),
+		renewals:      make(map[int]*models.LoanRenewal),
+		policies:      make(map[int]*models.LoanPolicy),
+		holidays:      make(map[int]*models.Holiday),
+		fines:         make(map[int]*models.FineEntry),
+		categories:    make(map[int]*models.Category),
+		notifications: make(map[int]*models.Notification),
+		notifPrefs:    make(map[string]map[string]models.NotificationPreference),
+		outbox:        make(map[int]*models.OutboxMessage),
+		webhooks:      make(map[int]*models.Webhook),
+		deliveries:    make(map[int]*models.WebhookDelivery),
+		jobs:          make(map[string]*models.Job),
+		jobRuns:       make(map[int]*models.JobRun),
+	}
+}
+
+// Close tidak melakukan apa-apa untuk penyimpanan in-memory.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// InitSchema menyiapkan pengaturan default jika belum ada.
+func (s *MemoryStore) InitSchema() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settings == nil {
+		s.settings = DefaultSettings()
+	}
+	return nil
+}
+
+// containsFold meniru perilaku LIKE '%q%' pada collation MySQL (case-insensitive).
+func containsFold(s, q string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(q))
+}
+
+// ==========================================
+// USER
+// ==========================================
+
+// CreateUser menambahkan pengguna baru.
+func (s *MemoryStore) CreateUser(username, hashedPassword, role, fullname string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Username == username {
+			return nil, ErrUserExists
+		}
+	}
+
+	user := &models.User{
+		ID:        uuid.NewString(),
+		Username:  username,
+		Password:  hashedPassword,
+		Role:      role,
+		Fullname:  fullname,
+		CreatedAt: time.Now(),
+	}
+	s.users[user.ID] = user
+
+	cp := *user
+	return &cp, nil
+}
+
+// GetByUsername mencari pengguna berdasarkan username.
+func (s *MemoryStore) GetByUsername(username string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Username == username {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// GetUserByID mencari pengguna berdasarkan ID.
+func (s *MemoryStore) GetUserByID(id string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	cp := *u
+	return &cp, nil
+}
+
+// GetAllUsers mengambil semua data pengguna (tanpa password).
+func (s *MemoryStore) GetAllUsers() ([]models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterUsers(func(*models.User) bool { return true }), nil
+}
+
+// filterUsers mengembalikan salinan pengguna yang lolos filter, diurutkan berdasarkan waktu daftar.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) filterUsers(keep func(*models.User) bool) []models.User {
+	var users []models.User
+	for _, u := range s.users {
+		if keep(u) {
+			cp := *u
+			cp.Password = ""
+			users = append(users, cp)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].Username < users[j].Username
+		}
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+	return users
+}
+
+// UpdateUser memperbarui data pengguna (nama, role, NIP, kontak).
+func (s *MemoryStore) UpdateUser(user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[user.ID]
+	if !ok {
+		return nil // UPDATE tanpa baris yang cocok tidak dianggap error
+	}
+	u.Fullname = user.Fullname
+	u.Role = user.Role
+	u.NIP = user.NIP
+	u.Contact = user.Contact
+	return nil
+}
+
+// SetMembershipExpiry mengatur masa berlaku keanggotaan (nil = tanpa batas).
+func (s *MemoryStore) SetMembershipExpiry(userID string, expiresAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if expiresAt != nil {
+		t := *expiresAt
+		expiresAt = &t
+	}
+	u.MembershipExpiresAt = expiresAt
+	return nil
+}
+
+// SetUserBlock memblokir pengguna dengan alasan tertentu; alasan kosong membuka blokir.
+func (s *MemoryStore) SetUserBlock(userID, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.BlockedReason = reason
+	return nil
+}
+
+// SetEmailPreference mengatur alamat email dan opt-in notifikasi email pengguna.
+func (s *MemoryStore) SetEmailPreference(userID, email string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.Email = email
+	u.EmailNotifications = enabled
+	return nil
+}
+
+// DeleteUser menghapus pengguna beserta notifikasi, reservasi, dan histori pinjamannya.
+func (s *MemoryStore) DeleteUser(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for nid, n := range s.notifications {
+		if n.UserID == id {
+			delete(s.notifications, nid)
+		}
+	}
+	delete(s.notifPrefs, id)
+	for oid, m := range s.outbox {
+		if m.UserID == id {
+			delete(s.outbox, oid)
+		}
+	}
+	for fid, f := range s.fines {
+		if f.UserID == id {
+			delete(s.fines, fid)
+		}
+	}
+	for lid, l := range s.loans {
+		if l.UserID == id {
+			// Eksemplar yang masih dipinjam tidak akan pernah kembali, tandai hilang
+			if item, ok := s.items[l.ItemID]; ok && IsOpenLoan(l.Status) {
+				item.Status = ItemLost
+				s.syncStock(l.BookID)
+			}
+			for rid, r := range s.renewals {
+				if r.LoanID == lid {
+					delete(s.renewals, rid)
+				}
+			}
+			delete(s.loans, lid)
+		}
+	}
+	for hid, h := range s.holds {
+		if h.UserID == id {
+			s.releaseHoldItem(h)
+			delete(s.holds, hid)
+		}
+	}
+	delete(s.users, id)
+	return nil
+}
+
+// SearchUsers mencari pengguna berdasarkan username, nama lengkap, atau NIP.
+func (s *MemoryStore) SearchUsers(query string) ([]models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterUsers(func(u *models.User) bool {
+		return containsFold(u.Username, query) || containsFold(u.Fullname, query) || containsFold(u.NIP, query)
+	}), nil
+}
+
+// CountUsers menghitung total pengguna terdaftar.
+func (s *MemoryStore) CountUsers() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.users), nil
+}
+
+// ==========================================
+// BOOKS
+// ==========================================
+
+// filterBooks mengembalikan salinan buku yang lolos filter, terbaru lebih dulu.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) filterBooks(keep func(*models.Book) bool) []models.Book {
+	var books []models.Book
+	for _, b := range s.books {
+		if keep(b) {
+			books = append(books, *b)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool {
+		if books[i].CreatedAt.Equal(books[j].CreatedAt) {
+			return books[i].ID > books[j].ID
+		}
+		return books[i].CreatedAt.After(books[j].CreatedAt)
+	})
+	return books
+}
+
+// SearchBooks mencari buku berdasarkan judul, penulis, kategori, ISBN, atau penerbit.
+func (s *MemoryStore) SearchBooks(query string) ([]models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterBooks(func(b *models.Book) bool {
+		return containsFold(b.Title, query) || containsFold(b.Author, query) || containsFold(b.Category, query) ||
+			containsFold(b.ISBN, query) || containsFold(b.Publisher, query)
+	}), nil
+}
+
+// CreateBook menambahkan buku baru.
+// Book.Stock diperlakukan sebagai jumlah eksemplar awal yang dibuat otomatis.
+func (s *MemoryStore) CreateBook(book *models.Book) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertBook(book)
+}
+
+// insertBook adalah inti CreateBook; pemanggil memegang s.mu.
+func (s *MemoryStore) insertBook(book *models.Book) error {
+	s.nextBookID++
+	book.ID = s.nextBookID
+	book.CreatedAt = time.Now()
+	cp := *book
+	cp.Stock = 0
+	s.books[book.ID] = &cp
+
+	for i := 0; i < book.Stock; i++ {
+		if err := s.insertItem(&models.BookItem{BookID: book.ID}); err != nil {
+			return err
+		}
+	}
+	s.syncStock(book.ID)
+	return nil
+}
+
+// ImportBooks menyimpan kategori baru dan buku hasil impor sekaligus.
+func (s *MemoryStore) ImportBooks(books []models.Book, categories []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, b := range books {
+		if b.ISBN == "" {
+			continue
+		}
+		for _, e := range s.books {
+			if e.ISBN == b.ISBN {
+				return ErrISBNExists
+			}
+		}
+	}
+
+	existing := make(map[string]bool, len(s.categories))
+	for _, c := range s.categories {
+		existing[strings.ToLower(c.Name)] = true
+	}
+	for _, name := range categories {
+		if existing[strings.ToLower(name)] {
+			continue
+		}
+		existing[strings.ToLower(name)] = true
+		s.nextCategoryID++
+		s.categories[s.nextCategoryID] = &models.Category{ID: s.nextCategoryID, Name: name}
+	}
+	for i := range books {
+		if err := s.insertBook(&books[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAllBooks mengambil semua daftar buku diurutkan dari yang terbaru.
+func (s *MemoryStore) GetAllBooks() ([]models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterBooks(func(*models.Book) bool { return true }), nil
+}
+
+// GetBookByID mengambil detail buku berdasarkan ID.
+func (s *MemoryStore) GetBookByID(id int) (*models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.books[id]
+	if !ok {
+		return nil, ErrBookNotFound
+	}
+	cp := *b
+	return &cp, nil
+}
+
+// UpdateBook memperbarui informasi buku. Stok diturunkan dari eksemplar sehingga tidak diubah.
+func (s *MemoryStore) UpdateBook(book *models.Book) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.books[book.ID]
+	if !ok {
+		return nil
+	}
+	b.Title = book.Title
+	b.Author = book.Author
+	b.Category = book.Category
+	b.ISBN = book.ISBN
+	b.Publisher = book.Publisher
+	b.ImageURL = book.ImageURL
+	b.PublishedYear = book.PublishedYear
+	return nil
+}
+
+// DeleteBook menghapus buku berdasarkan ID.
+// Seperti foreign key pada MySQL, buku yang masih memiliki histori pinjaman tidak dapat dihapus.
+func (s *MemoryStore) DeleteBook(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.loans {
+		if l.BookID == id {
+			return ErrBookHasLoans
+		}
+	}
+	for hid, h := range s.holds {
+		if h.BookID == id {
+			delete(s.holds, hid)
+		}
+	}
+	for iid, item := range s.items {
+		if item.BookID == id {
+			delete(s.items, iid)
+		}
+	}
+	delete(s.books, id)
+	return nil
+}
+
+// CountBooks menghitung total buku.
+func (s *MemoryStore) CountBooks() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.books), nil
+}
+
+// ==========================================
+// CATEGORIES
+// ==========================================
+
+// CreateCategory menambah kategori buku baru. Nama kategori harus unik.
+func (s *MemoryStore) CreateCategory(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.categories {
+		if strings.EqualFold(c.Name, name) {
+			return ErrCategoryExists
+		}
+	}
+	s.nextCategoryID++
+	s.categories[s.nextCategoryID] = &models.Category{ID: s.nextCategoryID, Name: name}
+	return nil
+}
+
+// GetAllCategories mengambil semua kategori buku diurutkan berdasarkan nama.
+func (s *MemoryStore) GetAllCategories() ([]models.Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var categories []models.Category
+	for _, c := range s.categories {
+		categories = append(categories, *c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+// DeleteCategory menghapus kategori.
+func (s *MemoryStore) DeleteCategory(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.categories, id)
+	return nil
+}
+
+// ==========================================
+// LOANS
+// ==========================================
+
+// BorrowBook memproses peminjaman buku: memilih eksemplar tersedia, menandainya dipinjam,
+// dan membuat record peminjaman. Seluruh langkah dilakukan di bawah satu lock
+// sehingga setara dengan transaksi pada SQLStore.
+func (s *MemoryStore) BorrowBook(userID string, bookID, duration int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.borrow(userID, bookID, 0, duration)
+}
+
+// BorrowItem memproses peminjaman eksemplar tertentu (misal hasil scan barcode).
+func (s *MemoryStore) BorrowItem(userID string, itemID, duration int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[itemID]
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+	return s.borrow(userID, item.BookID, itemID, duration)
+}
+
+// borrow adalah inti BorrowBook/BorrowItem. itemID 0 berarti pilih eksemplar tersedia pertama.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) borrow(userID string, bookID, itemID, duration int) (*models.Loan, error) {
+	if _, ok := s.books[bookID]; !ok {
+		return nil, ErrBookNotFound
+	}
+	if _, ok := s.users[userID]; !ok {
+		return nil, ErrInvalidReference
+	}
+
+	// Reservasi "ready" milik peminjam ini memakai eksemplar yang sudah disisihkan untuknya
+	var hold *models.Hold
+	for _, h := range s.holds {
+		if h.UserID == userID && h.BookID == bookID && h.Status == HoldReady && (hold == nil || h.ID < hold.ID) {
+			hold = h
+		}
+	}
+
+	var item *models.BookItem
+	if hold != nil && (itemID == 0 || itemID == hold.ItemID) {
+		item = s.items[hold.ItemID]
+		if item == nil || item.Status != ItemReserved {
+			return nil, ErrItemUnavailable
+		}
+	} else {
+		if hold == nil {
+			// Eksemplar yang kembali menjadi hak antrean reservasi lebih dulu
+			for _, h := range s.holds {
+				if h.BookID == bookID && h.Status == HoldWaiting {
+					return nil, ErrOutOfStock
+				}
+			}
+		}
+		if itemID != 0 {
+			item = s.items[itemID]
+			if item == nil || item.BookID != bookID {
+				return nil, ErrItemNotFound
+			}
+			if item.Status != ItemAvailable {
+				return nil, ErrItemUnavailable
+			}
+		} else {
+			for _, it := range s.items {
+				if it.BookID == bookID && it.Status == ItemAvailable && (item == nil || it.CopyNo < item.CopyNo) {
+					item = it
+				}
+			}
+			if item == nil {
+				return nil, ErrOutOfStock
+			}
+		}
+		// Peminjam mengambil eksemplar lain, eksemplar yang disisihkan dikembalikan ke rak
+		if hold != nil {
+			s.releaseHoldItem(hold)
+		}
+	}
+	if hold != nil {
+		now := time.Now()
+		hold.Status = HoldFulfilled
+		hold.ClosedAt = &now
+	}
+
+	item.Status = ItemBorrowed
+	s.syncStock(bookID)
+
+	loanDate := time.Now()
+	s.nextLoanID++
+	loan := &models.Loan{
+		ID:       s.nextLoanID,
+		UserID:   userID,
+		BookID:   bookID,
+		ItemID:   item.ID,
+		LoanDate: loanDate,
+		DueDate:  s.calendar().DueDate(loanDate, duration),
+		Status:   LoanBorrowed,
+	}
+	s.loans[loan.ID] = loan
+
+	cp := *loan
+	return &cp, nil
+}
+
+// ReturnBook memproses pengembalian buku (hitung denda, update status, tambah stok).
+func (s *MemoryStore) ReturnBook(loanID int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.loans[loanID]
+	if !ok {
+		return nil, ErrLoanNotFound
+	}
+	if l.Status == LoanReturned {
+		return nil, ErrAlreadyReturned
+	}
+
+	var role, category string
+	if u, ok := s.users[l.UserID]; ok {
+		role = u.Role
+	}
+	if b, ok := s.books[l.BookID]; ok {
+		category = b.Category
+	}
+	finePerDay := ResolveLoanPolicy(s.policyList(), s.settings, role, category).FinePerDay
+
+	returnDate := time.Now()
+	l.ReturnDate = &returnDate
+	l.Status = LoanReturned
+	l.Fine = CalculateFine(l.DueDate, returnDate, finePerDay, s.calendar())
+	l.AccruedFine = 0 // Digantikan denda final
+	if l.Fine > 0 {
+		s.addFineEntry(&models.FineEntry{UserID: l.UserID, LoanID: l.ID, Type: FineCharge, Amount: l.Fine, CreatedAt: returnDate})
+	}
+
+	if item, ok := s.items[l.ItemID]; ok && item.Status == ItemBorrowed {
+		item.Status = ItemAvailable
+		s.syncStock(l.BookID)
+	}
+
+	return &models.Loan{
+		ID:         l.ID,
+		UserID:     l.UserID,
+		BookID:     l.BookID,
+		ItemID:     l.ItemID,
+		DueDate:    l.DueDate,
+		ReturnDate: &returnDate,
+		Status:     l.Status,
+		Fine:       l.Fine,
+	}, nil
+}
+
+// filterLoans mengembalikan pinjaman yang lolos filter beserta judul buku dan username,
+// terbaru lebih dulu. Seperti JOIN pada MySQL, pinjaman tanpa buku/pengguna dilewati.
+// Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) filterLoans(keep func(*models.Loan) bool) []models.Loan {
+	var loans []models.Loan
+	for _, l := range s.loans {
+		if !keep(l) {
+			continue
+		}
+		b, ok := s.books[l.BookID]
+		if !ok {
+			continue
+		}
+		u, ok := s.users[l.UserID]
+		if !ok {
+			continue
+		}
+
+		cp := *l
+		if l.ReturnDate != nil {
+			t := *l.ReturnDate
+			cp.ReturnDate = &t
+		}
+		cp.Book = &models.Book{ID: b.ID, Title: b.Title, Category: b.Category}
+		cp.User = &models.User{ID: u.ID, Username: u.Username, Fullname: u.Fullname}
+		if item, ok := s.items[l.ItemID]; ok {
+			cp.Item = &models.BookItem{ID: item.ID, BookID: item.BookID, Barcode: item.Barcode}
+		}
+		loans = append(loans, cp)
+	}
+	sort.Slice(loans, func(i, j int) bool {
+		if loans[i].LoanDate.Equal(loans[j].LoanDate) {
+			return loans[i].ID > loans[j].ID
+		}
+		return loans[i].LoanDate.After(loans[j].LoanDate)
+	})
+	return loans
+}
+
+// GetAllBorrowedLoans mengambil semua peminjaman yang belum dikembalikan ('borrowed' atau 'late').
+func (s *MemoryStore) GetAllBorrowedLoans() ([]models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var loans []models.Loan
+	for _, l := range s.loans {
+		if !IsOpenLoan(l.Status) {
+			continue
+		}
+		b, ok := s.books[l.BookID]
+		if !ok {
+			continue
+		}
+		u, ok := s.users[l.UserID]
+		if !ok {
+			continue
+		}
+		loans = append(loans, models.Loan{
+			ID:          l.ID,
+			UserID:      l.UserID,
+			BookID:      l.BookID,
+			ItemID:      l.ItemID,
+			LoanDate:    l.LoanDate,
+			DueDate:     l.DueDate,
+			Status:      l.Status,
+			AccruedFine: l.AccruedFine,
+			User:        &models.User{ID: u.ID, Username: u.Username, Role: u.Role},
+			Book:        &models.Book{ID: b.ID, Title: b.Title, Category: b.Category},
+		})
+	}
+	sort.Slice(loans, func(i, j int) bool { return loans[i].ID < loans[j].ID })
+	return loans, nil
+}
+
+// GetAllLoans mengambil semua riwayat peminjaman.
+func (s *MemoryStore) GetAllLoans() ([]models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterLoans(func(*models.Loan) bool { return true }), nil
+}
+
+// GetLoansFiltered mengambil riwayat peminjaman berdasarkan rentang tanggal.
+func (s *MemoryStore) GetLoansFiltered(startDate, endDate time.Time) ([]models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterLoans(func(l *models.Loan) bool {
+		return !l.LoanDate.Before(startDate) && !l.LoanDate.After(endDate)
+	}), nil
+}
+
+// GetLoanByID mengambil detail satu peminjaman beserta judul buku dan username.
+func (s *MemoryStore) GetLoanByID(id int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loans := s.filterLoans(func(l *models.Loan) bool { return l.ID == id })
+	if len(loans) == 0 {
+		return nil, ErrLoanNotFound
+	}
+	l := &loans[0]
+	l.User.Role = s.users[l.UserID].Role
+	l.Book.Category = s.books[l.BookID].Category
+	return l, nil
+}
+
+// GetLoansByUserID mengambil riwayat peminjaman milik user tertentu.
+func (s *MemoryStore) GetLoansByUserID(userID string) ([]models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loans := s.filterLoans(func(l *models.Loan) bool { return l.UserID == userID })
+	for i := range loans {
+		loans[i].User = nil // Query MySQL tidak melakukan JOIN ke users
+	}
+	return loans, nil
+}
+
+// GetOverdueLoans mengambil daftar peminjaman yang terlambat dan belum dikembalikan.
+func (s *MemoryStore) GetOverdueLoans(userID string) ([]models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var loans []models.Loan
+	for _, l := range s.loans {
+		if l.UserID != userID || !IsOpenLoan(l.Status) || !l.DueDate.Before(now) {
+			continue
+		}
+		b, ok := s.books[l.BookID]
+		if !ok {
+			continue
+		}
+		loans = append(loans, models.Loan{
+			ID:      l.ID,
+			BookID:  l.BookID,
+			DueDate: l.DueDate,
+			Book:    &models.Book{ID: b.ID, Title: b.Title},
+		})
+	}
+	sort.Slice(loans, func(i, j int) bool { return loans[i].ID < loans[j].ID })
+	return loans, nil
+}
+
+// SetLoanAccrual memperbarui status dan denda berjalan pinjaman yang belum dikembalikan.
+func (s *MemoryStore) SetLoanAccrual(loanID int, status string, accruedFine int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.loans[loanID]
+	if !ok {
+		return ErrLoanNotFound
+	}
+	if IsOpenLoan(l.Status) {
+		l.Status = status
+		l.AccruedFine = accruedFine
+	}
+	return nil
+}
+
+// CountTotalActiveLoans menghitung total peminjaman yang masih aktif.
+func (s *MemoryStore) CountTotalActiveLoans() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, l := range s.loans {
+		if IsOpenLoan(l.Status) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountActiveLoansByUser menghitung peminjaman aktif milik user tertentu.
+func (s *MemoryStore) CountActiveLoansByUser(userID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, l := range s.loans {
+		if l.UserID == userID && IsOpenLoan(l.Status) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ==========================================
+// NOTIFICATIONS
+// ==========================================
+
+// GetNotifications mengambil daftar notifikasi untuk user tertentu, terbaru lebih dulu,
+// difilter kategori dan status baca.
+func (s *MemoryStore) GetNotifications(userID string, filter models.NotificationFilter) ([]models.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notifs []models.Notification
+	for _, n := range s.notifications {
+		if n.UserID != userID {
+			continue
+		}
+		if filter.Category != "" && n.Category != filter.Category {
+			continue
+		}
+		if filter.IsRead != nil && n.IsRead != *filter.IsRead {
+			continue
+		}
+		notifs = append(notifs, *n)
+	}
+	sort.Slice(notifs, func(i, j int) bool {
+		if notifs[i].CreatedAt.Equal(notifs[j].CreatedAt) {
+			return notifs[i].ID > notifs[j].ID
+		}
+		return notifs[i].CreatedAt.After(notifs[j].CreatedAt)
+	})
+	return notifs, nil
+}
+
+// GetNotificationsSince mengambil notifikasi dengan ID lebih besar dari afterID, terlama lebih dulu.
+func (s *MemoryStore) GetNotificationsSince(userID string, afterID int) ([]models.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notifs []models.Notification
+	for _, n := range s.notifications {
+		if n.UserID == userID && n.ID > afterID {
+			notifs = append(notifs, *n)
+		}
+	}
+	sort.Slice(notifs, func(i, j int) bool { return notifs[i].ID < notifs[j].ID })
+	return notifs, nil
+}
+
+// MarkNotificationRead menandai notifikasi sebagai sudah dibaca.
+func (s *MemoryStore) MarkNotificationRead(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n, ok := s.notifications[id]; ok {
+		n.IsRead = true
+	}
+	return nil
+}
+
+// CreateNotification membuat notifikasi baru.
+// Mencegah duplikasi pesan yang sama untuk user yang sama.
+func (s *MemoryStore) CreateNotification(userID, category, severity, message string) error {
+	s.mu.Lock()
+	if _, ok := s.users[userID]; !ok {
+		s.mu.Unlock()
+		return ErrInvalidReference
+	}
+	enabled := FindNotificationPreference(s.notificationPreferences(userID), category).Enabled
+	// Notifikasi kategori yang dimatikan hanya ada di outbox, jadi deduplikasi dicek di sana
+	if enabled {
+		for _, n := range s.notifications {
+			if n.UserID == userID && n.Message == message {
+				s.mu.Unlock()
+				return nil // Duplicate found (even if read), skip
+			}
+		}
+	} else {
+		for _, m := range s.outbox {
+			if m.UserID == userID && m.Message == message {
+				s.mu.Unlock()
+				return nil
+			}
+		}
+	}
+
+	n := &models.Notification{
+		UserID:    userID,
+		Category:  category,
+		Severity:  severity,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	// Kategori yang dimatikan penerima tidak disimpan di aplikasi, tetapi tetap diteruskan ke
+	// publisher dengan ID 0 agar kanal luar (email) mengikuti preferensinya sendiri
+	if enabled {
+		s.nextNotificationID++
+		n.ID = s.nextNotificationID
+		s.notifications[n.ID] = n
+	}
+	publisher, published := s.publisher, *n
+	s.mu.Unlock()
+
+	// Publisher dipanggil di luar lock agar tidak menahan operasi store lain
+	if publisher != nil {
+		publisher.PublishNotification(published)
+	}
+	return nil
+}
+
+// SetNotificationPublisher memasang penerima notifikasi baru. Dipanggil sekali saat startup.
+func (s *MemoryStore) SetNotificationPublisher(p NotificationPublisher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publisher = p
+}
+
+// DeleteNotification menghapus notifikasi.
+func (s *MemoryStore) DeleteNotification(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.notifications, id)
+	return nil
+}
+
+// ==========================================
+// SETTINGS
+// ==========================================
+
+// GetSettings mengambil pengaturan aplikasi.
+func (s *MemoryStore) GetSettings() (*models.Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settings == nil {
+		return DefaultSettings(), nil
+	}
+	cp := *s.settings
+	return &cp, nil
+}
+
+// UpdateSettings menyimpan pengaturan baru dan mencatat setiap field yang berubah.
+func (s *MemoryStore) UpdateSettings(settings *models.Settings, userID, username string) ([]models.SettingsChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := DefaultSettings()
+	if s.settings != nil {
+		current = s.settings
+	}
+	changes := diffSettings(current, settings, userID, username, time.Now())
+
+	next := *settings
+	next.ID = 1
+	s.settings = &next
+	for i := range changes {
+		changes[i].ID = len(s.settingsAudit) + 1
+		s.settingsAudit = append(s.settingsAudit, changes[i])
+	}
+	return changes, nil
+}
+
+// GetSettingsHistory mengambil riwayat perubahan pengaturan, terbaru lebih dulu.
+// limit <= 0 berarti tanpa batas.
+func (s *MemoryStore) GetSettingsHistory(limit int) ([]models.SettingsChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changes []models.SettingsChange
+	for i := len(s.settingsAudit) - 1; i >= 0; i-- {
+		if limit > 0 && len(changes) == limit {
+			break
+		}
+		changes = append(changes, s.settingsAudit[i])
+	}
+	return changes, nil
+}
@@ -0,0 +1,233 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+// copyWebhook menyalin webhook termasuk slice Events agar data internal tidak ikut berubah.
+func copyWebhook(h *models.Webhook) models.Webhook {
+	cp := *h
+	cp.Events = append([]string{}, h.Events...)
+	return cp
+}
+
+// GetWebhooks mengambil semua webhook, termasuk secret-nya.
+func (s *MemoryStore) GetWebhooks() ([]models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var hooks []models.Webhook
+	for _, h := range s.webhooks {
+		hooks = append(hooks, copyWebhook(h))
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].ID < hooks[j].ID })
+	return hooks, nil
+}
+
+// GetWebhook mengambil satu webhook berdasarkan ID.
+func (s *MemoryStore) GetWebhook(id int) (*models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.webhooks[id]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	cp := copyWebhook(h)
+	return &cp, nil
+}
+
+// CreateWebhook menambahkan langganan webhook baru.
+func (s *MemoryStore) CreateWebhook(hook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextWebhookID++
+	hook.ID = s.nextWebhookID
+	hook.CreatedAt = time.Now()
+	cp := copyWebhook(hook)
+	s.webhooks[cp.ID] = &cp
+	return nil
+}
+
+// UpdateWebhook memperbarui URL, event, deskripsi dan status aktif; Secret kosong berarti tidak diganti.
+func (s *MemoryStore) UpdateWebhook(hook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.webhooks[hook.ID]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+	h.URL = hook.URL
+	h.Events = append([]string{}, hook.Events...)
+	h.Description = hook.Description
+	h.Active = hook.Active
+	if hook.Secret != "" {
+		h.Secret = hook.Secret
+	}
+	return nil
+}
+
+// DeleteWebhook menghapus webhook beserta log pengirimannya.
+func (s *MemoryStore) DeleteWebhook(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return ErrWebhookNotFound
+	}
+	for did, d := range s.deliveries {
+		if d.WebhookID == id {
+			delete(s.deliveries, did)
+		}
+	}
+	delete(s.webhooks, id)
+	return nil
+}
+
+// EnqueueWebhookEvent membuat satu pengiriman untuk setiap webhook aktif yang berlangganan event tersebut.
+func (s *MemoryStore) EnqueueWebhookEvent(eventID, event, payload string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for _, h := range s.webhooks {
+		if !h.Active || !WebhookSubscribes(h.Events, event) {
+			continue
+		}
+		s.addDelivery(&models.WebhookDelivery{
+			WebhookID: h.ID, EventID: eventID, Event: event, Payload: payload,
+			Status: DeliveryPending, NextAttemptAt: now, CreatedAt: now,
+		})
+		count++
+	}
+	return count, nil
+}
+
+// addDelivery menyimpan pengiriman baru dan mengisi ID-nya. Pemanggil wajib memegang s.mu.
+func (s *MemoryStore) addDelivery(d *models.WebhookDelivery) {
+	s.nextDeliveryID++
+	d.ID = s.nextDeliveryID
+	cp := *d
+	s.deliveries[cp.ID] = &cp
+}
+
+// GetDueWebhookDeliveries mengambil pengiriman pending yang jadwalnya sudah tiba, terlama lebih dulu,
+// termasuk pengiriman sending yang klaimnya sudah kedaluwarsa.
+func (s *MemoryStore) GetDueWebhookDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, d := range s.deliveries {
+		if (d.Status == DeliveryPending || d.Status == DeliverySending) && !d.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDeliveries mengambil log pengiriman terbaru milik satu webhook.
+func (s *MemoryStore) GetWebhookDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// ClaimWebhookDelivery menandai pengiriman sending sebelum dikirim; false jika sudah diklaim.
+func (s *MemoryStore) ClaimWebhookDelivery(id int, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[id]
+	if !ok {
+		return false, ErrDeliveryNotFound
+	}
+	if (d.Status != DeliveryPending && d.Status != DeliverySending) || d.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	d.Status = DeliverySending
+	d.NextAttemptAt = now.Add(DeliveryClaimTimeout)
+	return true, nil
+}
+
+// MarkWebhookDelivered menandai pengiriman berhasil (respon 2xx).
+func (s *MemoryStore) MarkWebhookDelivered(id, responseCode int, deliveredAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[id]
+	if !ok {
+		return ErrDeliveryNotFound
+	}
+	d.Status = DeliveryDelivered
+	d.Attempts++
+	d.ResponseCode = responseCode
+	d.LastError = ""
+	d.DeliveredAt = &deliveredAt
+	return nil
+}
+
+// MarkWebhookFailed mencatat percobaan yang gagal; retryAt nil berarti tidak dicoba lagi.
+func (s *MemoryStore) MarkWebhookFailed(id, responseCode int, lastError string, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[id]
+	if !ok {
+		return ErrDeliveryNotFound
+	}
+	d.Attempts++
+	d.ResponseCode = responseCode
+	d.LastError = lastError
+	if retryAt == nil {
+		d.Status = DeliveryFailed
+	} else {
+		d.Status = DeliveryPending
+		d.NextAttemptAt = *retryAt
+	}
+	return nil
+}
+
+// RedeliverWebhook membuat pengiriman baru dengan payload yang sama dari pengiriman lama.
+// Event ID tidak berubah sehingga penerima dapat mengenali duplikat.
+func (s *MemoryStore) RedeliverWebhook(deliveryID int) (*models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.deliveries[deliveryID]
+	if !ok {
+		return nil, ErrDeliveryNotFound
+	}
+	now := time.Now()
+	d := &models.WebhookDelivery{
+		WebhookID: old.WebhookID, EventID: old.EventID, Event: old.Event, Payload: old.Payload,
+		Status: DeliveryPending, NextAttemptAt: now, CreatedAt: now,
+	}
+	s.addDelivery(d)
+	return d, nil
+}
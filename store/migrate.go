@@ -0,0 +1,265 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// ErrMigrationLocked dikembalikan jika instance lain sedang menjalankan migrasi.
+var ErrMigrationLocked = errors.New("another instance is running migrations")
+
+// migrationLockName adalah nama lock MySQL (GET_LOCK) yang dipakai selama migrasi.
+const migrationLockName = "simpus_schema_migrations"
+
+// migrationLockTimeout adalah lama menunggu lock sebelum menyerah (detik).
+const migrationLockTimeout = 60
+
+// migrationFunc menjalankan satu arah migrasi di dalam transaksi.
+// Catatan: MySQL melakukan implicit commit untuk DDL, sehingga hanya SQLite
+// yang benar-benar atomik; karena itu setiap migrasi harus aman diulang.
+type migrationFunc func(tx *sql.Tx, driver string) error
+
+// migration adalah satu langkah perubahan skema bernomor dengan arah up dan down.
+type migration struct {
+	Version int
+	Name    string
+	Up      migrationFunc
+	Down    migrationFunc
+}
+
+// MigrationStatus menggambarkan status satu migrasi pada database.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator diimplementasikan oleh store yang skemanya dikelola dengan migrasi bernomor.
+type Migrator interface {
+	MigrationStatus() ([]MigrationStatus, error)
+	MigrateUp() (int, error)
+	MigrateDown(steps int) (int, error)
+}
+
+var _ Migrator = (*SQLStore)(nil)
+
+// sqlFor membuat migrationFunc yang menjalankan daftar query sesuai driver.
+func sqlFor(mysql, sqlite []string) migrationFunc {
+	return func(tx *sql.Tx, driver string) error {
+		queries := mysql
+		if driver == DriverSQLite {
+			queries = sqlite
+		}
+		for _, query := range queries {
+			if _, err := tx.Exec(query); err != nil {
+				return fmt.Errorf("failed to execute query: %v, error: %w", query, err)
+			}
+		}
+		return nil
+	}
+}
+
+// sameSQL membuat migrationFunc yang query-nya sama untuk semua driver.
+func sameSQL(queries ...string) migrationFunc {
+	return sqlFor(queries, queries)
+}
+
+// ensureMigrationsTable membuat tabel schema_migrations jika belum ada.
+func (s *SQLStore) ensureMigrationsTable() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// appliedMigrations mengambil versi migrasi yang sudah diterapkan beserta waktunya.
+func (s *SQLStore) appliedMigrations() (map[int]time.Time, map[int]string, error) {
+	rows, err := s.db.Query("SELECT version, name, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	names := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var name string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &name, &appliedAt); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = appliedAt
+		names[version] = name
+	}
+	return applied, names, rows.Err()
+}
+
+// withMigrationLock menjalankan fn sambil memegang lock migrasi.
+// MySQL memakai GET_LOCK (otomatis lepas jika koneksi putus); SQLite mengandalkan
+// transaksi IMMEDIATE per migrasi yang sudah mengunci file database.
+func (s *SQLStore) withMigrationLock(fn func() error) error {
+	if s.driver != DriverMySQL {
+		return fn()
+	}
+
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&got); err != nil {
+		return err
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return ErrMigrationLocked
+	}
+	defer conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName)
+
+	return fn()
+}
+
+// MigrationStatus mengembalikan status seluruh migrasi yang dikenal aplikasi,
+// ditambah versi di database yang tidak dikenal (misal dari binary yang lebih baru).
+func (s *SQLStore) MigrationStatus() ([]MigrationStatus, error) {
+	if err := s.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+	applied, names, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []MigrationStatus
+	known := make(map[int]bool)
+	for _, m := range migrations {
+		known[m.Version] = true
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			t := at
+			st.Applied = true
+			st.AppliedAt = &t
+		}
+		result = append(result, st)
+	}
+	for version, at := range applied {
+		if !known[version] {
+			t := at
+			result = append(result, MigrationStatus{Version: version, Name: names[version] + " (unknown)", Applied: true, AppliedAt: &t})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// MigrateUp menerapkan seluruh migrasi yang belum diterapkan secara berurutan.
+// Mengembalikan jumlah migrasi yang diterapkan.
+func (s *SQLStore) MigrateUp() (int, error) {
+	if err := s.ensureMigrationsTable(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.withMigrationLock(func() error {
+		for _, m := range migrations {
+			done, err := s.applyMigration(m, true)
+			if err != nil {
+				return fmt.Errorf("migration %d (%s) up: %w", m.Version, m.Name, err)
+			}
+			if done {
+				log.Printf("Migration %d (%s) applied", m.Version, m.Name)
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// MigrateDown membatalkan maksimal steps migrasi terakhir yang sudah diterapkan.
+// Mengembalikan jumlah migrasi yang dibatalkan.
+func (s *SQLStore) MigrateDown(steps int) (int, error) {
+	if err := s.ensureMigrationsTable(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.withMigrationLock(func() error {
+		for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+			m := migrations[i]
+			done, err := s.applyMigration(m, false)
+			if err != nil {
+				return fmt.Errorf("migration %d (%s) down: %w", m.Version, m.Name, err)
+			}
+			if done {
+				log.Printf("Migration %d (%s) rolled back", m.Version, m.Name)
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// applyMigration menjalankan satu migrasi (up atau down) beserta pencatatannya dalam satu transaksi.
+// Status diperiksa ulang di dalam transaksi agar aman jika instance lain baru saja menjalankannya.
+func (s *SQLStore) applyMigration(m migration, up bool) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = ?", m.Version).Scan(&count); err != nil {
+		return false, err
+	}
+	applied := count > 0
+	if applied == up {
+		return false, nil // Tidak ada yang perlu dilakukan
+	}
+
+	if up {
+		if err := m.Up(tx, s.driver); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now()); err != nil {
+			return false, err
+		}
+	} else {
+		if err := m.Down(tx, s.driver); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// PendingMigrations menghitung migrasi yang belum diterapkan.
+func PendingMigrations(m Migrator) (int, error) {
+	statuses, err := m.MigrationStatus()
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, st := range statuses {
+		if !st.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
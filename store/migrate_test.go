@@ -0,0 +1,41 @@
+package store
+
+import "testing"
+
+func TestMigrationsUpDownUp(t *testing.T) {
+	s := newSQLiteTestStore(t)
+
+	pending, err := PendingMigrations(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 0 {
+		t.Fatalf("pending after up = %d, want 0", pending)
+	}
+	createTestUser(t, s, "fajar")
+
+	n, err := s.MigrateDown(len(migrations))
+	if err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if n != len(migrations) {
+		t.Fatalf("rolled back %d migrations, want %d", n, len(migrations))
+	}
+	if pending, _ := PendingMigrations(s); pending != len(migrations) {
+		t.Fatalf("pending after down = %d, want %d", pending, len(migrations))
+	}
+
+	n, err = s.MigrateUp()
+	if err != nil {
+		t.Fatalf("migrate up again: %v", err)
+	}
+	if n != len(migrations) {
+		t.Fatalf("applied %d migrations, want %d", n, len(migrations))
+	}
+	// Skema hasil up kedua harus bisa dipakai seperti semula
+	createTestUser(t, s, "fajar")
+	book := createTestBook(t, s, "Negeri 5 Menara", 2)
+	if got := bookStock(t, s, book.ID); got != 2 {
+		t.Fatalf("stock = %d, want 2", got)
+	}
+}
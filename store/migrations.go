@@ -0,0 +1,932 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"latihan_cloud8/models"
+)
+
+// migrations adalah daftar migrasi skema secara berurutan.
+// Jangan mengubah migrasi yang sudah dirilis; tambahkan migrasi baru di akhir daftar.
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "create_core_tables",
+		Up: func(tx *sql.Tx, driver string) error {
+			if err := sqlFor(mysqlSchema, sqliteSchema)(tx, driver); err != nil {
+				return err
+			}
+			// Masukkan pengaturan default jika belum ada
+			return sqlFor(
+				[]string{"INSERT IGNORE INTO settings (id, max_loan_books, loan_duration, fine_per_day) VALUES (1, 3, 7, 5000)"},
+				[]string{"INSERT OR IGNORE INTO settings (id, max_loan_books, loan_duration, fine_per_day) VALUES (1, 3, 7, 5000)"},
+			)(tx, driver)
+		},
+		Down: sameSQL(
+			"DROP TABLE IF EXISTS notifications",
+			"DROP TABLE IF EXISTS loans",
+			"DROP TABLE IF EXISTS categories",
+			"DROP TABLE IF EXISTS settings",
+			"DROP TABLE IF EXISTS books",
+			"DROP TABLE IF EXISTS users",
+		),
+	},
+	{
+		// Database MySQL lama dibuat sebelum kolom-kolom ini ada di CREATE TABLE.
+		// Kolom hanya ditambahkan jika belum ada, sehingga aman untuk database baru.
+		Version: 2,
+		Name:    "legacy_user_book_columns",
+		Up: func(tx *sql.Tx, driver string) error {
+			if driver != DriverMySQL {
+				return nil
+			}
+			if err := dropColumnIfExists(tx, "users", "member_type"); err != nil {
+				return err
+			}
+			columns := []struct{ table, column, def string }{
+				{"users", "fullname", "VARCHAR(255)"},
+				{"users", "nip", "VARCHAR(50)"},
+				{"users", "contact", "VARCHAR(255)"},
+				{"books", "image_url", "VARCHAR(255)"},
+				{"books", "published_year", "INT"},
+			}
+			for _, c := range columns {
+				if err := addColumnIfMissing(tx, c.table, c.column, c.def); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		// Kolom-kolom ini bagian dari skema dasar (migrasi 1), jadi tidak dihapus.
+		Down: func(tx *sql.Tx, driver string) error { return nil },
+	},
+	{
+		// Eksemplar fisik buku. Stok lama dikonversi menjadi eksemplar "available" dan
+		// setiap pinjaman aktif mendapat eksemplar "borrowed" sendiri.
+		// Di SQLite loans.item_id tidak diberi FOREIGN KEY agar kolom bisa di-DROP saat rollback.
+		Version: 3,
+		Name:    "book_items",
+		Up: func(tx *sql.Tx, driver string) error {
+			err := sqlFor([]string{
+				`CREATE TABLE IF NOT EXISTS book_items (
+					id INT AUTO_INCREMENT PRIMARY KEY,
+					book_id INT NOT NULL,
+					copy_no INT NOT NULL,
+					barcode VARCHAR(64) NOT NULL UNIQUE,
+					accession_number VARCHAR(64),
+					item_condition VARCHAR(20) NOT NULL DEFAULT 'good',
+					shelf_location VARCHAR(100),
+					status VARCHAR(20) NOT NULL DEFAULT 'available',
+					created_at DATETIME,
+					INDEX idx_book_items_book_status (book_id, status),
+					FOREIGN KEY (book_id) REFERENCES books(id)
+				)`,
+			}, []string{
+				`CREATE TABLE IF NOT EXISTS book_items (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					book_id INT NOT NULL,
+					copy_no INT NOT NULL,
+					barcode VARCHAR(64) NOT NULL UNIQUE,
+					accession_number VARCHAR(64),
+					item_condition VARCHAR(20) NOT NULL DEFAULT 'good',
+					shelf_location VARCHAR(100),
+					status VARCHAR(20) NOT NULL DEFAULT 'available',
+					created_at DATETIME,
+					FOREIGN KEY (book_id) REFERENCES books(id)
+				)`,
+				"CREATE INDEX IF NOT EXISTS idx_book_items_book_status ON book_items (book_id, status)",
+			})(tx, driver)
+			if err != nil {
+				return err
+			}
+
+			if driver == DriverMySQL {
+				if err := addColumnIfMissing(tx, "loans", "item_id", "INT NULL"); err != nil {
+					return err
+				}
+				if err := addForeignKeyIfMissing(tx, "loans", "fk_loans_item", "item_id", "book_items(id)"); err != nil {
+					return err
+				}
+			} else if _, err := tx.Exec("ALTER TABLE loans ADD COLUMN item_id INTEGER"); err != nil {
+				return err
+			}
+
+			return backfillBookItems(tx)
+		},
+		Down: func(tx *sql.Tx, driver string) error {
+			// Pastikan kolom stok mencerminkan eksemplar tersedia sebelum tabel dihapus
+			if _, err := tx.Exec("UPDATE books SET stock = (SELECT COUNT(*) FROM book_items WHERE book_items.book_id = books.id AND book_items.status = 'available')"); err != nil {
+				return err
+			}
+			return sqlFor([]string{
+				"ALTER TABLE loans DROP FOREIGN KEY fk_loans_item",
+				"ALTER TABLE loans DROP COLUMN item_id",
+				"DROP TABLE IF EXISTS book_items",
+			}, []string{
+				"ALTER TABLE loans DROP COLUMN item_id",
+				"DROP INDEX IF EXISTS idx_book_items_book_status",
+				"DROP TABLE IF EXISTS book_items",
+			})(tx, driver)
+		},
+	},
+	{
+		// Reservasi buku beserta batas hari pengambilan di pengaturan.
+		Version: 4,
+		Name:    "holds",
+		Up: func(tx *sql.Tx, driver string) error {
+			err := sqlFor([]string{
+				`CREATE TABLE IF NOT EXISTS holds (
+					id INT AUTO_INCREMENT PRIMARY KEY,
+					user_id VARCHAR(36) NOT NULL,
+					book_id INT NOT NULL,
+					item_id INT NULL,
+					status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+					created_at DATETIME NOT NULL,
+					ready_at DATETIME NULL,
+					expires_at DATETIME NULL,
+					closed_at DATETIME NULL,
+					INDEX idx_holds_book_status (book_id, status),
+					INDEX idx_holds_user (user_id),
+					FOREIGN KEY (user_id) REFERENCES users(id),
+					FOREIGN KEY (book_id) REFERENCES books(id),
+					FOREIGN KEY (item_id) REFERENCES book_items(id)
+				)`,
+			}, []string{
+				`CREATE TABLE IF NOT EXISTS holds (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					user_id VARCHAR(36) NOT NULL,
+					book_id INT NOT NULL,
+					item_id INT NULL,
+					status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+					created_at DATETIME NOT NULL,
+					ready_at DATETIME NULL,
+					expires_at DATETIME NULL,
+					closed_at DATETIME NULL,
+					FOREIGN KEY (user_id) REFERENCES users(id),
+					FOREIGN KEY (book_id) REFERENCES books(id),
+					FOREIGN KEY (item_id) REFERENCES book_items(id)
+				)`,
+				"CREATE INDEX IF NOT EXISTS idx_holds_book_status ON holds (book_id, status)",
+				"CREATE INDEX IF NOT EXISTS idx_holds_user ON holds (user_id)",
+				"ALTER TABLE settings ADD COLUMN hold_pickup_days INT NOT NULL DEFAULT 3",
+			})(tx, driver)
+			if err != nil {
+				return err
+			}
+			if driver == DriverMySQL {
+				return addColumnIfMissing(tx, "settings", "hold_pickup_days", "INT NOT NULL DEFAULT 3")
+			}
+			return nil
+		},
+		Down: func(tx *sql.Tx, driver string) error {
+			// Eksemplar yang disisihkan untuk reservasi dikembalikan ke rak
+			if _, err := tx.Exec("UPDATE book_items SET status = 'available' WHERE status = 'reserved'"); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("UPDATE books SET stock = (SELECT COUNT(*) FROM book_items WHERE book_items.book_id = books.id AND book_items.status = 'available')"); err != nil {
+				return err
+			}
+			return sqlFor([]string{
+				"DROP TABLE IF EXISTS holds",
+				"ALTER TABLE settings DROP COLUMN hold_pickup_days",
+			}, []string{
+				"DROP INDEX IF EXISTS idx_holds_user",
+				"DROP INDEX IF EXISTS idx_holds_book_status",
+				"DROP TABLE IF EXISTS holds",
+				"ALTER TABLE settings DROP COLUMN hold_pickup_days",
+			})(tx, driver)
+		},
+	},
+	{
+		// Perpanjangan pinjaman: jumlah perpanjangan per pinjaman, batasnya di pengaturan,
+		// dan riwayat setiap perpanjangan. renewed_by sengaja tanpa FOREIGN KEY agar
+		// riwayat tetap ada walaupun admin yang memperpanjang dihapus.
+		Version: 5,
+		Name:    "loan_renewals",
+		Up: func(tx *sql.Tx, driver string) error {
+			err := sqlFor([]string{
+				`CREATE TABLE IF NOT EXISTS loan_renewals (
+					id INT AUTO_INCREMENT PRIMARY KEY,
+					loan_id INT NOT NULL,
+					renewed_by VARCHAR(36),
+					old_due_date DATETIME NOT NULL,
+					new_due_date DATETIME NOT NULL,
+					renewed_at DATETIME NOT NULL,
+					INDEX idx_loan_renewals_loan (loan_id),
+					FOREIGN KEY (loan_id) REFERENCES loans(id)
+				)`,
+			}, []string{
+				`CREATE TABLE IF NOT EXISTS loan_renewals (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					loan_id INT NOT NULL,
+					renewed_by VARCHAR(36),
+					old_due_date DATETIME NOT NULL,
+					new_due_date DATETIME NOT NULL,
+					renewed_at DATETIME NOT NULL,
+					FOREIGN KEY (loan_id) REFERENCES loans(id)
+				)`,
+				"CREATE INDEX IF NOT EXISTS idx_loan_renewals_loan ON loan_renewals (loan_id)",
+				"ALTER TABLE loans ADD COLUMN renewal_count INT NOT NULL DEFAULT 0",
+				"ALTER TABLE settings ADD COLUMN max_renewals INT NOT NULL DEFAULT 2",
+			})(tx, driver)
+			if err != nil || driver != DriverMySQL {
+				return err
+			}
+			if err := addColumnIfMissing(tx, "loans", "renewal_count", "INT NOT NULL DEFAULT 0"); err != nil {
+				return err
+			}
+			return addColumnIfMissing(tx, "settings", "max_renewals", "INT NOT NULL DEFAULT 2")
+		},
+		Down: sqlFor([]string{
+			"DROP TABLE IF EXISTS loan_renewals",
+			"ALTER TABLE loans DROP COLUMN renewal_count",
+			"ALTER TABLE settings DROP COLUMN max_renewals",
+		}, []string{
+			"DROP INDEX IF EXISTS idx_loan_renewals_loan",
+			"DROP TABLE IF EXISTS loan_renewals",
+			"ALTER TABLE loans DROP COLUMN renewal_count",
+			"ALTER TABLE settings DROP COLUMN max_renewals",
+		}),
+	},
+	{
+		// Kebijakan peminjaman per role dan opsional per kategori. Tanpa baris di tabel ini
+		// aplikasi tetap memakai pengaturan global, sehingga perilaku lama tidak berubah.
+		Version: 6,
+		Name:    "loan_policies",
+		Up: sqlFor([]string{
+			`CREATE TABLE IF NOT EXISTS loan_policies (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				role VARCHAR(50) NOT NULL,
+				category VARCHAR(255) NOT NULL DEFAULT '',
+				max_loan_books INT NOT NULL,
+				loan_duration INT NOT NULL,
+				fine_per_day INT NOT NULL,
+				max_renewals INT NOT NULL DEFAULT 0,
+				UNIQUE KEY uq_loan_policies_role_category (role, category)
+			)`,
+		}, []string{
+			`CREATE TABLE IF NOT EXISTS loan_policies (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				role VARCHAR(50) NOT NULL,
+				category VARCHAR(255) NOT NULL DEFAULT '' COLLATE NOCASE,
+				max_loan_books INT NOT NULL,
+				loan_duration INT NOT NULL,
+				fine_per_day INT NOT NULL,
+				max_renewals INT NOT NULL DEFAULT 0,
+				UNIQUE (role, category)
+			)`,
+		}),
+		Down: sameSQL("DROP TABLE IF EXISTS loan_policies"),
+	},
+	{
+		// Audit trail perubahan pengaturan, satu baris per field yang berubah.
+		// Username ikut disimpan agar riwayat tetap terbaca walaupun admin tersebut dihapus.
+		Version: 7,
+		Name:    "settings_audit",
+		Up: sqlFor([]string{
+			`CREATE TABLE IF NOT EXISTS settings_audit (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				changed_by VARCHAR(36) NOT NULL,
+				changed_by_name VARCHAR(255) NOT NULL,
+				field VARCHAR(50) NOT NULL,
+				old_value INT NOT NULL,
+				new_value INT NOT NULL,
+				changed_at DATETIME NOT NULL
+			)`,
+		}, []string{
+			`CREATE TABLE IF NOT EXISTS settings_audit (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				changed_by VARCHAR(36) NOT NULL,
+				changed_by_name VARCHAR(255) NOT NULL,
+				field VARCHAR(50) NOT NULL,
+				old_value INT NOT NULL,
+				new_value INT NOT NULL,
+				changed_at DATETIME NOT NULL
+			)`,
+		}),
+		Down: sameSQL("DROP TABLE IF EXISTS settings_audit"),
+	},
+	{
+		// Kalender perpustakaan: tanggal libur dan hari tutup mingguan (bitmask, bit 0 = Minggu).
+		// Tanggal disimpan sebagai teks YYYY-MM-DD agar sama di MySQL dan SQLite tanpa
+		// terpengaruh zona waktu koneksi. Default 0 berarti buka setiap hari.
+		Version: 8,
+		Name:    "holidays",
+		Up: func(tx *sql.Tx, driver string) error {
+			err := sqlFor([]string{
+				`CREATE TABLE IF NOT EXISTS holidays (
+					id INT AUTO_INCREMENT PRIMARY KEY,
+					holiday_date VARCHAR(10) NOT NULL,
+					name VARCHAR(255) NOT NULL,
+					UNIQUE KEY uq_holidays_date (holiday_date)
+				)`,
+			}, []string{
+				`CREATE TABLE IF NOT EXISTS holidays (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					holiday_date VARCHAR(10) NOT NULL UNIQUE,
+					name VARCHAR(255) NOT NULL
+				)`,
+				"ALTER TABLE settings ADD COLUMN closed_weekdays INT NOT NULL DEFAULT 0",
+			})(tx, driver)
+			if err != nil || driver != DriverMySQL {
+				return err
+			}
+			return addColumnIfMissing(tx, "settings", "closed_weekdays", "INT NOT NULL DEFAULT 0")
+		},
+		Down: sameSQL(
+			"DROP TABLE IF EXISTS holidays",
+			"ALTER TABLE settings DROP COLUMN closed_weekdays",
+		),
+	},
+	{
+		// Buku besar denda. Sebelum migrasi ini tidak ada catatan pembayaran, sehingga lunas
+		// tidaknya denda lama tidak bisa diketahui. Semua denda lama dipindahkan sebagai tagihan
+		// tertunggak agar utang yang sebenarnya belum dibayar tidak hilang; denda yang dulu sudah
+		// dibayar di meja sirkulasi dicatat petugas sebagai pembayaran atau pembebasan.
+		Version: 9,
+		Name:    "fine_ledger",
+		Up: sqlFor([]string{
+			`CREATE TABLE IF NOT EXISTS fine_entries (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id VARCHAR(36) NOT NULL,
+				loan_id INT,
+				entry_type VARCHAR(20) NOT NULL,
+				amount INT NOT NULL,
+				method VARCHAR(20),
+				receipt_no VARCHAR(50),
+				reason TEXT,
+				recorded_by VARCHAR(36),
+				created_at DATETIME NOT NULL,
+				INDEX idx_fine_entries_user (user_id),
+				INDEX idx_fine_entries_loan (loan_id),
+				UNIQUE KEY uq_fine_entries_receipt (receipt_no),
+				FOREIGN KEY (user_id) REFERENCES users(id),
+				FOREIGN KEY (loan_id) REFERENCES loans(id)
+			)`,
+			`INSERT INTO fine_entries (user_id, loan_id, entry_type, amount, created_at)
+				SELECT user_id, id, 'charge', fine, COALESCE(return_date, due_date) FROM loans WHERE fine > 0`,
+		}, []string{
+			`CREATE TABLE IF NOT EXISTS fine_entries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id VARCHAR(36) NOT NULL,
+				loan_id INT,
+				entry_type VARCHAR(20) NOT NULL,
+				amount INT NOT NULL,
+				method VARCHAR(20),
+				receipt_no VARCHAR(50) UNIQUE,
+				reason TEXT,
+				recorded_by VARCHAR(36),
+				created_at DATETIME NOT NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id),
+				FOREIGN KEY (loan_id) REFERENCES loans(id)
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_fine_entries_user ON fine_entries (user_id)",
+			"CREATE INDEX IF NOT EXISTS idx_fine_entries_loan ON fine_entries (loan_id)",
+			`INSERT INTO fine_entries (user_id, loan_id, entry_type, amount, created_at)
+				SELECT user_id, id, 'charge', fine, COALESCE(return_date, due_date) FROM loans WHERE fine > 0`,
+		}),
+		Down: sqlFor([]string{
+			"DROP TABLE IF EXISTS fine_entries",
+		}, []string{
+			"DROP INDEX IF EXISTS idx_fine_entries_user",
+			"DROP INDEX IF EXISTS idx_fine_entries_loan",
+			"DROP TABLE IF EXISTS fine_entries",
+		}),
+	},
+	{
+		// Blokir peminjaman: masa berlaku keanggotaan dan blokir manual per pengguna, serta
+		// ambang pinjaman terlambat dan sisa denda pada pengaturan (-1 = tidak memblokir). Ambang
+		// bawaan -1 agar perpustakaan yang sudah berjalan tidak tiba-tiba memblokir anggotanya.
+		Version: 10,
+		Name:    "borrowing_blocks",
+		Up: func(tx *sql.Tx, driver string) error {
+			columns := []struct{ table, column, def string }{
+				{"users", "membership_expires_at", "DATETIME NULL"},
+				{"users", "blocked_reason", "VARCHAR(255) NOT NULL DEFAULT ''"},
+				{"settings", "max_overdue_loans", "INT NOT NULL DEFAULT -1"},
+				{"settings", "max_outstanding_fine", "INT NOT NULL DEFAULT -1"},
+			}
+			for _, c := range columns {
+				if driver == DriverMySQL {
+					if err := addColumnIfMissing(tx, c.table, c.column, c.def); err != nil {
+						return err
+					}
+					continue
+				}
+				if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.def)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: sameSQL(
+			"ALTER TABLE users DROP COLUMN membership_expires_at",
+			"ALTER TABLE users DROP COLUMN blocked_reason",
+			"ALTER TABLE settings DROP COLUMN max_overdue_loans",
+			"ALTER TABLE settings DROP COLUMN max_outstanding_fine",
+		),
+	},
+	{
+		// Pengiriman notifikasi lewat email: alamat dan opt-in per pengguna, serta outbox
+		// berisi pesan yang sudah dirender dan dicoba ulang sampai berhasil terkirim.
+		// notification_id tidak diberi FOREIGN KEY karena notifikasi boleh dihapus pengguna.
+		// message menyimpan isi notifikasi asli untuk deduplikasi notifikasi yang hanya dikirim
+		// lewat email (kategorinya dimatikan di aplikasi sehingga tidak ada baris notifications).
+		Version: 11,
+		Name:    "notification_outbox",
+		Up: func(tx *sql.Tx, driver string) error {
+			columns := []struct{ table, column, def string }{
+				{"users", "email", "VARCHAR(255) NOT NULL DEFAULT ''"},
+				{"users", "email_notifications", "BOOLEAN NOT NULL DEFAULT FALSE"},
+			}
+			for _, c := range columns {
+				if driver == DriverMySQL {
+					if err := addColumnIfMissing(tx, c.table, c.column, c.def); err != nil {
+						return err
+					}
+					continue
+				}
+				if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.def)); err != nil {
+					return err
+				}
+			}
+			return sqlFor([]string{
+				`CREATE TABLE IF NOT EXISTS notification_outbox (
+					id INT AUTO_INCREMENT PRIMARY KEY,
+					notification_id INT,
+					user_id VARCHAR(36) NOT NULL,
+					channel VARCHAR(20) NOT NULL,
+					recipient VARCHAR(255) NOT NULL,
+					subject VARCHAR(255) NOT NULL,
+					body_text TEXT NOT NULL,
+					body_html TEXT NOT NULL,
+					status VARCHAR(20) NOT NULL DEFAULT 'pending',
+					attempts INT NOT NULL DEFAULT 0,
+					last_error TEXT,
+					next_attempt_at DATETIME NOT NULL,
+					created_at DATETIME NOT NULL,
+					sent_at DATETIME NULL,
+					message TEXT NULL,
+					INDEX idx_outbox_due (status, next_attempt_at),
+					INDEX idx_outbox_user (user_id),
+					INDEX idx_outbox_message (user_id, message(191)),
+					FOREIGN KEY (user_id) REFERENCES users(id)
+				)`,
+			}, []string{
+				`CREATE TABLE IF NOT EXISTS notification_outbox (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					notification_id INT,
+					user_id VARCHAR(36) NOT NULL,
+					channel VARCHAR(20) NOT NULL,
+					recipient VARCHAR(255) NOT NULL,
+					subject VARCHAR(255) NOT NULL,
+					body_text TEXT NOT NULL,
+					body_html TEXT NOT NULL,
+					status VARCHAR(20) NOT NULL DEFAULT 'pending',
+					attempts INT NOT NULL DEFAULT 0,
+					last_error TEXT,
+					next_attempt_at DATETIME NOT NULL,
+					created_at DATETIME NOT NULL,
+					sent_at DATETIME NULL,
+					message TEXT NULL,
+					FOREIGN KEY (user_id) REFERENCES users(id)
+				)`,
+				"CREATE INDEX IF NOT EXISTS idx_outbox_due ON notification_outbox (status, next_attempt_at)",
+				"CREATE INDEX IF NOT EXISTS idx_outbox_user ON notification_outbox (user_id)",
+				"CREATE INDEX IF NOT EXISTS idx_outbox_message ON notification_outbox (user_id, message)",
+			})(tx, driver)
+		},
+		Down: sqlFor([]string{
+			"DROP TABLE IF EXISTS notification_outbox",
+			"ALTER TABLE users DROP COLUMN email",
+			"ALTER TABLE users DROP COLUMN email_notifications",
+		}, []string{
+			"DROP INDEX IF EXISTS idx_outbox_due",
+			"DROP INDEX IF EXISTS idx_outbox_user",
+			"DROP INDEX IF EXISTS idx_outbox_message",
+			"DROP TABLE IF EXISTS notification_outbox",
+			"ALTER TABLE users DROP COLUMN email",
+			"ALTER TABLE users DROP COLUMN email_notifications",
+		}),
+	},
+	{
+		// Webhook keluar: langganan event per URL dan log pengiriman (sekaligus antrean coba ulang).
+		// events disimpan sebagai daftar dipisah koma.
+		Version: 12,
+		Name:    "webhooks",
+		Up: sqlFor([]string{
+			`CREATE TABLE IF NOT EXISTS webhooks (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				url VARCHAR(500) NOT NULL,
+				secret VARCHAR(128) NOT NULL,
+				events TEXT NOT NULL,
+				description VARCHAR(255) NOT NULL DEFAULT '',
+				active BOOLEAN NOT NULL DEFAULT TRUE,
+				created_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				webhook_id INT NOT NULL,
+				event_id VARCHAR(36) NOT NULL,
+				event VARCHAR(50) NOT NULL,
+				payload MEDIUMTEXT NOT NULL,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				attempts INT NOT NULL DEFAULT 0,
+				response_code INT NOT NULL DEFAULT 0,
+				last_error TEXT,
+				next_attempt_at DATETIME NOT NULL,
+				created_at DATETIME NOT NULL,
+				delivered_at DATETIME NULL,
+				INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+				INDEX idx_webhook_deliveries_hook (webhook_id),
+				FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+			)`,
+		}, []string{
+			`CREATE TABLE IF NOT EXISTS webhooks (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				url VARCHAR(500) NOT NULL,
+				secret VARCHAR(128) NOT NULL,
+				events TEXT NOT NULL,
+				description VARCHAR(255) NOT NULL DEFAULT '',
+				active BOOLEAN NOT NULL DEFAULT TRUE,
+				created_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				webhook_id INT NOT NULL,
+				event_id VARCHAR(36) NOT NULL,
+				event VARCHAR(50) NOT NULL,
+				payload TEXT NOT NULL,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				attempts INT NOT NULL DEFAULT 0,
+				response_code INT NOT NULL DEFAULT 0,
+				last_error TEXT,
+				next_attempt_at DATETIME NOT NULL,
+				created_at DATETIME NOT NULL,
+				delivered_at DATETIME NULL,
+				FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at)",
+			"CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_hook ON webhook_deliveries (webhook_id)",
+		}),
+		Down: sqlFor([]string{
+			"DROP TABLE IF EXISTS webhook_deliveries",
+			"DROP TABLE IF EXISTS webhooks",
+		}, []string{
+			"DROP INDEX IF EXISTS idx_webhook_deliveries_due",
+			"DROP INDEX IF EXISTS idx_webhook_deliveries_hook",
+			"DROP TABLE IF EXISTS webhook_deliveries",
+			"DROP TABLE IF EXISTS webhooks",
+		}),
+	},
+	{
+		// Kategori dan tingkat kepentingan notifikasi serta preferensi penerimaan per kategori.
+		// Notifikasi lama dikategorikan dari awalan pesan yang dibuat worker; sisanya "general".
+		Version: 13,
+		Name:    "notification_categories",
+		Up: func(tx *sql.Tx, driver string) error {
+			columns := []struct{ table, column, def string }{
+				{"notifications", "category", "VARCHAR(20) NOT NULL DEFAULT 'general'"},
+				{"notifications", "severity", "VARCHAR(10) NOT NULL DEFAULT 'info'"},
+			}
+			for _, c := range columns {
+				if driver == DriverMySQL {
+					if err := addColumnIfMissing(tx, c.table, c.column, c.def); err != nil {
+						return err
+					}
+					continue
+				}
+				if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.def)); err != nil {
+					return err
+				}
+			}
+			backfill := []struct{ prefix, category, severity string }{
+				{"PERINGATAN:%", NotifyOverdue, SeverityWarning},
+				{"PENGINGAT:%", NotifyReminder, SeverityInfo},
+				{"RESERVASI:%", NotifyHold, SeverityInfo},
+			}
+			for _, b := range backfill {
+				if _, err := tx.Exec("UPDATE notifications SET category = ?, severity = ? WHERE message LIKE ?", b.category, b.severity, b.prefix); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS notification_preferences (
+				user_id VARCHAR(36) NOT NULL,
+				category VARCHAR(20) NOT NULL,
+				enabled BOOLEAN NOT NULL DEFAULT TRUE,
+				email BOOLEAN NOT NULL DEFAULT TRUE,
+				PRIMARY KEY (user_id, category),
+				FOREIGN KEY (user_id) REFERENCES users(id)
+			)`); err != nil {
+				return err
+			}
+			if driver == DriverMySQL {
+				return addIndexIfMissing(tx, "notifications", "idx_notifications_user_category", "user_id, category")
+			}
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_notifications_user_category ON notifications (user_id, category)")
+			return err
+		},
+		Down: func(tx *sql.Tx, driver string) error {
+			if driver == DriverMySQL {
+				if _, err := tx.Exec("DROP TABLE IF EXISTS notification_preferences"); err != nil {
+					return err
+				}
+				if err := dropIndexIfExists(tx, "notifications", "idx_notifications_user_category"); err != nil {
+					return err
+				}
+				if err := dropColumnIfExists(tx, "notifications", "category"); err != nil {
+					return err
+				}
+				return dropColumnIfExists(tx, "notifications", "severity")
+			}
+			return sameSQL(
+				"DROP TABLE IF EXISTS notification_preferences",
+				"DROP INDEX IF EXISTS idx_notifications_user_category",
+				"ALTER TABLE notifications DROP COLUMN category",
+				"ALTER TABLE notifications DROP COLUMN severity",
+			)(tx, driver)
+		},
+	},
+	{
+		// Denda berjalan pinjaman terlambat, diperbarui setiap hari oleh worker bersama status "late".
+		Version: 14,
+		Name:    "loan_accrued_fine",
+		Up: func(tx *sql.Tx, driver string) error {
+			if driver == DriverMySQL {
+				if err := addColumnIfMissing(tx, "loans", "accrued_fine", "INT NOT NULL DEFAULT 0"); err != nil {
+					return err
+				}
+				return addIndexIfMissing(tx, "loans", "idx_loans_status_due", "status, due_date")
+			}
+			return sameSQL(
+				"ALTER TABLE loans ADD COLUMN accrued_fine INT NOT NULL DEFAULT 0",
+				"CREATE INDEX IF NOT EXISTS idx_loans_status_due ON loans (status, due_date)",
+			)(tx, driver)
+		},
+		// Pinjaman "late" dikembalikan ke "borrowed" karena versi sebelumnya tidak mengenal status tersebut
+		Down: func(tx *sql.Tx, driver string) error {
+			if _, err := tx.Exec("UPDATE loans SET status = 'borrowed' WHERE status = 'late'"); err != nil {
+				return err
+			}
+			if driver == DriverMySQL {
+				if err := dropIndexIfExists(tx, "loans", "idx_loans_status_due"); err != nil {
+					return err
+				}
+				return dropColumnIfExists(tx, "loans", "accrued_fine")
+			}
+			return sameSQL(
+				"DROP INDEX IF EXISTS idx_loans_status_due",
+				"ALTER TABLE loans DROP COLUMN accrued_fine",
+			)(tx, driver)
+		},
+	},
+	{
+		// Riwayat job terjadwal. UNIQUE (job_name, scheduled_at) menjadi kunci klaim antar instance:
+		// hanya instance yang berhasil menyisipkan baris untuk suatu slot yang menjalankan job-nya.
+		Version: 15,
+		Name:    "jobs",
+		Up: sqlFor([]string{
+			`CREATE TABLE IF NOT EXISTS jobs (
+				name VARCHAR(50) PRIMARY KEY,
+				last_run_at DATETIME NULL,
+				last_status VARCHAR(20) NOT NULL DEFAULT '',
+				last_duration_ms BIGINT NOT NULL DEFAULT 0,
+				last_error TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS job_runs (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				job_name VARCHAR(50) NOT NULL,
+				scheduled_at DATETIME NOT NULL,
+				trigger_type VARCHAR(20) NOT NULL DEFAULT 'schedule',
+				triggered_by VARCHAR(50) NOT NULL DEFAULT '',
+				instance VARCHAR(100) NOT NULL DEFAULT '',
+				status VARCHAR(20) NOT NULL DEFAULT 'running',
+				started_at DATETIME NOT NULL,
+				finished_at DATETIME NULL,
+				duration_ms BIGINT NOT NULL DEFAULT 0,
+				error_message TEXT,
+				UNIQUE KEY uq_job_runs_slot (job_name, scheduled_at),
+				INDEX idx_job_runs_started (job_name, started_at)
+			)`,
+		}, []string{
+			`CREATE TABLE IF NOT EXISTS jobs (
+				name VARCHAR(50) PRIMARY KEY,
+				last_run_at DATETIME NULL,
+				last_status VARCHAR(20) NOT NULL DEFAULT '',
+				last_duration_ms BIGINT NOT NULL DEFAULT 0,
+				last_error TEXT
+			)`,
+			`CREATE TABLE IF NOT EXISTS job_runs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				job_name VARCHAR(50) NOT NULL,
+				scheduled_at DATETIME NOT NULL,
+				trigger_type VARCHAR(20) NOT NULL DEFAULT 'schedule',
+				triggered_by VARCHAR(50) NOT NULL DEFAULT '',
+				instance VARCHAR(100) NOT NULL DEFAULT '',
+				status VARCHAR(20) NOT NULL DEFAULT 'running',
+				started_at DATETIME NOT NULL,
+				finished_at DATETIME NULL,
+				duration_ms BIGINT NOT NULL DEFAULT 0,
+				error_message TEXT,
+				UNIQUE (job_name, scheduled_at)
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_job_runs_started ON job_runs (job_name, started_at)",
+		}),
+		Down: sqlFor([]string{
+			"DROP TABLE IF EXISTS job_runs",
+			"DROP TABLE IF EXISTS jobs",
+		}, []string{
+			"DROP INDEX IF EXISTS idx_job_runs_started",
+			"DROP TABLE IF EXISTS job_runs",
+			"DROP TABLE IF EXISTS jobs",
+		}),
+	},
+	{
+		// Kop surat dokumen PDF; satu baris dengan id = 1 seperti tabel settings
+		Version: 16,
+		Name:    "letterhead",
+		Up: sameSQL(`CREATE TABLE IF NOT EXISTS letterhead (
+			id INT PRIMARY KEY,
+			library_name VARCHAR(255) NOT NULL DEFAULT '',
+			institution VARCHAR(255) NOT NULL DEFAULT '',
+			address VARCHAR(500) NOT NULL DEFAULT '',
+			city VARCHAR(100) NOT NULL DEFAULT '',
+			phone VARCHAR(50) NOT NULL DEFAULT '',
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			website VARCHAR(255) NOT NULL DEFAULT '',
+			head_name VARCHAR(255) NOT NULL DEFAULT '',
+			head_nip VARCHAR(50) NOT NULL DEFAULT '',
+			updated_by VARCHAR(50) NOT NULL DEFAULT '',
+			updated_at DATETIME NULL
+		)`),
+		Down: sameSQL("DROP TABLE IF EXISTS letterhead"),
+	},
+	{
+		// ISBN buku untuk deteksi duplikat saat impor katalog. Tidak UNIQUE karena data lama
+		// bisa memiliki beberapa judul tanpa ISBN (string kosong).
+		Version: 17,
+		Name:    "book_isbn",
+		Up: func(tx *sql.Tx, driver string) error {
+			if driver == DriverMySQL {
+				if err := addColumnIfMissing(tx, "books", "isbn", "VARCHAR(20) NOT NULL DEFAULT ''"); err != nil {
+					return err
+				}
+				return addIndexIfMissing(tx, "books", "idx_books_isbn", "isbn")
+			}
+			return sameSQL(
+				"ALTER TABLE books ADD COLUMN isbn VARCHAR(20) NOT NULL DEFAULT ''",
+				"CREATE INDEX IF NOT EXISTS idx_books_isbn ON books (isbn)",
+			)(tx, driver)
+		},
+		Down: func(tx *sql.Tx, driver string) error {
+			if driver == DriverMySQL {
+				if err := dropIndexIfExists(tx, "books", "idx_books_isbn"); err != nil {
+					return err
+				}
+				return dropColumnIfExists(tx, "books", "isbn")
+			}
+			return sameSQL(
+				"DROP INDEX IF EXISTS idx_books_isbn",
+				"ALTER TABLE books DROP COLUMN isbn",
+			)(tx, driver)
+		},
+	},
+	{
+		Version: 18,
+		Name:    "book_publisher",
+		Up: func(tx *sql.Tx, driver string) error {
+			if driver == DriverMySQL {
+				return addColumnIfMissing(tx, "books", "publisher", "VARCHAR(255) NOT NULL DEFAULT ''")
+			}
+			_, err := tx.Exec("ALTER TABLE books ADD COLUMN publisher VARCHAR(255) NOT NULL DEFAULT ''")
+			return err
+		},
+		Down: sameSQL("ALTER TABLE books DROP COLUMN publisher"),
+	},
+}
+
+// backfillBookItems membuat eksemplar untuk buku yang belum memiliki eksemplar:
+// sejumlah books.stock berstatus "available" ditambah satu eksemplar "borrowed"
+// untuk setiap pinjaman aktif, lalu menautkan pinjaman tersebut ke eksemplarnya.
+func backfillBookItems(tx *sql.Tx) error {
+	type bookStock struct{ id, stock int }
+	var books []bookStock
+	rows, err := tx.Query("SELECT id, COALESCE(stock, 0) FROM books WHERE NOT EXISTS (SELECT 1 FROM book_items WHERE book_items.book_id = books.id)")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var b bookStock
+		if err := rows.Scan(&b.id, &b.stock); err != nil {
+			rows.Close()
+			return err
+		}
+		books = append(books, b)
+	}
+	rows.Close()
+
+	for _, b := range books {
+		var loanIDs []int
+		rows, err := tx.Query("SELECT id FROM loans WHERE book_id = ? AND status = 'borrowed' AND item_id IS NULL ORDER BY id", b.id)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			loanIDs = append(loanIDs, id)
+		}
+		rows.Close()
+
+		for i := 0; i < b.stock; i++ {
+			if err := insertBookItem(tx, &models.BookItem{BookID: b.id}); err != nil {
+				return err
+			}
+		}
+		for _, loanID := range loanIDs {
+			item := &models.BookItem{BookID: b.id, Status: ItemBorrowed}
+			if err := insertBookItem(tx, item); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("UPDATE loans SET item_id = ? WHERE id = ?", item.ID, loanID); err != nil {
+				return err
+			}
+		}
+		if err := syncBookStock(tx, b.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mysqlColumnExists memeriksa keberadaan kolom pada database MySQL aktif.
+func mysqlColumnExists(tx *sql.Tx, table, column string) (bool, error) {
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`, table, column).Scan(&count)
+	return count > 0, err
+}
+
+// addColumnIfMissing menambah kolom MySQL hanya jika belum ada.
+func addColumnIfMissing(tx *sql.Tx, table, column, def string) error {
+	exists, err := mysqlColumnExists(tx, table, column)
+	if err != nil || exists {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, def))
+	return err
+}
+
+// addForeignKeyIfMissing menambah foreign key bernama pada MySQL hanya jika belum ada.
+func addForeignKeyIfMissing(tx *sql.Tx, table, name, column, ref string) error {
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM information_schema.table_constraints
+		WHERE table_schema = DATABASE() AND table_name = ? AND constraint_name = ?`, table, name).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s", table, name, column, ref))
+	return err
+}
+
+// mysqlIndexExists memeriksa apakah index bernama ada pada tabel MySQL.
+func mysqlIndexExists(tx *sql.Tx, table, name string) (bool, error) {
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?`, table, name).Scan(&count)
+	return count > 0, err
+}
+
+// addIndexIfMissing membuat index MySQL hanya jika belum ada (MySQL tidak mengenal
+// CREATE INDEX IF NOT EXISTS).
+func addIndexIfMissing(tx *sql.Tx, table, name, columns string) error {
+	exists, err := mysqlIndexExists(tx, table, name)
+	if err != nil || exists {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (%s)", name, table, columns))
+	return err
+}
+
+// dropIndexIfExists menghapus index MySQL hanya jika ada.
+func dropIndexIfExists(tx *sql.Tx, table, name string) error {
+	exists, err := mysqlIndexExists(tx, table, name)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("DROP INDEX %s ON %s", name, table))
+	return err
+}
+
+// dropColumnIfExists menghapus kolom MySQL hanya jika ada.
+func dropColumnIfExists(tx *sql.Tx, table, column string) error {
+	exists, err := mysqlColumnExists(tx, table, column)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, column))
+	return err
+}
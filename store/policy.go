@@ -0,0 +1,50 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"strings"
+)
+
+// ResolveLoanPolicy memilih kebijakan yang berlaku untuk role dan kategori buku:
+// kebijakan role+kategori, lalu kebijakan role untuk semua kategori, lalu pengaturan
+// global (Settings) sebagai cadangan. Kebijakan cadangan memiliki ID 0.
+func ResolveLoanPolicy(policies []models.LoanPolicy, settings *models.Settings, role, category string) models.LoanPolicy {
+	var roleOnly *models.LoanPolicy
+	for i := range policies {
+		p := &policies[i]
+		if p.Role != role {
+			continue
+		}
+		if p.Category != "" && strings.EqualFold(p.Category, category) {
+			return *p
+		}
+		if p.Category == "" {
+			roleOnly = p
+		}
+	}
+	if roleOnly != nil {
+		return *roleOnly
+	}
+
+	if settings == nil {
+		settings = DefaultSettings()
+	}
+	return models.LoanPolicy{
+		Role:         role,
+		MaxLoanBooks: settings.MaxLoanBooks,
+		LoanDuration: settings.LoanDuration,
+		FinePerDay:   settings.FinePerDay,
+		MaxRenewals:  settings.MaxRenewals,
+	}
+}
+
+// LoanPolicyFor mengambil kebijakan yang berlaku untuk role dan kategori buku dari store.
+// Jika kebijakan atau pengaturan gagal dimuat, nilai bawaan yang dipakai.
+func LoanPolicyFor(s Store, role, category string) models.LoanPolicy {
+	policies, _ := s.GetLoanPolicies()
+	settings, err := s.GetSettings()
+	if err != nil {
+		settings = nil
+	}
+	return ResolveLoanPolicy(policies, settings, role, category)
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"time"
+)
+
+// Interval periode laporan.
+const (
+	ReportDaily   = "day"
+	ReportWeekly  = "week"
+	ReportMonthly = "month"
+)
+
+// IsReportInterval memeriksa apakah interval dikenali.
+func IsReportInterval(interval string) bool {
+	return interval == ReportDaily || interval == ReportWeekly || interval == ReportMonthly
+}
+
+// periodStart mengembalikan awal periode yang memuat t dalam zona waktu lokal server.
+// Minggu dimulai hari Senin.
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	switch interval {
+	case ReportWeekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case ReportMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+	default:
+		return day
+	}
+}
+
+// ReportPeriod mengembalikan label periode untuk t: tanggal awal periode ("2006-01-02")
+// untuk harian dan mingguan, atau "2006-01" untuk bulanan.
+func ReportPeriod(t time.Time, interval string) string {
+	start := periodStart(t, interval)
+	if interval == ReportMonthly {
+		return start.Format("2006-01")
+	}
+	return start.Format(DateLayout)
+}
+
+// reportPeriods mengembalikan label semua periode dalam rentang secara berurutan,
+// termasuk periode tanpa data, agar grafik tidak berlubang.
+func reportPeriods(r models.ReportRange, interval string) []string {
+	var periods []string
+	for t := periodStart(r.Start, interval); t.Before(r.End); {
+		periods = append(periods, ReportPeriod(t, interval))
+		switch interval {
+		case ReportWeekly:
+			t = t.AddDate(0, 0, 7)
+		case ReportMonthly:
+			t = t.AddDate(0, 1, 0)
+		default:
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	return periods
+}
+
+// inRange memeriksa apakah t berada dalam rentang laporan (Start inklusif, End eksklusif).
+func inRange(r models.ReportRange, t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// isOverdue memeriksa apakah pinjaman dikembalikan setelah jatuh tempo, atau belum
+// dikembalikan padahal jatuh temponya sudah lewat pada now.
+func isOverdue(l *models.Loan, now time.Time) bool {
+	if l.ReturnDate != nil {
+		return l.ReturnDate.After(l.DueDate)
+	}
+	return l.DueDate.Before(now)
+}
+
+// loanVolume menyusun volume per periode dari tanggal pinjam dan tanggal kembali.
+func loanVolume(r models.ReportRange, interval string, loanDates, returnDates []time.Time) []models.LoanVolume {
+	periods := reportPeriods(r, interval)
+	index := make(map[string]int, len(periods))
+	volume := make([]models.LoanVolume, len(periods))
+	for i, p := range periods {
+		index[p] = i
+		volume[i].Period = p
+	}
+	for _, t := range loanDates {
+		if i, ok := index[ReportPeriod(t, interval)]; ok {
+			volume[i].Loans++
+		}
+	}
+	for _, t := range returnDates {
+		if i, ok := index[ReportPeriod(t, interval)]; ok {
+			volume[i].Returned++
+		}
+	}
+	return volume
+}
+
+// fineReport menyusun laporan denda per periode dari entri buku besar dalam rentang.
+// Accruing diisi oleh pemanggil.
+func fineReport(r models.ReportRange, interval string, entries []models.FineEntry) *models.FineReport {
+	periods := reportPeriods(r, interval)
+	index := make(map[string]int, len(periods))
+	report := &models.FineReport{Periods: make([]models.FinePeriod, len(periods))}
+	for i, p := range periods {
+		index[p] = i
+		report.Periods[i].Period = p
+	}
+	for _, e := range entries {
+		var fp *models.FinePeriod
+		if i, ok := index[ReportPeriod(e.CreatedAt, interval)]; ok {
+			fp = &report.Periods[i]
+		} else {
+			fp = &models.FinePeriod{}
+		}
+		switch e.Type {
+		case FineCharge:
+			report.Charged += e.Amount
+			fp.Charged += e.Amount
+		case FinePayment:
+			report.Collected += e.Amount
+			fp.Collected += e.Amount
+		case FineWaiver:
+			report.Waived += e.Amount
+			fp.Waived += e.Amount
+		}
+	}
+	return report
+}
+
+// overdueRate menghitung porsi pinjaman terlambat; 0 jika tidak ada yang jatuh tempo.
+func overdueRate(o *models.OverdueReport) {
+	if o.Due > 0 {
+		o.OverdueRate = float64(o.Overdue) / float64(o.Due)
+	}
+}
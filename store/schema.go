@@ -0,0 +1,111 @@
+package store
+
+// mysqlSchema berisi DDL tabel-tabel SIMPUS untuk MySQL.
+var mysqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS users (
+		id VARCHAR(36) PRIMARY KEY,
+		username VARCHAR(255) NOT NULL UNIQUE,
+		password VARCHAR(255) NOT NULL,
+		role VARCHAR(50) NOT NULL,
+		fullname VARCHAR(255),
+		nip VARCHAR(50),
+		contact VARCHAR(255),
+		created_at DATETIME
+	)`,
+	`CREATE TABLE IF NOT EXISTS books (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		title VARCHAR(255) NOT NULL,
+		author VARCHAR(255) NOT NULL,
+		category VARCHAR(255),
+		stock INT DEFAULT 0,
+		image_url VARCHAR(255),
+		published_year INT,
+		created_at DATETIME
+	)`,
+	`CREATE TABLE IF NOT EXISTS loans (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		book_id INT NOT NULL,
+		loan_date DATETIME NOT NULL,
+		due_date DATETIME NOT NULL,
+		return_date DATETIME,
+		status VARCHAR(50) NOT NULL,
+		fine INT DEFAULT 0,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		FOREIGN KEY (book_id) REFERENCES books(id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS settings (
+		id INT PRIMARY KEY,
+		max_loan_books INT DEFAULT 3,
+		loan_duration INT DEFAULT 7,
+		fine_per_day INT DEFAULT 5000
+	)`,
+	`CREATE TABLE IF NOT EXISTS categories (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		name VARCHAR(100) NOT NULL UNIQUE
+	)`,
+	`CREATE TABLE IF NOT EXISTS notifications (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		message TEXT NOT NULL,
+		is_read BOOLEAN DEFAULT FALSE,
+		created_at DATETIME,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	)`,
+}
+
+// sqliteSchema berisi DDL yang sama untuk SQLite.
+// Perbedaan utama: AUTO_INCREMENT diganti INTEGER PRIMARY KEY AUTOINCREMENT
+// dan username/nama kategori dibandingkan tanpa memperhatikan huruf besar/kecil seperti collation MySQL.
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS users (
+		id VARCHAR(36) PRIMARY KEY,
+		username VARCHAR(255) NOT NULL UNIQUE COLLATE NOCASE,
+		password VARCHAR(255) NOT NULL,
+		role VARCHAR(50) NOT NULL,
+		fullname VARCHAR(255),
+		nip VARCHAR(50),
+		contact VARCHAR(255),
+		created_at DATETIME
+	)`,
+	`CREATE TABLE IF NOT EXISTS books (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title VARCHAR(255) NOT NULL,
+		author VARCHAR(255) NOT NULL,
+		category VARCHAR(255),
+		stock INT DEFAULT 0,
+		image_url VARCHAR(255),
+		published_year INT,
+		created_at DATETIME
+	)`,
+	`CREATE TABLE IF NOT EXISTS loans (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id VARCHAR(36) NOT NULL,
+		book_id INT NOT NULL,
+		loan_date DATETIME NOT NULL,
+		due_date DATETIME NOT NULL,
+		return_date DATETIME,
+		status VARCHAR(50) NOT NULL,
+		fine INT DEFAULT 0,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		FOREIGN KEY (book_id) REFERENCES books(id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS settings (
+		id INT PRIMARY KEY,
+		max_loan_books INT DEFAULT 3,
+		loan_duration INT DEFAULT 7,
+		fine_per_day INT DEFAULT 5000
+	)`,
+	`CREATE TABLE IF NOT EXISTS categories (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(100) NOT NULL UNIQUE COLLATE NOCASE
+	)`,
+	`CREATE TABLE IF NOT EXISTS notifications (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id VARCHAR(36) NOT NULL,
+		message TEXT NOT NULL,
+		is_read BOOLEAN DEFAULT FALSE,
+		created_at DATETIME,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	)`,
+}
@@ -0,0 +1,218 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// execer adalah bagian dari *sql.DB dan *sql.Tx yang dipakai helper bersama.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// syncBookStock menyamakan kolom books.stock dengan jumlah eksemplar yang tersedia.
+// Harus dipanggil di transaksi yang sama setiap kali status eksemplar berubah.
+func syncBookStock(ex execer, bookID int) error {
+	_, err := ex.Exec("UPDATE books SET stock = (SELECT COUNT(*) FROM book_items WHERE book_id = ? AND status = ?) WHERE id = ?",
+		bookID, ItemAvailable, bookID)
+	return err
+}
+
+// insertBookItem menambah satu eksemplar dengan nomor urut berikutnya untuk buku tersebut.
+func insertBookItem(ex execer, item *models.BookItem) error {
+	if err := ex.QueryRow("SELECT COALESCE(MAX(copy_no), 0) + 1 FROM book_items WHERE book_id = ?", item.BookID).Scan(&item.CopyNo); err != nil {
+		return err
+	}
+	if item.Barcode == "" {
+		item.Barcode = GenerateBarcode(item.BookID, item.CopyNo)
+	}
+	if item.Condition == "" {
+		item.Condition = ConditionGood
+	}
+	if item.Status == "" {
+		item.Status = ItemAvailable
+	}
+
+	var count int
+	if err := ex.QueryRow("SELECT COUNT(*) FROM book_items WHERE barcode = ?", item.Barcode).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBarcodeExists
+	}
+
+	item.CreatedAt = time.Now()
+	res, err := ex.Exec(`INSERT INTO book_items (book_id, copy_no, barcode, accession_number, item_condition, shelf_location, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.BookID, item.CopyNo, item.Barcode, item.AccessionNumber, item.Condition, item.ShelfLocation, item.Status, item.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	item.ID = int(id)
+	return nil
+}
+
+// CreateBookItem menambahkan eksemplar baru untuk sebuah buku.
+// Jika barcode kosong, barcode dibuat otomatis dari ID buku dan nomor eksemplar.
+func (s *SQLStore) CreateBookItem(item *models.BookItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM books WHERE id = ?", item.BookID).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrBookNotFound
+	}
+
+	if err := insertBookItem(tx, item); err != nil {
+		return err
+	}
+	if err := syncBookStock(tx, item.BookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const bookItemColumns = "i.id, i.book_id, i.copy_no, i.barcode, i.accession_number, i.item_condition, i.shelf_location, i.status, i.created_at, b.title"
+
+// scanBookItem membaca satu baris hasil query dengan kolom bookItemColumns.
+func scanBookItem(scan func(dest ...any) error) (*models.BookItem, error) {
+	var item models.BookItem
+	var accession, shelf sql.NullString
+	var title string
+	if err := scan(&item.ID, &item.BookID, &item.CopyNo, &item.Barcode, &accession, &item.Condition, &shelf, &item.Status, &item.CreatedAt, &title); err != nil {
+		return nil, err
+	}
+	item.AccessionNumber = accession.String
+	item.ShelfLocation = shelf.String
+	item.Book = &models.Book{ID: item.BookID, Title: title}
+	return &item, nil
+}
+
+// GetBookItems mengambil semua eksemplar milik sebuah buku, diurutkan berdasarkan nomor eksemplar.
+func (s *SQLStore) GetBookItems(bookID int) ([]models.BookItem, error) {
+	rows, err := s.db.Query("SELECT "+bookItemColumns+" FROM book_items i JOIN books b ON i.book_id = b.id WHERE i.book_id = ? ORDER BY i.copy_no", bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.BookItem
+	for rows.Next() {
+		item, err := scanBookItem(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// GetBookItemByID mengambil detail eksemplar berdasarkan ID.
+func (s *SQLStore) GetBookItemByID(id int) (*models.BookItem, error) {
+	item, err := scanBookItem(s.db.QueryRow("SELECT "+bookItemColumns+" FROM book_items i JOIN books b ON i.book_id = b.id WHERE i.id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	return item, err
+}
+
+// GetBookItemByBarcode mengambil detail eksemplar berdasarkan barcode.
+func (s *SQLStore) GetBookItemByBarcode(barcode string) (*models.BookItem, error) {
+	item, err := scanBookItem(s.db.QueryRow("SELECT "+bookItemColumns+" FROM book_items i JOIN books b ON i.book_id = b.id WHERE i.barcode = ?", barcode).Scan)
+	if err == sql.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	return item, err
+}
+
+// UpdateBookItem memperbarui barcode, nomor induk, kondisi, lokasi rak, dan status eksemplar.
+// Status "borrowed" dan "reserved" hanya diatur oleh proses peminjaman dan reservasi.
+func (s *SQLStore) UpdateBookItem(item *models.BookItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var bookID int
+	var status string
+	err = tx.QueryRow("SELECT book_id, status FROM book_items WHERE id = ?", item.ID).Scan(&bookID, &status)
+	if err == sql.ErrNoRows {
+		return ErrItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkItemStatusChange(status, item.Status); err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM book_items WHERE barcode = ? AND id <> ?", item.Barcode, item.ID).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBarcodeExists
+	}
+
+	_, err = tx.Exec("UPDATE book_items SET barcode=?, accession_number=?, item_condition=?, shelf_location=?, status=? WHERE id=?",
+		item.Barcode, item.AccessionNumber, item.Condition, item.ShelfLocation, item.Status, item.ID)
+	if err != nil {
+		return err
+	}
+	if err := syncBookStock(tx, bookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteBookItem menghapus eksemplar yang tidak sedang dipinjam dan tidak memiliki histori pinjaman.
+func (s *SQLStore) DeleteBookItem(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var bookID int
+	var status string
+	err = tx.QueryRow("SELECT book_id, status FROM book_items WHERE id = ?", id).Scan(&bookID, &status)
+	if err == sql.ErrNoRows {
+		return ErrItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status == ItemBorrowed {
+		return ErrItemBorrowed
+	}
+	if status == ItemReserved {
+		return ErrItemReserved
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM loans WHERE item_id = ?", id).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrItemHasLoans
+	}
+
+	if _, err := tx.Exec("DELETE FROM book_items WHERE id = ?", id); err != nil {
+		return err
+	}
+	if err := syncBookStock(tx, bookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
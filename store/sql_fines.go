@@ -0,0 +1,302 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// fineEntryColumns memuat entri denda beserta data anggota, judul buku pinjaman terkait,
+// dan username admin yang mencatat.
+const fineEntryColumns = `f.id, f.user_id, f.loan_id, f.entry_type, f.amount, f.method, f.receipt_no, f.reason, f.recorded_by, f.created_at,
+	u.username, u.fullname, b.id, b.title, r.username`
+
+const fineEntryFrom = ` FROM fine_entries f
+	JOIN users u ON f.user_id = u.id
+	LEFT JOIN loans l ON f.loan_id = l.id
+	LEFT JOIN books b ON l.book_id = b.id
+	LEFT JOIN users r ON f.recorded_by = r.id`
+
+// fineSums menghitung total tagihan, pembayaran, dan pembebasan. loanID 0 berarti semua pinjaman.
+const fineSums = `COALESCE(SUM(CASE WHEN entry_type = 'charge' THEN amount ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN entry_type = 'payment' THEN amount ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN entry_type = 'waiver' THEN amount ELSE 0 END), 0)`
+
+// scanFineEntry membaca satu baris hasil query dengan kolom fineEntryColumns.
+func scanFineEntry(scan func(dest ...any) error) (*models.FineEntry, error) {
+	var e models.FineEntry
+	var loanID, bookID sql.NullInt64
+	var method, receipt, reason, recordedBy, fullname, title, recorder sql.NullString
+	var username string
+	err := scan(&e.ID, &e.UserID, &loanID, &e.Type, &e.Amount, &method, &receipt, &reason, &recordedBy, &e.CreatedAt,
+		&username, &fullname, &bookID, &title, &recorder)
+	if err != nil {
+		return nil, err
+	}
+	e.LoanID = int(loanID.Int64)
+	e.Method = method.String
+	e.ReceiptNo = receipt.String
+	e.Reason = reason.String
+	e.RecordedBy = recordedBy.String
+	e.RecordedByName = recorder.String
+	e.User = &models.User{ID: e.UserID, Username: username, Fullname: fullname.String}
+	if bookID.Valid {
+		e.Book = &models.Book{ID: int(bookID.Int64), Title: title.String}
+	}
+	return &e, nil
+}
+
+// GetFineEntries mengambil entri buku besar denda milik user, terbaru lebih dulu.
+// userID kosong berarti semua anggota.
+func (s *SQLStore) GetFineEntries(userID string) ([]models.FineEntry, error) {
+	query := "SELECT " + fineEntryColumns + fineEntryFrom
+	var args []any
+	if userID != "" {
+		query += " WHERE f.user_id = ?"
+		args = append(args, userID)
+	}
+	rows, err := s.db.Query(query+" ORDER BY f.id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.FineEntry
+	for rows.Next() {
+		e, err := scanFineEntry(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// GetFineEntry mengambil satu entri buku besar denda beserta data anggota dan bukunya.
+func (s *SQLStore) GetFineEntry(id int) (*models.FineEntry, error) {
+	e, err := scanFineEntry(s.db.QueryRow("SELECT "+fineEntryColumns+fineEntryFrom+" WHERE f.id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, ErrFineNotFound
+	}
+	return e, err
+}
+
+// fineBalance menghitung saldo denda user (atau satu pinjamannya jika loanID bukan 0).
+func fineBalance(ex execer, userID string, loanID int) (*models.FineBalance, error) {
+	b := &models.FineBalance{UserID: userID}
+	query := "SELECT " + fineSums + " FROM fine_entries WHERE user_id = ?"
+	args := []any{userID}
+	if loanID != 0 {
+		query += " AND loan_id = ?"
+		args = append(args, loanID)
+	}
+	if err := ex.QueryRow(query, args...).Scan(&b.Charged, &b.Paid, &b.Waived); err != nil {
+		return nil, err
+	}
+	b.Outstanding = b.Charged - b.Paid - b.Waived
+	return b, nil
+}
+
+// GetFineBalance mengambil ringkasan saldo denda seorang anggota.
+func (s *SQLStore) GetFineBalance(userID string) (*models.FineBalance, error) {
+	var username string
+	var fullname sql.NullString
+	err := s.db.QueryRow("SELECT username, fullname FROM users WHERE id = ?", userID).Scan(&username, &fullname)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := fineBalance(s.db, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.QueryRow("SELECT COALESCE(SUM(accrued_fine), 0) FROM loans WHERE user_id = ? AND status IN ("+openLoanStatuses+")", userID).Scan(&b.Accruing)
+	if err != nil {
+		return nil, err
+	}
+	b.Username = username
+	b.Fullname = fullname.String
+	return b, nil
+}
+
+// GetOutstandingBalances mengambil anggota yang masih memiliki denda tertunggak atau denda
+// berjalan, tunggakan terbesar lebih dulu.
+func (s *SQLStore) GetOutstandingBalances() ([]models.FineBalance, error) {
+	rows, err := s.db.Query(`SELECT f.user_id, u.username, u.fullname, ` + fineSums + `
+		FROM fine_entries f
+		JOIN users u ON f.user_id = u.id
+		GROUP BY f.user_id, u.username, u.fullname`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byUser := make(map[string]*models.FineBalance)
+	for rows.Next() {
+		var b models.FineBalance
+		var fullname sql.NullString
+		if err := rows.Scan(&b.UserID, &b.Username, &fullname, &b.Charged, &b.Paid, &b.Waived); err != nil {
+			return nil, err
+		}
+		b.Fullname = fullname.String
+		b.Outstanding = b.Charged - b.Paid - b.Waived
+		byUser[b.UserID] = &b
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Denda berjalan dari pinjaman terlambat yang belum masuk buku besar
+	accRows, err := s.db.Query(`SELECT l.user_id, u.username, u.fullname, SUM(l.accrued_fine)
+		FROM loans l
+		JOIN users u ON l.user_id = u.id
+		WHERE l.status IN (` + openLoanStatuses + `) AND l.accrued_fine > 0
+		GROUP BY l.user_id, u.username, u.fullname`)
+	if err != nil {
+		return nil, err
+	}
+	defer accRows.Close()
+	for accRows.Next() {
+		var userID, username string
+		var fullname sql.NullString
+		var accruing int
+		if err := accRows.Scan(&userID, &username, &fullname, &accruing); err != nil {
+			return nil, err
+		}
+		b, ok := byUser[userID]
+		if !ok {
+			b = &models.FineBalance{UserID: userID, Username: username, Fullname: fullname.String}
+			byUser[userID] = b
+		}
+		b.Accruing = accruing
+	}
+	if err := accRows.Err(); err != nil {
+		return nil, err
+	}
+
+	var balances []models.FineBalance
+	for _, b := range byUser {
+		if b.Outstanding > 0 || b.Accruing > 0 {
+			balances = append(balances, *b)
+		}
+	}
+	sortBalances(balances)
+	return balances, nil
+}
+
+// RecordFinePayment mencatat pembayaran denda (penuh atau sebagian) beserta nomor kuitansi.
+// Nomor kuitansi dibuat otomatis jika tidak diisi.
+func (s *SQLStore) RecordFinePayment(entry *models.FineEntry) error {
+	entry.Type = FinePayment
+	return s.recordFineCredit(entry)
+}
+
+// WaiveFine mencatat pembebasan denda (penuh atau sebagian) beserta alasannya.
+func (s *SQLStore) WaiveFine(entry *models.FineEntry) error {
+	entry.Type = FineWaiver
+	entry.Method = ""
+	entry.ReceiptNo = ""
+	return s.recordFineCredit(entry)
+}
+
+// recordFineCredit menyimpan entri yang mengurangi saldo (pembayaran atau pembebasan)
+// setelah memastikan jumlahnya tidak melebihi saldo tertunggak anggota, dan juga saldo
+// pinjamannya jika LoanID diisi. Baris user dikunci lebih dulu (FOR UPDATE di MySQL; SQLite
+// sudah memakai BEGIN IMMEDIATE) agar dua pembayaran bersamaan tidak lolos pemeriksaan yang sama.
+func (s *SQLStore) recordFineCredit(entry *models.FineEntry) error {
+	if entry.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	lockQuery := "SELECT id FROM users WHERE id = ?"
+	if s.driver == DriverMySQL {
+		lockQuery += " FOR UPDATE"
+	}
+	var userID string
+	err = tx.QueryRow(lockQuery, entry.UserID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	var count int
+	if entry.LoanID != 0 {
+		if err := tx.QueryRow("SELECT COUNT(*) FROM loans WHERE id = ? AND user_id = ?", entry.LoanID, entry.UserID).Scan(&count); err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrLoanNotFound
+		}
+	}
+	if entry.ReceiptNo != "" {
+		if err := tx.QueryRow("SELECT COUNT(*) FROM fine_entries WHERE receipt_no = ?", entry.ReceiptNo).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrReceiptExists
+		}
+	}
+
+	balance, err := fineBalance(tx, entry.UserID, 0)
+	if err != nil {
+		return err
+	}
+	if entry.Amount > balance.Outstanding {
+		return ErrExceedsBalance
+	}
+	// Pembayaran per pinjaman juga dibatasi saldo pinjaman tersebut
+	if entry.LoanID != 0 {
+		loanBalance, err := fineBalance(tx, entry.UserID, entry.LoanID)
+		if err != nil {
+			return err
+		}
+		if entry.Amount > loanBalance.Outstanding {
+			return ErrExceedsBalance
+		}
+	}
+
+	entry.CreatedAt = time.Now()
+	res, err := tx.Exec("INSERT INTO fine_entries (user_id, loan_id, entry_type, amount, method, receipt_no, reason, recorded_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.UserID, nullInt(entry.LoanID), entry.Type, entry.Amount, nullString(entry.Method), nullString(entry.ReceiptNo),
+		nullString(entry.Reason), nullString(entry.RecordedBy), entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	entry.ID = int(id)
+
+	if entry.Type == FinePayment && entry.ReceiptNo == "" {
+		entry.ReceiptNo = GenerateReceiptNo(entry.CreatedAt, entry.ID)
+		if _, err := tx.Exec("UPDATE fine_entries SET receipt_no = ? WHERE id = ?", entry.ReceiptNo, entry.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// insertFineCharge mencatat tagihan denda dari pengembalian pinjaman di dalam transaksi.
+func insertFineCharge(ex execer, userID string, loanID, amount int, at time.Time) error {
+	_, err := ex.Exec("INSERT INTO fine_entries (user_id, loan_id, entry_type, amount, created_at) VALUES (?, ?, ?, ?, ?)",
+		userID, loanID, FineCharge, amount, at)
+	return err
+}
+
+// nullInt mengubah 0 menjadi NULL untuk kolom referensi opsional.
+func nullInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+// nullString mengubah string kosong menjadi NULL.
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
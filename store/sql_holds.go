@@ -0,0 +1,342 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// holdColumns memuat data reservasi beserta username, judul buku, barcode eksemplar,
+// dan posisi antrean (dihitung hanya untuk status "waiting").
+const holdColumns = `h.id, h.user_id, h.book_id, h.item_id, h.status, h.created_at, h.ready_at, h.expires_at, h.closed_at,
+	u.username, u.fullname, b.title, i.barcode,
+	CASE WHEN h.status = 'waiting' THEN (SELECT COUNT(*) FROM holds w WHERE w.book_id = h.book_id AND w.status = 'waiting' AND w.id <= h.id) ELSE 0 END`
+
+const holdFrom = ` FROM holds h
+	JOIN users u ON h.user_id = u.id
+	JOIN books b ON h.book_id = b.id
+	LEFT JOIN book_items i ON h.item_id = i.id`
+
+// scanHold membaca satu baris hasil query dengan kolom holdColumns.
+func scanHold(scan func(dest ...any) error) (*models.Hold, error) {
+	var h models.Hold
+	var itemID sql.NullInt64
+	var readyAt, expiresAt, closedAt sql.NullTime
+	var username, title string
+	var fullname, barcode sql.NullString
+	err := scan(&h.ID, &h.UserID, &h.BookID, &itemID, &h.Status, &h.CreatedAt, &readyAt, &expiresAt, &closedAt,
+		&username, &fullname, &title, &barcode, &h.Position)
+	if err != nil {
+		return nil, err
+	}
+	if readyAt.Valid {
+		t := readyAt.Time
+		h.ReadyAt = &t
+	}
+	if expiresAt.Valid {
+		t := expiresAt.Time
+		h.ExpiresAt = &t
+	}
+	if closedAt.Valid {
+		t := closedAt.Time
+		h.ClosedAt = &t
+	}
+	h.User = &models.User{ID: h.UserID, Username: username, Fullname: fullname.String}
+	h.Book = &models.Book{ID: h.BookID, Title: title}
+	if itemID.Valid {
+		h.ItemID = int(itemID.Int64)
+		h.Item = &models.BookItem{ID: h.ItemID, BookID: h.BookID, Barcode: barcode.String}
+	}
+	return &h, nil
+}
+
+// queryHolds menjalankan query reservasi dan memindai seluruh barisnya.
+func (s *SQLStore) queryHolds(where string, args ...any) ([]models.Hold, error) {
+	rows, err := s.db.Query("SELECT "+holdColumns+holdFrom+" "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []models.Hold
+	for rows.Next() {
+		h, err := scanHold(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, *h)
+	}
+	return holds, rows.Err()
+}
+
+// PlaceHold menambahkan user ke antrean reservasi sebuah buku.
+// Reservasi hanya boleh dibuat jika tidak ada eksemplar tersedia (atau antrean sudah ada).
+func (s *SQLStore) PlaceHold(userID string, bookID int) (*models.Hold, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var stock int
+	err = tx.QueryRow("SELECT stock FROM books WHERE id = ?", bookID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return nil, ErrBookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", userID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM holds WHERE user_id = ? AND book_id = ? AND status IN (?, ?)",
+		userID, bookID, HoldWaiting, HoldReady).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrHoldExists
+	}
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM loans WHERE user_id = ? AND book_id = ? AND status IN ("+openLoanStatuses+")", userID, bookID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrAlreadyBorrowing
+	}
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM holds WHERE book_id = ? AND status = ?", bookID, HoldWaiting).Scan(&count); err != nil {
+		return nil, err
+	}
+	if stock > 0 && count == 0 {
+		return nil, ErrBookAvailable
+	}
+
+	res, err := tx.Exec("INSERT INTO holds (user_id, book_id, status, created_at) VALUES (?, ?, ?, ?)",
+		userID, bookID, HoldWaiting, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	id, _ := res.LastInsertId()
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetHoldByID(int(id))
+}
+
+// GetHoldByID mengambil detail reservasi berdasarkan ID.
+func (s *SQLStore) GetHoldByID(id int) (*models.Hold, error) {
+	h, err := scanHold(s.db.QueryRow("SELECT "+holdColumns+holdFrom+" WHERE h.id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, ErrHoldNotFound
+	}
+	return h, err
+}
+
+// GetHoldsByUser mengambil seluruh reservasi milik user, terbaru lebih dulu.
+func (s *SQLStore) GetHoldsByUser(userID string) ([]models.Hold, error) {
+	return s.queryHolds("WHERE h.user_id = ? ORDER BY h.id DESC", userID)
+}
+
+// GetActiveHolds mengambil reservasi aktif (ready lalu waiting, urut antrean).
+// bookID 0 berarti semua buku.
+func (s *SQLStore) GetActiveHolds(bookID int) ([]models.Hold, error) {
+	order := " ORDER BY b.title, CASE WHEN h.status = 'ready' THEN 0 ELSE 1 END, h.id"
+	if bookID == 0 {
+		return s.queryHolds("WHERE h.status IN (?, ?)"+order, HoldWaiting, HoldReady)
+	}
+	return s.queryHolds("WHERE h.book_id = ? AND h.status IN (?, ?)"+order, bookID, HoldWaiting, HoldReady)
+}
+
+// CancelHold membatalkan reservasi aktif. Eksemplar yang sudah disisihkan dikembalikan ke rak;
+// pemanggil sebaiknya menjalankan AssignHolds agar eksemplar diteruskan ke antrean berikutnya.
+func (s *SQLStore) CancelHold(id int) (*models.Hold, error) {
+	if err := s.closeHold(id, HoldCancelled, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.GetHoldByID(id)
+}
+
+// closeHold menutup reservasi aktif dengan status tertentu dan melepas eksemplarnya.
+func (s *SQLStore) closeHold(id int, status string, now time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := closeHoldTx(tx, id, status, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// closeHoldTx adalah inti closeHold di dalam transaksi yang sudah berjalan.
+func closeHoldTx(tx *sql.Tx, id int, status string, now time.Time) error {
+	var bookID int
+	var current string
+	var itemID sql.NullInt64
+	err := tx.QueryRow("SELECT book_id, item_id, status FROM holds WHERE id = ?", id).Scan(&bookID, &itemID, &current)
+	if err == sql.ErrNoRows {
+		return ErrHoldNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if current != HoldWaiting && current != HoldReady {
+		return ErrHoldClosed
+	}
+
+	if _, err := tx.Exec("UPDATE holds SET status = ?, closed_at = ? WHERE id = ?", status, now, id); err != nil {
+		return err
+	}
+	if current == HoldReady && itemID.Valid {
+		if _, err := tx.Exec("UPDATE book_items SET status = ? WHERE id = ? AND status = ?", ItemAvailable, itemID.Int64, ItemReserved); err != nil {
+			return err
+		}
+		return syncBookStock(tx, bookID)
+	}
+	return nil
+}
+
+// AssignHolds menyisihkan eksemplar tersedia untuk antrean terdepan (FIFO) dan mengubah
+// reservasinya menjadi "ready" dengan batas pengambilan sesuai pengaturan.
+// bookID 0 berarti semua buku yang memiliki antrean. Mengembalikan reservasi yang baru siap.
+func (s *SQLStore) AssignHolds(bookID int) ([]models.Hold, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var bookIDs []int
+	if bookID != 0 {
+		bookIDs = append(bookIDs, bookID)
+	} else {
+		rows, err := tx.Query("SELECT DISTINCT book_id FROM holds WHERE status = ?", HoldWaiting)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			bookIDs = append(bookIDs, id)
+		}
+		rows.Close()
+	}
+
+	pickupDays := DefaultSettings().HoldPickupDays
+	if err := tx.QueryRow("SELECT hold_pickup_days FROM settings WHERE id = 1").Scan(&pickupDays); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var readyIDs []int
+	for _, bid := range bookIDs {
+		assigned := false
+		for {
+			var holdID, itemID int
+			err := tx.QueryRow("SELECT id FROM holds WHERE book_id = ? AND status = ? ORDER BY id LIMIT 1", bid, HoldWaiting).Scan(&holdID)
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			err = tx.QueryRow("SELECT id FROM book_items WHERE book_id = ? AND status = ? ORDER BY copy_no LIMIT 1", bid, ItemAvailable).Scan(&itemID)
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			// Kondisi status pada UPDATE mencegah eksemplar yang sama diambil transaksi paralel
+			res, err := tx.Exec("UPDATE book_items SET status = ? WHERE id = ? AND status = ?", ItemReserved, itemID, ItemAvailable)
+			if err != nil {
+				return nil, err
+			}
+			if n, _ := res.RowsAffected(); n != 1 {
+				break
+			}
+			readyAt := time.Now()
+			expiresAt := readyAt.AddDate(0, 0, pickupDays)
+			res, err = tx.Exec("UPDATE holds SET status = ?, item_id = ?, ready_at = ?, expires_at = ? WHERE id = ? AND status = ?",
+				HoldReady, itemID, readyAt, expiresAt, holdID, HoldWaiting)
+			if err != nil {
+				return nil, err
+			}
+			if n, _ := res.RowsAffected(); n != 1 {
+				return nil, ErrHoldClosed // Dibatalkan bersamaan; seluruh penugasan diulang di panggilan berikutnya
+			}
+			readyIDs = append(readyIDs, holdID)
+			assigned = true
+		}
+		if assigned {
+			if err := syncBookStock(tx, bid); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.holdsByIDs(readyIDs)
+}
+
+// ExpireHolds menandai reservasi "ready" yang melewati batas pengambilan sebagai "expired"
+// dan mengembalikan eksemplarnya ke rak. Mengembalikan reservasi yang kedaluwarsa.
+func (s *SQLStore) ExpireHolds(now time.Time) ([]models.Hold, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var ids []int
+	rows, err := tx.Query("SELECT id FROM holds WHERE status = ? AND expires_at < ?", HoldReady, now)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if err := closeHoldTx(tx, id, HoldExpired, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.holdsByIDs(ids)
+}
+
+// holdsByIDs mengambil detail beberapa reservasi sesuai urutan ID yang diberikan.
+func (s *SQLStore) holdsByIDs(ids []int) ([]models.Hold, error) {
+	var holds []models.Hold
+	for _, id := range ids {
+		h, err := s.GetHoldByID(id)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, *h)
+	}
+	return holds, nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// loadCalendar menyusun kalender dari pengaturan dan tanggal libur mulai tanggal since.
+// Tanggal libur sebelum since tidak dibutuhkan untuk menghitung jatuh tempo maupun denda.
+func loadCalendar(ex execer, since time.Time) (*Calendar, error) {
+	var closed int
+	err := ex.QueryRow("SELECT closed_weekdays FROM settings WHERE id = 1").Scan(&closed)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	rows, err := ex.Query("SELECT holiday_date FROM holidays WHERE holiday_date >= ?", since.Local().Format(DateLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cal := NewCalendar(closed, nil)
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		cal.Holidays[date] = true
+	}
+	return cal, rows.Err()
+}
+
+// GetHolidays mengambil semua tanggal libur, urut tanggal.
+func (s *SQLStore) GetHolidays() ([]models.Holiday, error) {
+	rows, err := s.db.Query("SELECT id, holiday_date, name FROM holidays ORDER BY holiday_date")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holidays []models.Holiday
+	for rows.Next() {
+		var h models.Holiday
+		if err := rows.Scan(&h.ID, &h.Date, &h.Name); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+// CreateHoliday menambahkan satu tanggal libur.
+func (s *SQLStore) CreateHoliday(h *models.Holiday) error {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM holidays WHERE holiday_date = ?", h.Date).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrHolidayExists
+	}
+
+	res, err := s.db.Exec("INSERT INTO holidays (holiday_date, name) VALUES (?, ?)", h.Date, h.Name)
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	h.ID = int(id)
+	return nil
+}
+
+// DeleteHoliday menghapus tanggal libur.
+func (s *SQLStore) DeleteHoliday(id int) error {
+	res, err := s.db.Exec("DELETE FROM holidays WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
+
+// ImportHolidays menambahkan banyak tanggal libur sekaligus dalam satu transaksi.
+// Tanggal yang sudah terdaftar dilewati. Mengembalikan jumlah tanggal yang ditambahkan.
+func (s *SQLStore) ImportHolidays(holidays []models.Holiday) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	added := 0
+	for _, h := range holidays {
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM holidays WHERE holiday_date = ?", h.Date).Scan(&count); err != nil {
+			return 0, err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := tx.Exec("INSERT INTO holidays (holiday_date, name) VALUES (?, ?)", h.Date, h.Name); err != nil {
+			return 0, err
+		}
+		added++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+)
+
+const jobRunColumns = `id, job_name, scheduled_at, trigger_type, triggered_by, instance, status,
+	started_at, finished_at, duration_ms, COALESCE(error_message, '')`
+
+// scanJobRuns membaca baris hasil query dengan kolom jobRunColumns.
+func scanJobRuns(rows *sql.Rows) ([]models.JobRun, error) {
+	defer rows.Close()
+
+	var runs []models.JobRun
+	for rows.Next() {
+		var r models.JobRun
+		var finishedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.JobName, &r.ScheduledAt, &r.Trigger, &r.TriggeredBy, &r.Instance, &r.Status,
+			&r.StartedAt, &finishedAt, &r.DurationMs, &r.Error); err != nil {
+			return nil, err
+		}
+		if finishedAt.Valid {
+			r.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// StartJobRun mengklaim slot jadwal dengan menyisipkan baris job_runs. Constraint UNIQUE
+// (job_name, scheduled_at) menolak sisipan kedua, baik dari instance lain maupun instance yang sama.
+func (s *SQLStore) StartJobRun(run *models.JobRun) error {
+	run.Status = JobRunning
+	res, err := s.db.Exec(`INSERT INTO job_runs
+		(job_name, scheduled_at, trigger_type, triggered_by, instance, status, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.JobName, run.ScheduledAt, run.Trigger, run.TriggeredBy, run.Instance, run.Status, run.StartedAt)
+	if err != nil {
+		// Kode galat duplikat berbeda antar driver, jadi periksa langsung apakah slot sudah terisi
+		var count int
+		if qerr := s.db.QueryRow("SELECT COUNT(*) FROM job_runs WHERE job_name = ? AND scheduled_at = ?",
+			run.JobName, run.ScheduledAt).Scan(&count); qerr == nil && count > 0 {
+			return ErrJobClaimed
+		}
+		return err
+	}
+	id, _ := res.LastInsertId()
+	run.ID = int(id)
+	return nil
+}
+
+// FinishJobRun mencatat hasil run dan memperbarui ringkasan run terakhir di tabel jobs.
+func (s *SQLStore) FinishJobRun(run *models.JobRun) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE job_runs SET status = ?, finished_at = ?, duration_ms = ?, error_message = ? WHERE id = ?",
+		run.Status, run.FinishedAt, run.DurationMs, run.Error, run.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrJobRunNotFound
+	}
+
+	// Upsert ringkasan dengan hapus lalu sisipkan agar sama di MySQL dan SQLite
+	if _, err := tx.Exec("DELETE FROM jobs WHERE name = ?", run.JobName); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO jobs (name, last_run_at, last_status, last_duration_ms, last_error) VALUES (?, ?, ?, ?, ?)",
+		run.JobName, run.StartedAt, run.Status, run.DurationMs, run.Error); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetJobs mengambil ringkasan run terakhir semua job yang pernah dijalankan.
+func (s *SQLStore) GetJobs() ([]models.Job, error) {
+	rows, err := s.db.Query("SELECT name, last_run_at, last_status, last_duration_ms, COALESCE(last_error, '') FROM jobs ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var j models.Job
+		var lastRunAt sql.NullTime
+		if err := rows.Scan(&j.Name, &lastRunAt, &j.LastStatus, &j.LastDurationMs, &j.LastError); err != nil {
+			return nil, err
+		}
+		if lastRunAt.Valid {
+			j.LastRunAt = &lastRunAt.Time
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// GetJobRuns mengambil riwayat run terbaru, difilter nama job jika tidak kosong.
+func (s *SQLStore) GetJobRuns(name string, limit int) ([]models.JobRun, error) {
+	query := "SELECT " + jobRunColumns + " FROM job_runs"
+	args := []interface{}{}
+	if name != "" {
+		query += " WHERE job_name = ?"
+		args = append(args, name)
+	}
+	query += " ORDER BY started_at DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanJobRuns(rows)
+}
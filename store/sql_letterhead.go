@@ -0,0 +1,57 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// letterheadColumns adalah kolom tabel letterhead sesuai urutan letterheadValues.
+const letterheadColumns = "library_name, institution, address, city, phone, email, website, head_name, head_nip, updated_by, updated_at"
+
+func letterheadValues(lh *models.Letterhead) []any {
+	return []any{lh.LibraryName, lh.Institution, lh.Address, lh.City, lh.Phone, lh.Email, lh.Website,
+		lh.HeadName, lh.HeadNIP, lh.UpdatedBy, lh.UpdatedAt}
+}
+
+// GetLetterhead mengambil kop surat; DefaultLetterhead jika belum pernah diatur.
+func (s *SQLStore) GetLetterhead() (*models.Letterhead, error) {
+	var lh models.Letterhead
+	var updatedAt sql.NullTime
+	err := s.db.QueryRow("SELECT "+letterheadColumns+" FROM letterhead WHERE id = 1").Scan(
+		&lh.LibraryName, &lh.Institution, &lh.Address, &lh.City, &lh.Phone, &lh.Email, &lh.Website,
+		&lh.HeadName, &lh.HeadNIP, &lh.UpdatedBy, &updatedAt)
+	if err == sql.ErrNoRows {
+		return DefaultLetterhead(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lh.UpdatedAt = updatedAt.Time
+	return &lh, nil
+}
+
+// UpdateLetterhead menyimpan kop surat dan mengisi lh.UpdatedAt.
+func (s *SQLStore) UpdateLetterhead(lh *models.Letterhead) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	lh.UpdatedAt = time.Now()
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM letterhead WHERE id = 1").Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		_, err = tx.Exec("INSERT INTO letterhead (id, "+letterheadColumns+") VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", letterheadValues(lh)...)
+	} else {
+		_, err = tx.Exec(`UPDATE letterhead SET library_name=?, institution=?, address=?, city=?, phone=?, email=?, website=?,
+			head_name=?, head_nip=?, updated_by=?, updated_at=? WHERE id = 1`, letterheadValues(lh)...)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+)
+
+const loanPolicyColumns = "id, role, category, max_loan_books, loan_duration, fine_per_day, max_renewals"
+
+// scanLoanPolicy membaca satu baris hasil query dengan kolom loanPolicyColumns.
+func scanLoanPolicy(scan func(dest ...any) error) (*models.LoanPolicy, error) {
+	var p models.LoanPolicy
+	if err := scan(&p.ID, &p.Role, &p.Category, &p.MaxLoanBooks, &p.LoanDuration, &p.FinePerDay, &p.MaxRenewals); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetLoanPolicies mengambil semua kebijakan peminjaman, diurutkan per role lalu kategori.
+func (s *SQLStore) GetLoanPolicies() ([]models.LoanPolicy, error) {
+	rows, err := s.db.Query("SELECT " + loanPolicyColumns + " FROM loan_policies ORDER BY role, category")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []models.LoanPolicy
+	for rows.Next() {
+		p, err := scanLoanPolicy(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *p)
+	}
+	return policies, rows.Err()
+}
+
+// GetLoanPolicyByID mengambil satu kebijakan peminjaman.
+func (s *SQLStore) GetLoanPolicyByID(id int) (*models.LoanPolicy, error) {
+	p, err := scanLoanPolicy(s.db.QueryRow("SELECT "+loanPolicyColumns+" FROM loan_policies WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, ErrPolicyNotFound
+	}
+	return p, err
+}
+
+// policyExists memeriksa apakah kombinasi role dan kategori sudah dipakai kebijakan lain.
+func (s *SQLStore) policyExists(role, category string, exceptID int) (bool, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM loan_policies WHERE role = ? AND category = ? AND id <> ?", role, category, exceptID).Scan(&count)
+	return count > 0, err
+}
+
+// CreateLoanPolicy menambahkan kebijakan peminjaman baru.
+func (s *SQLStore) CreateLoanPolicy(p *models.LoanPolicy) error {
+	exists, err := s.policyExists(p.Role, p.Category, 0)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrPolicyExists
+	}
+
+	res, err := s.db.Exec("INSERT INTO loan_policies (role, category, max_loan_books, loan_duration, fine_per_day, max_renewals) VALUES (?, ?, ?, ?, ?, ?)",
+		p.Role, p.Category, p.MaxLoanBooks, p.LoanDuration, p.FinePerDay, p.MaxRenewals)
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	p.ID = int(id)
+	return nil
+}
+
+// UpdateLoanPolicy memperbarui kebijakan peminjaman.
+func (s *SQLStore) UpdateLoanPolicy(p *models.LoanPolicy) error {
+	if _, err := s.GetLoanPolicyByID(p.ID); err != nil {
+		return err
+	}
+	exists, err := s.policyExists(p.Role, p.Category, p.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrPolicyExists
+	}
+
+	_, err = s.db.Exec("UPDATE loan_policies SET role=?, category=?, max_loan_books=?, loan_duration=?, fine_per_day=?, max_renewals=? WHERE id=?",
+		p.Role, p.Category, p.MaxLoanBooks, p.LoanDuration, p.FinePerDay, p.MaxRenewals, p.ID)
+	return err
+}
+
+// DeleteLoanPolicy menghapus kebijakan peminjaman.
+func (s *SQLStore) DeleteLoanPolicy(id int) error {
+	res, err := s.db.Exec("DELETE FROM loan_policies WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// finePerDayForLoan menentukan denda per hari untuk pinjaman berdasarkan role peminjam dan
+// kategori buku, dengan urutan yang sama seperti ResolveLoanPolicy.
+func finePerDayForLoan(ex execer, loanID int) (int, error) {
+	var role, category string
+	err := ex.QueryRow(`SELECT u.role, COALESCE(b.category, '') FROM loans l
+		JOIN users u ON l.user_id = u.id
+		JOIN books b ON l.book_id = b.id
+		WHERE l.id = ?`, loanID).Scan(&role, &category)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	var fine int
+	// Kategori kosong (semua kategori) selalu terurut paling akhir
+	err = ex.QueryRow("SELECT fine_per_day FROM loan_policies WHERE role = ? AND (category = ? OR category = '') ORDER BY category DESC LIMIT 1",
+		role, category).Scan(&fine)
+	if err == nil {
+		return fine, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	if err := ex.QueryRow("SELECT fine_per_day FROM settings WHERE id = 1").Scan(&fine); err != nil {
+		return DefaultSettings().FinePerDay, nil
+	}
+	return fine, nil
+}
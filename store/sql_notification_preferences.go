@@ -0,0 +1,45 @@
+package store
+
+import "latihan_cloud8/models"
+
+// GetNotificationPreferences mengambil preferensi untuk semua kategori notifikasi.
+func (s *SQLStore) GetNotificationPreferences(userID string) ([]models.NotificationPreference, error) {
+	rows, err := s.db.Query("SELECT category, enabled, email FROM notification_preferences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var saved []models.NotificationPreference
+	for rows.Next() {
+		var p models.NotificationPreference
+		if err := rows.Scan(&p.Category, &p.Enabled, &p.Email); err != nil {
+			return nil, err
+		}
+		saved = append(saved, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return mergeNotificationPreferences(saved), nil
+}
+
+// SetNotificationPreferences menyimpan preferensi; kategori yang tidak dikirim tidak diubah.
+func (s *SQLStore) SetNotificationPreferences(userID string, prefs []models.NotificationPreference) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, p := range prefs {
+		if _, err := tx.Exec("DELETE FROM notification_preferences WHERE user_id = ? AND category = ?", userID, p.Category); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO notification_preferences (user_id, category, enabled, email) VALUES (?, ?, ?, ?)",
+			userID, p.Category, p.Enabled, p.Email); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+const outboxColumns = `id, COALESCE(notification_id, 0), user_id, channel, recipient, subject, body_text, body_html,
+	status, attempts, COALESCE(last_error, ''), next_attempt_at, created_at, sent_at`
+
+// scanOutbox membaca baris hasil query dengan kolom outboxColumns.
+func scanOutbox(rows *sql.Rows) ([]models.OutboxMessage, error) {
+	defer rows.Close()
+
+	var msgs []models.OutboxMessage
+	for rows.Next() {
+		var m models.OutboxMessage
+		var sentAt sql.NullTime
+		if err := rows.Scan(&m.ID, &m.NotificationID, &m.UserID, &m.Channel, &m.Recipient, &m.Subject, &m.BodyText, &m.BodyHTML,
+			&m.Status, &m.Attempts, &m.LastError, &m.NextAttemptAt, &m.CreatedAt, &sentAt); err != nil {
+			return nil, err
+		}
+		if sentAt.Valid {
+			m.SentAt = &sentAt.Time
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, rows.Err()
+}
+
+// EnqueueOutbox menyimpan pesan baru berstatus pending yang siap dikirim segera.
+func (s *SQLStore) EnqueueOutbox(msg *models.OutboxMessage) error {
+	msg.Status = OutboxPending
+	msg.Attempts = 0
+	msg.CreatedAt = time.Now()
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = msg.CreatedAt
+	}
+
+	var notifID interface{}
+	if msg.NotificationID > 0 {
+		notifID = msg.NotificationID
+	}
+	res, err := s.db.Exec(`INSERT INTO notification_outbox
+		(notification_id, user_id, channel, recipient, subject, body_text, body_html, status, attempts, next_attempt_at, created_at, message)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?)`,
+		notifID, msg.UserID, msg.Channel, msg.Recipient, msg.Subject, msg.BodyText, msg.BodyHTML, msg.Status, msg.NextAttemptAt, msg.CreatedAt, nullString(msg.Message))
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	msg.ID = int(id)
+	return nil
+}
+
+// GetDueOutbox mengambil pesan pending yang jadwal kirimnya sudah tiba, terlama lebih dulu,
+// termasuk pesan sending yang klaimnya sudah kedaluwarsa.
+func (s *SQLStore) GetDueOutbox(now time.Time, limit int) ([]models.OutboxMessage, error) {
+	rows, err := s.db.Query("SELECT "+outboxColumns+" FROM notification_outbox WHERE status IN (?, ?) AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?",
+		OutboxPending, OutboxSending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutbox(rows)
+}
+
+// GetOutbox mengambil pesan terbaru, difilter status jika tidak kosong.
+func (s *SQLStore) GetOutbox(status string, limit int) ([]models.OutboxMessage, error) {
+	query := "SELECT " + outboxColumns + " FROM notification_outbox"
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutbox(rows)
+}
+
+// ClaimOutbox menandai pesan sending secara atomik sebelum dikirim, sehingga dua worker
+// (atau dua instance aplikasi) tidak mengirim pesan yang sama. next_attempt_at diisi batas
+// klaim agar pesan diambil lagi jika pengiriman tidak pernah selesai.
+func (s *SQLStore) ClaimOutbox(id int, now time.Time) (bool, error) {
+	res, err := s.db.Exec(`UPDATE notification_outbox SET status = ?, next_attempt_at = ?
+		WHERE id = ? AND (status = ? OR status = ?) AND next_attempt_at <= ?`,
+		OutboxSending, now.Add(OutboxClaimTimeout), id, OutboxPending, OutboxSending, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// MarkOutboxSent menandai pesan berhasil terkirim.
+func (s *SQLStore) MarkOutboxSent(id int, sentAt time.Time) error {
+	return s.updateOutboxRow("UPDATE notification_outbox SET status = ?, attempts = attempts + 1, last_error = NULL, sent_at = ? WHERE id = ?",
+		OutboxSent, sentAt, id)
+}
+
+// MarkOutboxFailed mencatat percobaan yang gagal; retryAt nil berarti pesan tidak dicoba lagi.
+func (s *SQLStore) MarkOutboxFailed(id int, lastError string, retryAt *time.Time) error {
+	if retryAt == nil {
+		return s.updateOutboxRow("UPDATE notification_outbox SET status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?",
+			OutboxFailed, lastError, id)
+	}
+	return s.updateOutboxRow("UPDATE notification_outbox SET status = ?, attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?",
+		OutboxPending, lastError, *retryAt, id)
+}
+
+// RetryOutbox menjadwalkan ulang pesan yang belum terkirim agar segera dikirim.
+// Jumlah percobaan direset sehingga pesan mendapat jatah percobaan penuh lagi.
+func (s *SQLStore) RetryOutbox(id int) error {
+	var status string
+	err := s.db.QueryRow("SELECT status FROM notification_outbox WHERE id = ?", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return ErrOutboxNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status == OutboxSent {
+		return ErrOutboxSent
+	}
+	_, err = s.db.Exec("UPDATE notification_outbox SET status = ?, attempts = 0, next_attempt_at = ? WHERE id = ?",
+		OutboxPending, time.Now(), id)
+	return err
+}
+
+// updateOutboxRow menjalankan UPDATE satu pesan outbox dan mengembalikan ErrOutboxNotFound jika ID tidak ada.
+func (s *SQLStore) updateOutboxRow(query string, args ...interface{}) error {
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrOutboxNotFound
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// RenewLoan memperpanjang jatuh tempo pinjaman aktif sebanyak days hari dari jatuh tempo lama
+// dan mencatatnya di riwayat perpanjangan. Ditolak jika pinjaman sudah terlambat, batas
+// maxRenewals tercapai, atau judul tersebut masih memiliki antrean reservasi.
+func (s *SQLStore) RenewLoan(loanID int, renewedBy string, days, maxRenewals int) (*models.Loan, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var bookID, renewals int
+	var status string
+	var dueDate time.Time
+	err = tx.QueryRow("SELECT book_id, status, due_date, renewal_count FROM loans WHERE id = ?", loanID).
+		Scan(&bookID, &status, &dueDate, &renewals)
+	if err == sql.ErrNoRows {
+		return nil, ErrLoanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if status == LoanReturned {
+		return nil, ErrAlreadyReturned
+	}
+	if now.After(dueDate) {
+		return nil, ErrLoanOverdue
+	}
+	if renewals >= maxRenewals {
+		return nil, ErrRenewalLimit
+	}
+
+	var waiting int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM holds WHERE book_id = ? AND status = ?", bookID, HoldWaiting).Scan(&waiting); err != nil {
+		return nil, err
+	}
+	if waiting > 0 {
+		return nil, ErrHoldsPending
+	}
+
+	// Kondisi renewal_count pada UPDATE mencegah dua perpanjangan paralel melewati batas
+	cal, err := loadCalendar(tx, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	newDue := cal.DueDate(dueDate, days)
+	res, err := tx.Exec("UPDATE loans SET due_date = ?, renewal_count = renewal_count + 1 WHERE id = ? AND renewal_count = ?",
+		newDue, loanID, renewals)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		return nil, ErrRenewalLimit
+	}
+
+	if _, err := tx.Exec("INSERT INTO loan_renewals (loan_id, renewed_by, old_due_date, new_due_date, renewed_at) VALUES (?, ?, ?, ?, ?)",
+		loanID, renewedBy, dueDate, newDue, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetLoanByID(loanID)
+}
+
+// GetLoanRenewals mengambil riwayat perpanjangan sebuah pinjaman, terlama lebih dulu.
+func (s *SQLStore) GetLoanRenewals(loanID int) ([]models.LoanRenewal, error) {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.loan_id, r.renewed_by, r.old_due_date, r.new_due_date, r.renewed_at, u.username
+		FROM loan_renewals r
+		LEFT JOIN users u ON r.renewed_by = u.id
+		WHERE r.loan_id = ?
+		ORDER BY r.id`, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var renewals []models.LoanRenewal
+	for rows.Next() {
+		var r models.LoanRenewal
+		var renewedBy, username sql.NullString
+		if err := rows.Scan(&r.ID, &r.LoanID, &renewedBy, &r.OldDueDate, &r.NewDueDate, &r.RenewedAt, &username); err != nil {
+			return nil, err
+		}
+		r.RenewedBy = renewedBy.String
+		r.RenewedName = username.String
+		renewals = append(renewals, r)
+	}
+	return renewals, rows.Err()
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"time"
+)
+
+// overdueCase bernilai 1 untuk pinjaman yang dikembalikan setelah jatuh tempo atau belum
+// dikembalikan padahal jatuh tempo sebelum parameter now. Sama dengan isOverdue.
+const overdueCase = `CASE WHEN (l.return_date IS NOT NULL AND l.return_date > l.due_date)
+	OR (l.return_date IS NULL AND l.due_date < ?) THEN 1 ELSE 0 END`
+
+// queryTimes mengambil satu kolom waktu dari query.
+func (s *SQLStore) queryTimes(query string, args ...any) ([]time.Time, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, rows.Err()
+}
+
+// GetLoanVolume menghitung jumlah peminjaman dan pengembalian per periode.
+// Pengelompokan periode dilakukan di Go agar sama untuk MySQL dan SQLite.
+func (s *SQLStore) GetLoanVolume(r models.ReportRange, interval string) ([]models.LoanVolume, error) {
+	loanDates, err := s.queryTimes("SELECT loan_date FROM loans WHERE loan_date >= ? AND loan_date < ?", r.Start, r.End)
+	if err != nil {
+		return nil, err
+	}
+	returnDates, err := s.queryTimes("SELECT return_date FROM loans WHERE return_date >= ? AND return_date < ?", r.Start, r.End)
+	if err != nil {
+		return nil, err
+	}
+	return loanVolume(r, interval, loanDates, returnDates), nil
+}
+
+// GetTopTitles mengambil judul yang paling sering dipinjam dalam rentang.
+func (s *SQLStore) GetTopTitles(r models.ReportRange, limit int) ([]models.TitleCount, error) {
+	rows, err := s.db.Query(`SELECT b.id, b.title, b.author, b.category, COUNT(*) AS total
+		FROM loans l
+		JOIN books b ON l.book_id = b.id
+		WHERE l.loan_date >= ? AND l.loan_date < ?
+		GROUP BY b.id, b.title, b.author, b.category
+		ORDER BY total DESC, b.title
+		LIMIT ?`, r.Start, r.End, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	titles := []models.TitleCount{}
+	for rows.Next() {
+		var t models.TitleCount
+		var author, category sql.NullString
+		if err := rows.Scan(&t.BookID, &t.Title, &author, &category, &t.Loans); err != nil {
+			return nil, err
+		}
+		t.Author = author.String
+		t.Category = category.String
+		titles = append(titles, t)
+	}
+	return titles, rows.Err()
+}
+
+// GetTopCategories mengambil kategori yang paling sering dipinjam dalam rentang.
+func (s *SQLStore) GetTopCategories(r models.ReportRange, limit int) ([]models.CategoryCount, error) {
+	rows, err := s.db.Query(`SELECT COALESCE(b.category, ''), COUNT(*) AS total
+		FROM loans l
+		JOIN books b ON l.book_id = b.id
+		WHERE l.loan_date >= ? AND l.loan_date < ?
+		GROUP BY COALESCE(b.category, '')
+		ORDER BY total DESC, COALESCE(b.category, '')
+		LIMIT ?`, r.Start, r.End, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.CategoryCount{}
+	for rows.Next() {
+		var c models.CategoryCount
+		if err := rows.Scan(&c.Category, &c.Loans); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// GetTopBorrowers mengambil anggota dengan peminjaman terbanyak dalam rentang beserta
+// total denda final pinjaman tersebut.
+func (s *SQLStore) GetTopBorrowers(r models.ReportRange, limit int) ([]models.BorrowerCount, error) {
+	rows, err := s.db.Query(`SELECT u.id, u.username, u.fullname, u.role, COUNT(*) AS total, COALESCE(SUM(l.fine), 0)
+		FROM loans l
+		JOIN users u ON l.user_id = u.id
+		WHERE l.loan_date >= ? AND l.loan_date < ?
+		GROUP BY u.id, u.username, u.fullname, u.role
+		ORDER BY total DESC, u.username
+		LIMIT ?`, r.Start, r.End, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	borrowers := []models.BorrowerCount{}
+	for rows.Next() {
+		var b models.BorrowerCount
+		var fullname sql.NullString
+		if err := rows.Scan(&b.UserID, &b.Username, &fullname, &b.Role, &b.Loans, &b.Fines); err != nil {
+			return nil, err
+		}
+		b.Fullname = fullname.String
+		borrowers = append(borrowers, b)
+	}
+	return borrowers, rows.Err()
+}
+
+// GetFineReport menjumlahkan tagihan, pembayaran dan pembebasan denda per periode,
+// ditambah denda berjalan pinjaman yang belum dikembalikan saat ini.
+func (s *SQLStore) GetFineReport(r models.ReportRange, interval string) (*models.FineReport, error) {
+	rows, err := s.db.Query("SELECT entry_type, amount, created_at FROM fine_entries WHERE created_at >= ? AND created_at < ?", r.Start, r.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.FineEntry
+	for rows.Next() {
+		var e models.FineEntry
+		if err := rows.Scan(&e.Type, &e.Amount, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report := fineReport(r, interval, entries)
+	err = s.db.QueryRow("SELECT COALESCE(SUM(accrued_fine), 0) FROM loans WHERE status IN (" + openLoanStatuses + ")").Scan(&report.Accruing)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetOverdueReport menghitung tingkat keterlambatan pinjaman yang jatuh tempo dalam rentang
+// dan sebelum now.
+func (s *SQLStore) GetOverdueReport(r models.ReportRange, now time.Time) (*models.OverdueReport, error) {
+	var o models.OverdueReport
+	err := s.db.QueryRow(`SELECT COUNT(*),
+		COALESCE(SUM(`+overdueCase+`), 0),
+		COALESCE(SUM(CASE WHEN l.return_date IS NULL THEN 1 ELSE 0 END), 0)
+		FROM loans l
+		WHERE l.due_date >= ? AND l.due_date < ? AND l.due_date < ?`,
+		now, r.Start, r.End, now).Scan(&o.Due, &o.Overdue, &o.StillOpen)
+	if err != nil {
+		return nil, err
+	}
+	overdueRate(&o)
+	return &o, nil
+}
+
+// GetRoleReport merangkum pinjaman dalam rentang per role anggota, terbanyak lebih dulu.
+func (s *SQLStore) GetRoleReport(r models.ReportRange, now time.Time) ([]models.RoleReport, error) {
+	rows, err := s.db.Query(`SELECT u.role, COUNT(DISTINCT l.user_id), COUNT(*) AS total,
+		COALESCE(SUM(CASE WHEN l.return_date IS NOT NULL THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(`+overdueCase+`), 0),
+		COALESCE(SUM(CASE WHEN l.return_date IS NOT NULL THEN l.fine ELSE 0 END), 0)
+		FROM loans l
+		JOIN users u ON l.user_id = u.id
+		WHERE l.loan_date >= ? AND l.loan_date < ?
+		GROUP BY u.role
+		ORDER BY total DESC, u.role`, now, r.Start, r.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.RoleReport{}
+	for rows.Next() {
+		var rr models.RoleReport
+		if err := rows.Scan(&rr.Role, &rr.Borrowers, &rr.Loans, &rr.Returned, &rr.Overdue, &rr.Fines); err != nil {
+			return nil, err
+		}
+		roles = append(roles, rr)
+	}
+	return roles, rows.Err()
+}
@@ -0,0 +1,1358 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"latihan_cloud8/models"
+	"log"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+)
+
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+)
+
+// SQLStore adalah implementasi Store di atas database SQL (MySQL atau SQLite).
+// Seluruh query ditulis agar kompatibel dengan kedua dialek; perbedaan DDL
+// ditangani oleh migrasi (lihat migrations.go) berdasarkan driver.
+type SQLStore struct {
+	db        *sql.DB
+	driver    string
+	publisher NotificationPublisher
+}
+
+var (
+	_ Store         = (*SQLStore)(nil)
+	_ HealthChecker = (*SQLStore)(nil)
+)
+
+// NewMySQLStore menginisialisasi koneksi database MySQL baru.
+// Fungsi ini membuka koneksi dan melakukan ping untuk memastikan database aktif.
+func NewMySQLStore(dsn string) (*SQLStore, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	return &SQLStore{db: db, driver: DriverMySQL}, nil
+}
+
+// NewSQLiteStore membuka (atau membuat) database SQLite pada path yang diberikan.
+// Foreign key diaktifkan, transaksi memakai BEGIN IMMEDIATE, dan koneksi dibatasi satu
+// agar transaksi tulis (misal BorrowBook/ReturnBook) berjalan berurutan seperti row lock pada MySQL.
+// Waktu disimpan dalam UTC dengan format teks SQLite (lihat sqliteConnector) sehingga
+// perbandingan rentang waktu sebagai teks tetap benar.
+func NewSQLiteStore(path string) (*SQLStore, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate&_time_format=sqlite"
+	db := sql.OpenDB(sqliteConnector{dsn: dsn})
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLStore{db: db, driver: DriverSQLite}, nil
+}
+
+// Close menutup koneksi database.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// Ping memastikan database masih bisa dihubungi.
+func (s *SQLStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// DBStats mengembalikan statistik pool koneksi database.
+func (s *SQLStore) DBStats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// InitSchema menyiapkan skema database dengan menerapkan seluruh migrasi yang tertunda.
+// Tabel meliputi: users, books, loans, categories, settings, notifications.
+func (s *SQLStore) InitSchema() error {
+	_, err := s.MigrateUp()
+	return err
+}
+
+// ==========================================
+// USER
+// ==========================================
+
+// CreateUser menambahkan pengguna baru ke database.
+// Melakukan pengecekan duplikasi username sebelum insert.
+func (s *SQLStore) CreateUser(username, hashedPassword, role, fullname string) (*models.User, error) {
+	// Check if user exists
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM users WHERE username = ?", username).Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrUserExists
+	}
+
+	// Create new user
+	id := uuid.NewString()
+	_, err = s.db.Exec(
+		"INSERT INTO users (id, username, password, role, fullname, nip, contact, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		id, username, hashedPassword, role, fullname, "", "", time.Now(), // Default empty NIP/Contact for now, or update signature
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{
+		ID:        id,
+		Username:  username,
+		Password:  hashedPassword,
+		Role:      role,
+		Fullname:  fullname,
+		NIP:       "",
+		Contact:   "",
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// GetByUsername mencari pengguna berdasarkan username.
+func (s *SQLStore) GetByUsername(username string) (*models.User, error) {
+	return s.getUser("username", username)
+}
+
+// GetUserByID mencari pengguna berdasarkan ID.
+func (s *SQLStore) GetUserByID(id string) (*models.User, error) {
+	return s.getUser("id", id)
+}
+
+// getUser mengambil satu pengguna (termasuk password) berdasarkan kolom unik.
+func (s *SQLStore) getUser(column, value string) (*models.User, error) {
+	user := &models.User{}
+	var fullname, nip, contact sql.NullString // Handle potential nulls
+	var expires sql.NullTime
+	err := s.db.QueryRow(
+		"SELECT id, username, password, role, fullname, nip, contact, created_at, membership_expires_at, blocked_reason, email, email_notifications FROM users WHERE "+column+" = ?",
+		value,
+	).Scan(&user.ID, &user.Username, &user.Password, &user.Role, &fullname, &nip, &contact, &user.CreatedAt, &expires, &user.BlockedReason, &user.Email, &user.EmailNotifications)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.Fullname = fullname.String
+	user.NIP = nip.String
+	user.Contact = contact.String
+	if expires.Valid {
+		user.MembershipExpiresAt = &expires.Time
+	}
+	return user, nil
+}
+
+// GetAllUsers mengambil semua data pengguna.
+func (s *SQLStore) GetAllUsers() ([]models.User, error) {
+	var users []models.User
+	err := s.EachUser(func(u *models.User) error {
+		users = append(users, *u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// eachBatchSize adalah jumlah baris per query pada EachUser, EachBook dan EachLoan.
+const eachBatchSize = 500
+
+// eachBatch memanggil fn untuk setiap baris yang dibaca per batch dengan keyset pagination.
+// fetch menerima baris terakhir batch sebelumnya (nil untuk batch pertama) dan harus menutup
+// rows sebelum kembali, sehingga tidak ada cursor yang terbuka selama fn berjalan (misalnya
+// menulis ke klien yang lambat).
+func eachBatch[T any](fetch func(after *T) ([]T, error), fn func(*T) error) error {
+	var after *T
+	for {
+		batch, err := fetch(after)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		if len(batch) < eachBatchSize {
+			return nil
+		}
+		after = &last
+	}
+}
+
+// EachUser membaca semua pengguna (tanpa password) satu per satu, urut ID.
+func (s *SQLStore) EachUser(fn func(*models.User) error) error {
+	return eachBatch(func(after *models.User) ([]models.User, error) {
+		query := "SELECT id, username, role, fullname, nip, contact, created_at, membership_expires_at, blocked_reason, email, email_notifications FROM users"
+		args := []interface{}{}
+		if after != nil {
+			query += " WHERE id > ?"
+			args = append(args, after.ID)
+		}
+		rows, err := s.db.Query(query+" ORDER BY id LIMIT ?", append(args, eachBatchSize)...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var users []models.User
+		for rows.Next() {
+			var user models.User
+			var fullname, nip, contact sql.NullString
+			var expires sql.NullTime
+			if err := rows.Scan(&user.ID, &user.Username, &user.Role, &fullname, &nip, &contact, &user.CreatedAt, &expires, &user.BlockedReason, &user.Email, &user.EmailNotifications); err != nil {
+				return nil, err
+			}
+			user.Fullname = fullname.String
+			user.NIP = nip.String
+			user.Contact = contact.String
+			if expires.Valid {
+				user.MembershipExpiresAt = &expires.Time
+			}
+			users = append(users, user)
+		}
+		return users, rows.Err()
+	}, fn)
+}
+
+// ==========================================
+// USER
+// ==========================================
+
+// UpdateUser memperbarui data pengguna (nama, role, NIP, kontak).
+func (s *SQLStore) UpdateUser(user *models.User) error {
+	_, err := s.db.Exec("UPDATE users SET fullname=?, role=?, nip=?, contact=? WHERE id=?",
+		user.Fullname, user.Role, user.NIP, user.Contact, user.ID)
+	return err
+}
+
+// SetMembershipExpiry mengatur masa berlaku keanggotaan (nil = tanpa batas).
+func (s *SQLStore) SetMembershipExpiry(userID string, expiresAt *time.Time) error {
+	var value interface{}
+	if expiresAt != nil {
+		value = *expiresAt
+	}
+	return s.updateUserRow("UPDATE users SET membership_expires_at = ? WHERE id = ?", value, userID)
+}
+
+// SetUserBlock memblokir pengguna dengan alasan tertentu; alasan kosong membuka blokir.
+func (s *SQLStore) SetUserBlock(userID, reason string) error {
+	return s.updateUserRow("UPDATE users SET blocked_reason = ? WHERE id = ?", reason, userID)
+}
+
+// SetEmailPreference mengatur alamat email dan opt-in notifikasi email pengguna.
+func (s *SQLStore) SetEmailPreference(userID, email string, enabled bool) error {
+	return s.updateUserRow("UPDATE users SET email = ?, email_notifications = ? WHERE id = ?", email, enabled, userID)
+}
+
+// updateUserRow menjalankan UPDATE satu pengguna dan mengembalikan ErrUserNotFound jika ID tidak ada.
+func (s *SQLStore) updateUserRow(query string, args ...interface{}) error {
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	// RowsAffected MySQL bernilai 0 jika nilainya tidak berubah, jadi cek keberadaan secara terpisah
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", args[len(args)-1]).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteUser menghapus data pengguna beserta data terkait (notifikasi, histori pinjaman).
+func (s *SQLStore) DeleteUser(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 1. Delete Notifications
+	if _, err := tx.Exec("DELETE FROM notifications WHERE user_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete notifications: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM notification_preferences WHERE user_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete notification preferences: %v", err)
+	}
+
+	// 2. Eksemplar yang masih dipinjam user ini tidak akan pernah kembali, tandai hilang.
+	// Eksemplar yang disisihkan untuk reservasinya dikembalikan ke rak.
+	var bookIDs []int
+	rows, err := tx.Query(`SELECT book_id FROM loans WHERE user_id = ? AND status IN (`+openLoanStatuses+`)
+		UNION SELECT book_id FROM holds WHERE user_id = ? AND status = ?`, id, id, HoldReady)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var bookID int
+		if err := rows.Scan(&bookID); err != nil {
+			rows.Close()
+			return err
+		}
+		bookIDs = append(bookIDs, bookID)
+	}
+	rows.Close()
+	if _, err := tx.Exec("UPDATE book_items SET status = ? WHERE id IN (SELECT item_id FROM loans WHERE user_id = ? AND status IN ("+openLoanStatuses+"))", ItemLost, id); err != nil {
+		return fmt.Errorf("failed to release book copies: %v", err)
+	}
+	if _, err := tx.Exec("UPDATE book_items SET status = ? WHERE status = ? AND id IN (SELECT item_id FROM holds WHERE user_id = ? AND status = ?)",
+		ItemAvailable, ItemReserved, id, HoldReady); err != nil {
+		return fmt.Errorf("failed to release reserved copies: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM holds WHERE user_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete holds: %v", err)
+	}
+	for _, bookID := range bookIDs {
+		if err := syncBookStock(tx, bookID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM notification_outbox WHERE user_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete outbox messages: %v", err)
+	}
+
+	// 3. Delete Loans (History) beserta riwayat perpanjangan dan buku besar dendanya
+	if _, err := tx.Exec("DELETE FROM fine_entries WHERE user_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete fine entries: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM loan_renewals WHERE loan_id IN (SELECT id FROM loans WHERE user_id = ?)", id); err != nil {
+		return fmt.Errorf("failed to delete loan renewals: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM loans WHERE user_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete loans: %v", err)
+	}
+
+	// 4. Delete User
+	if _, err := tx.Exec("DELETE FROM users WHERE id=?", id); err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// SearchUsers mencari pengguna berdasarkan username, nama lengkap, atau NIP.
+func (s *SQLStore) SearchUsers(query string) ([]models.User, error) {
+	q := "%" + query + "%"
+	// Also search by NIP or Contact? Let's check Name, Username, NIP.
+	rows, err := s.db.Query("SELECT id, username, role, fullname, nip, contact, created_at, membership_expires_at, blocked_reason, email, email_notifications FROM users WHERE username LIKE ? OR fullname LIKE ? OR nip LIKE ?", q, q, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		var fullname, nip, contact sql.NullString
+		var expires sql.NullTime
+		if err := rows.Scan(&user.ID, &user.Username, &user.Role, &fullname, &nip, &contact, &user.CreatedAt, &expires, &user.BlockedReason, &user.Email, &user.EmailNotifications); err != nil {
+			return nil, err
+		}
+		user.Fullname = fullname.String
+		user.NIP = nip.String
+		user.Contact = contact.String
+		if expires.Valid {
+			user.MembershipExpiresAt = &expires.Time
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// ... existing CreateUser, GetByUsername, GetAllUsers ...
+
+// ==========================================
+// BOOKS
+// ==========================================
+
+// SearchBooks mencari buku berdasarkan judul, penulis, kategori, ISBN, atau penerbit.
+func (s *SQLStore) SearchBooks(query string) ([]models.Book, error) {
+	q := "%" + query + "%"
+	rows, err := s.db.Query("SELECT id, title, author, category, isbn, publisher, stock, image_url, published_year, created_at FROM books WHERE title LIKE ? OR author LIKE ? OR category LIKE ? OR isbn LIKE ? OR publisher LIKE ? ORDER BY created_at DESC", q, q, q, q, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []models.Book
+	for rows.Next() {
+		var b models.Book
+		var imageURL sql.NullString
+		var pubYear sql.NullInt64
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Category, &b.ISBN, &b.Publisher, &b.Stock, &imageURL, &pubYear, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		b.ImageURL = imageURL.String
+		b.PublishedYear = int(pubYear.Int64)
+		books = append(books, b)
+	}
+	return books, nil
+}
+
+// CreateBook menambahkan buku baru ke database.
+// Book.Stock diperlakukan sebagai jumlah eksemplar awal yang dibuat otomatis.
+func (s *SQLStore) CreateBook(book *models.Book) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertBook(tx, book); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertBook menyimpan buku beserta Book.Stock eksemplar awal di dalam transaksi tx.
+func insertBook(tx *sql.Tx, book *models.Book) error {
+	res, err := tx.Exec("INSERT INTO books (title, author, category, isbn, publisher, stock, image_url, published_year, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		book.Title, book.Author, book.Category, book.ISBN, book.Publisher, 0, book.ImageURL, book.PublishedYear, time.Now())
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	book.ID = int(id)
+
+	for i := 0; i < book.Stock; i++ {
+		if err := insertBookItem(tx, &models.BookItem{BookID: book.ID}); err != nil {
+			return err
+		}
+	}
+	return syncBookStock(tx, book.ID)
+}
+
+// ImportBooks menyimpan kategori baru dan buku hasil impor dalam satu transaksi.
+// Pemeriksaan ISBN mengunci rentang index isbn (FOR UPDATE di MySQL; SQLite sudah memakai
+// BEGIN IMMEDIATE) agar dua impor bersamaan tidak lolos pemeriksaan yang sama.
+func (s *SQLStore) ImportBooks(books []models.Book, categories []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	isbnQuery := "SELECT COUNT(*) FROM books WHERE isbn = ?"
+	if s.driver == DriverMySQL {
+		isbnQuery += " FOR UPDATE"
+	}
+	for _, b := range books {
+		if b.ISBN == "" {
+			continue
+		}
+		var count int
+		if err := tx.QueryRow(isbnQuery, b.ISBN).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrISBNExists
+		}
+	}
+
+	for _, name := range categories {
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM categories WHERE name = ?", name).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := tx.Exec("INSERT INTO categories (name) VALUES (?)", name); err != nil {
+			return err
+		}
+	}
+	for i := range books {
+		if err := insertBook(tx, &books[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAllBooks mengambil semua daftar buku diurutkan dari yang terbaru.
+func (s *SQLStore) GetAllBooks() ([]models.Book, error) {
+	var books []models.Book
+	err := s.EachBook(func(b *models.Book) error {
+		books = append(books, *b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// EachBook membaca semua buku satu per satu, terbaru lebih dulu.
+func (s *SQLStore) EachBook(fn func(*models.Book) error) error {
+	return eachBatch(func(after *models.Book) ([]models.Book, error) {
+		query := "SELECT id, title, author, category, isbn, publisher, stock, image_url, published_year, created_at FROM books"
+		args := []interface{}{}
+		if after != nil {
+			query += " WHERE created_at < ? OR (created_at = ? AND id < ?)"
+			args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+		}
+		rows, err := s.db.Query(query+" ORDER BY created_at DESC, id DESC LIMIT ?", append(args, eachBatchSize)...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var books []models.Book
+		for rows.Next() {
+			var b models.Book
+			var imageURL sql.NullString
+			var pubYear sql.NullInt64
+			if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Category, &b.ISBN, &b.Publisher, &b.Stock, &imageURL, &pubYear, &b.CreatedAt); err != nil {
+				return nil, err
+			}
+			b.ImageURL = imageURL.String
+			b.PublishedYear = int(pubYear.Int64)
+			books = append(books, b)
+		}
+		return books, rows.Err()
+	}, fn)
+}
+
+// GetBookByID mengambil detail buku berdasarkan ID.
+func (s *SQLStore) GetBookByID(id int) (*models.Book, error) {
+	var b models.Book
+	var imageURL sql.NullString
+	var pubYear sql.NullInt64
+	err := s.db.QueryRow("SELECT id, title, author, category, isbn, publisher, stock, image_url, published_year, created_at FROM books WHERE id = ?", id).
+		Scan(&b.ID, &b.Title, &b.Author, &b.Category, &b.ISBN, &b.Publisher, &b.Stock, &imageURL, &pubYear, &b.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrBookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.ImageURL = imageURL.String
+	b.PublishedYear = int(pubYear.Int64)
+	return &b, nil
+}
+
+// UpdateBook memperbarui informasi buku.
+// Stok tidak ikut diubah karena diturunkan dari eksemplar (lihat BookItemStore).
+func (s *SQLStore) UpdateBook(book *models.Book) error {
+	_, err := s.db.Exec("UPDATE books SET title=?, author=?, category=?, isbn=?, publisher=?, image_url=?, published_year=? WHERE id=?",
+		book.Title, book.Author, book.Category, book.ISBN, book.Publisher, book.ImageURL, book.PublishedYear, book.ID)
+	return err
+}
+
+// DeleteBook menghapus buku beserta eksemplar dan reservasinya berdasarkan ID.
+// Buku yang masih memiliki histori pinjaman tidak dapat dihapus.
+func (s *SQLStore) DeleteBook(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM loans WHERE book_id = ?", id).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBookHasLoans
+	}
+
+	if _, err := tx.Exec("DELETE FROM holds WHERE book_id=?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM book_items WHERE book_id=?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM books WHERE id=?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ==========================================
+// LOANS
+// ==========================================
+
+// BorrowBook memproses peminjaman buku: memilih eksemplar tersedia, menandainya dipinjam,
+// dan membuat record peminjaman dalam satu transaksi.
+func (s *SQLStore) BorrowBook(userID string, bookID, duration int) (*models.Loan, error) {
+	return s.borrow(userID, bookID, 0, duration)
+}
+
+// BorrowItem memproses peminjaman eksemplar tertentu (misal hasil scan barcode).
+func (s *SQLStore) BorrowItem(userID string, itemID, duration int) (*models.Loan, error) {
+	var bookID int
+	err := s.db.QueryRow("SELECT book_id FROM book_items WHERE id = ?", itemID).Scan(&bookID)
+	if err == sql.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.borrow(userID, bookID, itemID, duration)
+}
+
+// borrow adalah inti BorrowBook/BorrowItem. itemID 0 berarti pilih eksemplar tersedia pertama.
+func (s *SQLStore) borrow(userID string, bookID, itemID, duration int) (*models.Loan, error) {
+	// Mulai transaksi database
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Pastikan buku ada
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM books WHERE id = ?", bookID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrBookNotFound
+	}
+
+	// Reservasi "ready" milik peminjam ini memakai eksemplar yang sudah disisihkan untuknya
+	var holdID int
+	var holdItem sql.NullInt64
+	err = tx.QueryRow("SELECT id, item_id FROM holds WHERE user_id = ? AND book_id = ? AND status = ? ORDER BY id LIMIT 1",
+		userID, bookID, HoldReady).Scan(&holdID, &holdItem)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	hasHold := err == nil
+
+	if hasHold && (itemID == 0 || int64(itemID) == holdItem.Int64) {
+		res, err := tx.Exec("UPDATE book_items SET status = ? WHERE id = ? AND status = ?", ItemBorrowed, holdItem.Int64, ItemReserved)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n != 1 {
+			return nil, ErrItemUnavailable
+		}
+		itemID = int(holdItem.Int64)
+	} else {
+		if !hasHold {
+			// Eksemplar yang kembali menjadi hak antrean reservasi lebih dulu
+			if err := tx.QueryRow("SELECT COUNT(*) FROM holds WHERE book_id = ? AND status = ?", bookID, HoldWaiting).Scan(&count); err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, ErrOutOfStock
+			}
+		}
+		if itemID, err = claimItem(tx, bookID, itemID); err != nil {
+			return nil, err
+		}
+		// Peminjam mengambil eksemplar lain, eksemplar yang disisihkan dikembalikan ke rak
+		if hasHold && holdItem.Valid {
+			if _, err := tx.Exec("UPDATE book_items SET status = ? WHERE id = ? AND status = ?", ItemAvailable, holdItem.Int64, ItemReserved); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if hasHold {
+		if _, err := tx.Exec("UPDATE holds SET status = ?, closed_at = ? WHERE id = ?", HoldFulfilled, time.Now(), holdID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Stok buku mengikuti jumlah eksemplar tersedia
+	if err := syncBookStock(tx, bookID); err != nil {
+		return nil, err
+	}
+
+	// Buat record peminjaman, jatuh tempo digeser jika jatuh pada hari tutup
+	loanDate := time.Now()
+	cal, err := loadCalendar(tx, loanDate)
+	if err != nil {
+		return nil, err
+	}
+	dueDate := cal.DueDate(loanDate, duration)
+	res, err := tx.Exec("INSERT INTO loans (user_id, book_id, item_id, loan_date, due_date, status) VALUES (?, ?, ?, ?, ?, ?)",
+		userID, bookID, itemID, loanDate, dueDate, LoanBorrowed)
+	if err != nil {
+		return nil, err
+	}
+
+	loanID, _ := res.LastInsertId()
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.Loan{
+		ID:       int(loanID),
+		UserID:   userID,
+		BookID:   bookID,
+		ItemID:   itemID,
+		LoanDate: loanDate,
+		DueDate:  dueDate,
+		Status:   LoanBorrowed,
+	}, nil
+}
+
+// claimItem menandai eksemplar sebagai dipinjam. itemID 0 berarti pilih eksemplar tersedia pertama.
+// Kondisi status = 'available' pada UPDATE menjaga agar eksemplar yang sama tidak dipinjam
+// dua kali oleh transaksi paralel.
+func claimItem(tx *sql.Tx, bookID, itemID int) (int, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		candidate := itemID
+		if candidate == 0 {
+			err := tx.QueryRow("SELECT id FROM book_items WHERE book_id = ? AND status = ? ORDER BY copy_no LIMIT 1", bookID, ItemAvailable).Scan(&candidate)
+			if err == sql.ErrNoRows {
+				return 0, ErrOutOfStock
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		res, err := tx.Exec("UPDATE book_items SET status = ? WHERE id = ? AND book_id = ? AND status = ?", ItemBorrowed, candidate, bookID, ItemAvailable)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			return candidate, nil
+		}
+		if itemID != 0 {
+			return 0, ErrItemUnavailable
+		}
+	}
+	return 0, ErrOutOfStock
+}
+
+// ReturnBook memproses pengembalian buku (hitung denda, update status, tambah stok).
+func (s *SQLStore) ReturnBook(loanID int) (*models.Loan, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Ambil data peminjaman
+	var l models.Loan
+	var dueDate time.Time
+	var itemID sql.NullInt64
+	err = tx.QueryRow("SELECT id, user_id, book_id, item_id, status, due_date FROM loans WHERE id = ?", loanID).
+		Scan(&l.ID, &l.UserID, &l.BookID, &itemID, &l.Status, &dueDate)
+	if err == sql.ErrNoRows {
+		return nil, ErrLoanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Status == LoanReturned {
+		return nil, ErrAlreadyReturned
+	}
+
+	// Hitung Denda (kebijakan role & kategori, jatuh ke settings jika tidak ada)
+	finePerDay, err := finePerDayForLoan(tx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hari tutup perpustakaan tidak dihitung sebagai hari terlambat
+	cal, err := loadCalendar(tx, dueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	returnDate := time.Now()
+	fine := CalculateFine(dueDate, returnDate, finePerDay, cal)
+
+	// Update data peminjaman
+	// Denda berjalan digantikan denda final. Status dicek ulang di UPDATE karena status di atas
+	// dibaca tanpa lock: dari dua pengembalian bersamaan hanya satu yang mengubah baris, sehingga
+	// denda tidak tertagih dua kali dan eksemplar tidak dilepas dua kali.
+	res, err := tx.Exec("UPDATE loans SET return_date=?, status=?, fine=?, accrued_fine=0 WHERE id=? AND status IN ("+openLoanStatuses+")",
+		returnDate, LoanReturned, fine, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrAlreadyReturned
+	}
+
+	// Catat tagihan denda di buku besar
+	if fine > 0 {
+		if err := insertFineCharge(tx, l.UserID, loanID, fine, returnDate); err != nil {
+			return nil, err
+		}
+	}
+
+	// Kembalikan eksemplar ke rak, stok buku ikut bertambah
+	if itemID.Valid {
+		l.ItemID = int(itemID.Int64)
+		_, err = tx.Exec("UPDATE book_items SET status = ? WHERE id = ? AND status = ?", ItemAvailable, l.ItemID, ItemBorrowed)
+		if err != nil {
+			return nil, err
+		}
+		if err := syncBookStock(tx, l.BookID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Isi struct hasil pengembalian
+	l.Status = LoanReturned
+	l.Fine = fine
+	l.ReturnDate = &returnDate
+	l.DueDate = dueDate // Ensure DueDate is set for the returned loan object
+	// Power User Method for Worker
+	return &l, nil
+}
+
+// GetAllBorrowedLoans mengambil semua peminjaman yang belum dikembalikan ('borrowed' atau 'late').
+func (s *SQLStore) GetAllBorrowedLoans() ([]models.Loan, error) {
+	// Role peminjam dan kategori buku ikut diambil untuk menentukan kebijakan peminjaman
+	query := `
+		SELECT l.id, l.user_id, l.book_id, l.loan_date, l.due_date, l.status, l.accrued_fine,
+		       u.username, u.role, b.title, COALESCE(b.category, '')
+		FROM loans l
+		JOIN users u ON l.user_id = u.id
+		JOIN books b ON l.book_id = b.id
+		WHERE l.status IN (` + openLoanStatuses + `)
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		var l models.Loan
+		var dueDate time.Time
+		var loanDate time.Time
+		var username, role, title, category string
+		if err := rows.Scan(&l.ID, &l.UserID, &l.BookID, &loanDate, &dueDate, &l.Status, &l.AccruedFine, &username, &role, &title, &category); err != nil {
+			return nil, err
+		}
+		l.DueDate = dueDate
+		l.LoanDate = loanDate
+		l.User = &models.User{ID: l.UserID, Username: username, Role: role}
+		l.Book = &models.Book{ID: l.BookID, Title: title, Category: category}
+		loans = append(loans, l)
+	}
+	return loans, nil
+}
+
+// loanListQuery memuat pinjaman beserta judul buku, data peminjam dan barcode eksemplar;
+// dibaca dengan scanLoanListRow.
+const loanListQuery = `
+		SELECT l.id, l.user_id, l.book_id, l.item_id, l.loan_date, l.due_date, l.return_date, l.status, l.fine, l.accrued_fine, l.renewal_count,
+		       b.title, COALESCE(b.category, ''), u.username, u.fullname, i.barcode
+		FROM loans l
+		JOIN books b ON l.book_id = b.id
+		JOIN users u ON l.user_id = u.id
+		LEFT JOIN book_items i ON l.item_id = i.id
+	`
+
+// scanLoanListRow membaca satu baris hasil loanListQuery.
+func scanLoanListRow(rows *sql.Rows) (*models.Loan, error) {
+	var l models.Loan
+	var returnDate sql.NullTime
+	var itemID sql.NullInt64
+	var barcode, fullname sql.NullString
+	var bookTitle, category, username string
+
+	err := rows.Scan(&l.ID, &l.UserID, &l.BookID, &itemID, &l.LoanDate, &l.DueDate, &returnDate, &l.Status, &l.Fine, &l.AccruedFine, &l.Renewals, &bookTitle, &category, &username, &fullname, &barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	if returnDate.Valid {
+		t := returnDate.Time
+		l.ReturnDate = &t
+	}
+	if itemID.Valid {
+		l.ItemID = int(itemID.Int64)
+		l.Item = &models.BookItem{ID: l.ItemID, BookID: l.BookID, Barcode: barcode.String}
+	}
+
+	l.Book = &models.Book{ID: l.BookID, Title: bookTitle, Category: category}
+	l.User = &models.User{ID: l.UserID, Username: username, Fullname: fullname.String}
+	return &l, nil
+}
+
+// GetAllLoans mengambil semua riwayat peminjaman (join dengan user dan buku).
+func (s *SQLStore) GetAllLoans() ([]models.Loan, error) {
+	rows, err := s.db.Query(loanListQuery + " ORDER BY l.loan_date DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		l, err := scanLoanListRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, *l)
+	}
+	return loans, nil
+}
+
+// GetLoansFiltered mengambil riwayat peminjaman berdasarkan rentang tanggal.
+func (s *SQLStore) GetLoansFiltered(startDate, endDate time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := s.EachLoan(startDate, endDate, func(l *models.Loan) error {
+		loans = append(loans, *l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+// EachLoan membaca pinjaman dengan tanggal pinjam di antara startDate dan endDate (inklusif)
+// satu per satu, terbaru lebih dulu.
+func (s *SQLStore) EachLoan(startDate, endDate time.Time, fn func(*models.Loan) error) error {
+	return eachBatch(func(after *models.Loan) ([]models.Loan, error) {
+		query := loanListQuery + " WHERE l.loan_date >= ? AND l.loan_date <= ?"
+		args := []interface{}{startDate, endDate}
+		if after != nil {
+			query += " AND (l.loan_date < ? OR (l.loan_date = ? AND l.id < ?))"
+			args = append(args, after.LoanDate, after.LoanDate, after.ID)
+		}
+		rows, err := s.db.Query(query+" ORDER BY l.loan_date DESC, l.id DESC LIMIT ?", append(args, eachBatchSize)...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var loans []models.Loan
+		for rows.Next() {
+			l, err := scanLoanListRow(rows)
+			if err != nil {
+				return nil, err
+			}
+			loans = append(loans, *l)
+		}
+		return loans, rows.Err()
+	}, fn)
+}
+
+// GetLoanByID mengambil detail satu peminjaman (join dengan user dan buku).
+func (s *SQLStore) GetLoanByID(id int) (*models.Loan, error) {
+	query := `
+		SELECT l.id, l.user_id, l.book_id, l.item_id, l.loan_date, l.due_date, l.return_date, l.status, l.fine, l.accrued_fine, l.renewal_count,
+		       b.title, COALESCE(b.category, ''), u.username, u.role, i.barcode
+		FROM loans l
+		JOIN books b ON l.book_id = b.id
+		JOIN users u ON l.user_id = u.id
+		LEFT JOIN book_items i ON l.item_id = i.id
+		WHERE l.id = ?
+	`
+	var l models.Loan
+	var returnDate sql.NullTime
+	var itemID sql.NullInt64
+	var barcode sql.NullString
+	var bookTitle, category, username, role string
+
+	err := s.db.QueryRow(query, id).Scan(&l.ID, &l.UserID, &l.BookID, &itemID, &l.LoanDate, &l.DueDate, &returnDate, &l.Status, &l.Fine, &l.AccruedFine, &l.Renewals, &bookTitle, &category, &username, &role, &barcode)
+	if err == sql.ErrNoRows {
+		return nil, ErrLoanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if returnDate.Valid {
+		t := returnDate.Time
+		l.ReturnDate = &t
+	}
+	if itemID.Valid {
+		l.ItemID = int(itemID.Int64)
+		l.Item = &models.BookItem{ID: l.ItemID, BookID: l.BookID, Barcode: barcode.String}
+	}
+	l.Book = &models.Book{ID: l.BookID, Title: bookTitle, Category: category}
+	l.User = &models.User{ID: l.UserID, Username: username, Role: role}
+	return &l, nil
+}
+
+// GetLoansByUserID mengambil riwayat peminjaman milik user tertentu.
+func (s *SQLStore) GetLoansByUserID(userID string) ([]models.Loan, error) {
+	query := `
+		SELECT l.id, l.user_id, l.book_id, l.item_id, l.loan_date, l.due_date, l.return_date, l.status, l.fine, l.accrued_fine, l.renewal_count,
+		       b.title, i.barcode
+		FROM loans l
+		JOIN books b ON l.book_id = b.id
+		LEFT JOIN book_items i ON l.item_id = i.id
+		WHERE l.user_id = ?
+		ORDER BY l.loan_date DESC
+	`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		log.Println("Error querying loans:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		var l models.Loan
+		var returnDate sql.NullTime
+		var itemID sql.NullInt64
+		var barcode sql.NullString
+		var bookTitle string
+
+		err := rows.Scan(&l.ID, &l.UserID, &l.BookID, &itemID, &l.LoanDate, &l.DueDate, &returnDate, &l.Status, &l.Fine, &l.AccruedFine, &l.Renewals, &bookTitle, &barcode)
+		if err != nil {
+			log.Println("Error scanning loan:", err)
+			return nil, err
+		}
+
+		if returnDate.Valid {
+			t := returnDate.Time
+			l.ReturnDate = &t
+		}
+		if itemID.Valid {
+			l.ItemID = int(itemID.Int64)
+			l.Item = &models.BookItem{ID: l.ItemID, BookID: l.BookID, Barcode: barcode.String}
+		}
+
+		l.Book = &models.Book{ID: l.BookID, Title: bookTitle}
+		loans = append(loans, l)
+	}
+	return loans, nil
+}
+
+// GetOverdueLoans mengambil daftar peminjaman yang terlambat dan belum dikembalikan.
+func (s *SQLStore) GetOverdueLoans(userID string) ([]models.Loan, error) {
+	query := `
+		SELECT l.id, l.book_id, l.due_date, b.title 
+		FROM loans l
+		JOIN books b ON l.book_id = b.id
+		WHERE l.user_id = ? AND l.status IN (` + openLoanStatuses + `) AND l.due_date < ?
+	`
+	rows, err := s.db.Query(query, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		var l models.Loan
+		var bookTitle string
+		if err := rows.Scan(&l.ID, &l.BookID, &l.DueDate, &bookTitle); err != nil {
+			return nil, err
+		}
+		l.Book = &models.Book{ID: l.BookID, Title: bookTitle}
+		loans = append(loans, l)
+	}
+	return loans, nil
+}
+
+// notificationColumns adalah kolom tabel notifications sesuai urutan scanNotifications.
+const notificationColumns = "id, user_id, category, severity, message, is_read, created_at"
+
+// scanNotifications membaca seluruh baris notificationColumns.
+func scanNotifications(rows *sql.Rows) ([]models.Notification, error) {
+	defer rows.Close()
+
+	var notifs []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Category, &n.Severity, &n.Message, &n.IsRead, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifs = append(notifs, n)
+	}
+	return notifs, rows.Err()
+}
+
+// GetNotifications mengambil daftar notifikasi untuk user tertentu, difilter kategori dan status baca.
+func (s *SQLStore) GetNotifications(userID string, filter models.NotificationFilter) ([]models.Notification, error) {
+	query := "SELECT " + notificationColumns + " FROM notifications WHERE user_id = ?"
+	args := []interface{}{userID}
+	if filter.Category != "" {
+		query += " AND category = ?"
+		args = append(args, filter.Category)
+	}
+	if filter.IsRead != nil {
+		query += " AND is_read = ?"
+		args = append(args, *filter.IsRead)
+	}
+	rows, err := s.db.Query(query+" ORDER BY created_at DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
+
+// GetNotificationsSince mengambil notifikasi dengan ID lebih besar dari afterID, terlama lebih dulu.
+// Dipakai untuk melanjutkan stream notifikasi (Last-Event-ID).
+func (s *SQLStore) GetNotificationsSince(userID string, afterID int) ([]models.Notification, error) {
+	rows, err := s.db.Query("SELECT "+notificationColumns+" FROM notifications WHERE user_id = ? AND id > ? ORDER BY id", userID, afterID)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
+
+// MarkNotificationRead menandai notifikasi sebagai sudah dibaca.
+func (s *SQLStore) MarkNotificationRead(id int) error {
+	_, err := s.db.Exec("UPDATE notifications SET is_read = TRUE WHERE id = ?", id)
+	return err
+}
+
+// CreateNotification membuat notifikasi baru.
+// Mencegah duplikasi pesan yang sama untuk user yang sama.
+func (s *SQLStore) CreateNotification(userID, category, severity, message string) error {
+	prefs, err := s.GetNotificationPreferences(userID)
+	if err != nil {
+		return err
+	}
+	enabled := FindNotificationPreference(prefs, category).Enabled
+
+	// Deduplikasi: Cek jika pesan yang sama sudah ada
+	// Ini mencegah spamming notifikasi yang sama (misal dari worker).
+	// Notifikasi kategori yang dimatikan hanya ada di outbox, jadi dicek di sana.
+	query := "SELECT COUNT(*) FROM notifications WHERE user_id = ? AND message = ?"
+	if !enabled {
+		query = "SELECT COUNT(*) FROM notification_outbox WHERE user_id = ? AND message = ?"
+	}
+	var count int
+	if err := s.db.QueryRow(query, userID, message).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil // Duplicate found (even if read), skip
+	}
+
+	n := models.Notification{UserID: userID, Category: category, Severity: severity, Message: message, CreatedAt: time.Now()}
+	// Kategori yang dimatikan penerima tidak disimpan di aplikasi, tetapi tetap diteruskan ke
+	// publisher dengan ID 0 agar kanal luar (email) mengikuti preferensinya sendiri
+	if enabled {
+		res, err := s.db.Exec("INSERT INTO notifications (user_id, category, severity, message, is_read, created_at) VALUES (?, ?, ?, ?, FALSE, ?)",
+			n.UserID, n.Category, n.Severity, n.Message, n.CreatedAt)
+		if err != nil {
+			return err
+		}
+		id, _ := res.LastInsertId()
+		n.ID = int(id)
+	}
+	if s.publisher != nil {
+		s.publisher.PublishNotification(n)
+	}
+	return nil
+}
+
+// SetNotificationPublisher memasang penerima notifikasi baru. Dipanggil sekali saat startup.
+func (s *SQLStore) SetNotificationPublisher(p NotificationPublisher) {
+	s.publisher = p
+}
+
+// DeleteNotification menghapus notifikasi.
+func (s *SQLStore) DeleteNotification(id int) error {
+	_, err := s.db.Exec("DELETE FROM notifications WHERE id = ?", id)
+	return err
+}
+
+// settingsColumns adalah kolom tabel settings sesuai urutan settingsValues dan scanSettings.
+const settingsColumns = "max_loan_books, loan_duration, fine_per_day, hold_pickup_days, max_renewals, closed_weekdays, max_overdue_loans, max_outstanding_fine"
+
+// settingsValues mengembalikan nilai pengaturan sesuai urutan settingsColumns.
+func settingsValues(set *models.Settings) []interface{} {
+	return []interface{}{set.MaxLoanBooks, set.LoanDuration, set.FinePerDay, set.HoldPickupDays, set.MaxRenewals,
+		set.ClosedWeekdays, set.MaxOverdueLoans, set.MaxOutstandingFine}
+}
+
+// scanSettings membaca satu baris settingsColumns ke set.
+func scanSettings(row *sql.Row, set *models.Settings) error {
+	return row.Scan(&set.MaxLoanBooks, &set.LoanDuration, &set.FinePerDay, &set.HoldPickupDays, &set.MaxRenewals,
+		&set.ClosedWeekdays, &set.MaxOverdueLoans, &set.MaxOutstandingFine)
+}
+
+// GetSettings mengambil pengaturan aplikasi.
+func (s *SQLStore) GetSettings() (*models.Settings, error) {
+	var set models.Settings
+	err := scanSettings(s.db.QueryRow("SELECT "+settingsColumns+" FROM settings WHERE id = 1"), &set)
+	if err == sql.ErrNoRows {
+		return DefaultSettings(), nil // Default
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// UpdateSettings menyimpan pengaturan baru dan mencatat setiap field yang berubah ke
+// settings_audit dalam satu transaksi. Mengembalikan daftar perubahan yang tercatat.
+func (s *SQLStore) UpdateSettings(settings *models.Settings, userID, username string) ([]models.SettingsChange, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current := DefaultSettings()
+	err = scanSettings(tx.QueryRow("SELECT "+settingsColumns+" FROM settings WHERE id = 1"), current)
+	if err == sql.ErrNoRows {
+		_, err = tx.Exec("INSERT INTO settings (id, "+settingsColumns+") VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?)", settingsValues(current)...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE settings SET max_loan_books=?, loan_duration=?, fine_per_day=?, hold_pickup_days=?, max_renewals=?,
+		closed_weekdays=?, max_overdue_loans=?, max_outstanding_fine=? WHERE id = 1`, settingsValues(settings)...)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffSettings(current, settings, userID, username, time.Now())
+	for i := range changes {
+		c := &changes[i]
+		res, err := tx.Exec("INSERT INTO settings_audit (changed_by, changed_by_name, field, old_value, new_value, changed_at) VALUES (?, ?, ?, ?, ?, ?)",
+			c.ChangedBy, c.ChangedByName, c.Field, c.OldValue, c.NewValue, c.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		id, _ := res.LastInsertId()
+		c.ID = int(id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetSettingsHistory mengambil riwayat perubahan pengaturan, terbaru lebih dulu.
+// limit <= 0 berarti tanpa batas.
+func (s *SQLStore) GetSettingsHistory(limit int) ([]models.SettingsChange, error) {
+	query := "SELECT id, changed_by, changed_by_name, field, old_value, new_value, changed_at FROM settings_audit ORDER BY id DESC"
+	var args []any
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.SettingsChange
+	for rows.Next() {
+		var c models.SettingsChange
+		if err := rows.Scan(&c.ID, &c.ChangedBy, &c.ChangedByName, &c.Field, &c.OldValue, &c.NewValue, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// Category Methods
+
+// CreateCategory menambah kategori buku baru.
+func (s *SQLStore) CreateCategory(name string) error {
+	_, err := s.db.Exec("INSERT INTO categories (name) VALUES (?)", name)
+	return err
+}
+
+// GetAllCategories mengambil semua kategori buku.
+func (s *SQLStore) GetAllCategories() ([]models.Category, error) {
+	rows, err := s.db.Query("SELECT id, name FROM categories ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
+
+// DeleteCategory menghapus kategori.
+func (s *SQLStore) DeleteCategory(id int) error {
+	_, err := s.db.Exec("DELETE FROM categories WHERE id=?", id)
+	return err
+}
+
+// ==========================================
+// DASHBOARD STATS
+// ==========================================
+
+// CountUsers menghitung total pengguna terdaftar.
+func (s *SQLStore) CountUsers() (int, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
+	return count, err
+}
+
+// CountBooks menghitung total buku di database.
+func (s *SQLStore) CountBooks() (int, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM books").Scan(&count)
+	return count, err
+}
+
+// SetLoanAccrual memperbarui status dan denda berjalan pinjaman yang belum dikembalikan.
+// Kondisi status pada UPDATE mencegah pengembalian yang terjadi bersamaan tertimpa.
+func (s *SQLStore) SetLoanAccrual(loanID int, status string, accruedFine int) error {
+	_, err := s.db.Exec("UPDATE loans SET status = ?, accrued_fine = ? WHERE id = ? AND status IN ("+openLoanStatuses+")",
+		status, accruedFine, loanID)
+	return err
+}
+
+// CountTotalActiveLoans menghitung total peminjaman yang masih aktif.
+func (s *SQLStore) CountTotalActiveLoans() (int, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM loans WHERE status IN (" + openLoanStatuses + ")").Scan(&count)
+	return count, err
+}
+
+// CountActiveLoansByUser menghitung peminjaman aktif milik user tertentu.
+func (s *SQLStore) CountActiveLoansByUser(userID string) (int, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM loans WHERE user_id = ? AND status IN ("+openLoanStatuses+")", userID).Scan(&count)
+	return count, err
+}
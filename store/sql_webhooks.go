@@ -0,0 +1,260 @@
+package store
+
+import (
+	"database/sql"
+	"latihan_cloud8/models"
+	"strings"
+	"time"
+)
+
+const deliveryColumns = `id, webhook_id, event_id, event, payload, status, attempts, response_code,
+	COALESCE(last_error, ''), next_attempt_at, created_at, delivered_at`
+
+// scanWebhooks membaca baris hasil query kolom id, url, secret, events, description, active, created_at.
+func scanWebhooks(rows *sql.Rows) ([]models.Webhook, error) {
+	defer rows.Close()
+
+	var hooks []models.Webhook
+	for rows.Next() {
+		var h models.Webhook
+		var events string
+		if err := rows.Scan(&h.ID, &h.URL, &h.Secret, &events, &h.Description, &h.Active, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		h.Events = splitEvents(events)
+		hooks = append(hooks, h)
+	}
+	return hooks, rows.Err()
+}
+
+// splitEvents mengubah kolom events (dipisah koma) menjadi slice.
+func splitEvents(events string) []string {
+	list := []string{}
+	for _, e := range strings.Split(events, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// scanDeliveries membaca baris hasil query dengan kolom deliveryColumns.
+func scanDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.ResponseCode,
+			&d.LastError, &d.NextAttemptAt, &d.CreatedAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// GetWebhooks mengambil semua webhook, termasuk secret-nya.
+func (s *SQLStore) GetWebhooks() ([]models.Webhook, error) {
+	rows, err := s.db.Query("SELECT id, url, secret, events, description, active, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhooks(rows)
+}
+
+// GetWebhook mengambil satu webhook berdasarkan ID.
+func (s *SQLStore) GetWebhook(id int) (*models.Webhook, error) {
+	rows, err := s.db.Query("SELECT id, url, secret, events, description, active, created_at FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := scanWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(hooks) == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return &hooks[0], nil
+}
+
+// CreateWebhook menambahkan langganan webhook baru.
+func (s *SQLStore) CreateWebhook(hook *models.Webhook) error {
+	hook.CreatedAt = time.Now()
+	res, err := s.db.Exec("INSERT INTO webhooks (url, secret, events, description, active, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		hook.URL, hook.Secret, strings.Join(hook.Events, ","), hook.Description, hook.Active, hook.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, _ := res.LastInsertId()
+	hook.ID = int(id)
+	return nil
+}
+
+// UpdateWebhook memperbarui URL, event, deskripsi dan status aktif; Secret kosong berarti tidak diganti.
+func (s *SQLStore) UpdateWebhook(hook *models.Webhook) error {
+	if _, err := s.GetWebhook(hook.ID); err != nil {
+		return err
+	}
+	query := "UPDATE webhooks SET url = ?, events = ?, description = ?, active = ? WHERE id = ?"
+	args := []interface{}{hook.URL, strings.Join(hook.Events, ","), hook.Description, hook.Active, hook.ID}
+	if hook.Secret != "" {
+		query = "UPDATE webhooks SET url = ?, events = ?, description = ?, active = ?, secret = ? WHERE id = ?"
+		args = []interface{}{hook.URL, strings.Join(hook.Events, ","), hook.Description, hook.Active, hook.Secret, hook.ID}
+	}
+	_, err := s.db.Exec(query, args...)
+	return err
+}
+
+// DeleteWebhook menghapus webhook beserta log pengirimannya.
+func (s *SQLStore) DeleteWebhook(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+		return err
+	}
+	res, err := tx.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrWebhookNotFound
+	}
+	return tx.Commit()
+}
+
+// EnqueueWebhookEvent membuat satu pengiriman untuk setiap webhook aktif yang berlangganan event tersebut.
+func (s *SQLStore) EnqueueWebhookEvent(eventID, event, payload string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id, url, secret, events, description, active, created_at FROM webhooks WHERE active = ?", true)
+	if err != nil {
+		return 0, err
+	}
+	hooks, err := scanWebhooks(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	count := 0
+	for _, h := range hooks {
+		if !WebhookSubscribes(h.Events, event) {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, ?, 0, ?, ?)`, h.ID, eventID, event, payload, DeliveryPending, now, now); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, tx.Commit()
+}
+
+// GetDueWebhookDeliveries mengambil pengiriman pending yang jadwalnya sudah tiba, terlama lebih dulu.
+func (s *SQLStore) GetDueWebhookDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := s.db.Query("SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE status IN (?, ?) AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?",
+		DeliveryPending, DeliverySending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+// GetWebhookDeliveries mengambil log pengiriman terbaru milik satu webhook.
+func (s *SQLStore) GetWebhookDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := s.db.Query("SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE webhook_id = ? ORDER BY id DESC LIMIT ?", webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+// ClaimWebhookDelivery menandai pengiriman sending secara atomik sebelum dikirim, sehingga
+// dua worker tidak mengirim event yang sama. next_attempt_at diisi batas klaim agar pengiriman
+// diambil lagi jika tidak pernah selesai.
+func (s *SQLStore) ClaimWebhookDelivery(id int, now time.Time) (bool, error) {
+	res, err := s.db.Exec(`UPDATE webhook_deliveries SET status = ?, next_attempt_at = ?
+		WHERE id = ? AND (status = ? OR status = ?) AND next_attempt_at <= ?`,
+		DeliverySending, now.Add(DeliveryClaimTimeout), id, DeliveryPending, DeliverySending, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// MarkWebhookDelivered menandai pengiriman berhasil (respon 2xx).
+func (s *SQLStore) MarkWebhookDelivered(id, responseCode int, deliveredAt time.Time) error {
+	return s.updateDeliveryRow("UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, response_code = ?, last_error = NULL, delivered_at = ? WHERE id = ?",
+		DeliveryDelivered, responseCode, deliveredAt, id)
+}
+
+// MarkWebhookFailed mencatat percobaan yang gagal; retryAt nil berarti tidak dicoba lagi.
+func (s *SQLStore) MarkWebhookFailed(id, responseCode int, lastError string, retryAt *time.Time) error {
+	if retryAt == nil {
+		return s.updateDeliveryRow("UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, response_code = ?, last_error = ? WHERE id = ?",
+			DeliveryFailed, responseCode, lastError, id)
+	}
+	return s.updateDeliveryRow("UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, response_code = ?, last_error = ?, next_attempt_at = ? WHERE id = ?",
+		DeliveryPending, responseCode, lastError, *retryAt, id)
+}
+
+// RedeliverWebhook membuat pengiriman baru dengan payload yang sama dari pengiriman lama.
+// Event ID tidak berubah sehingga penerima dapat mengenali duplikat.
+func (s *SQLStore) RedeliverWebhook(deliveryID int) (*models.WebhookDelivery, error) {
+	rows, err := s.db.Query("SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = ?", deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	old, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(old) == 0 {
+		return nil, ErrDeliveryNotFound
+	}
+
+	d := models.WebhookDelivery{
+		WebhookID: old[0].WebhookID,
+		EventID:   old[0].EventID,
+		Event:     old[0].Event,
+		Payload:   old[0].Payload,
+		Status:    DeliveryPending,
+		CreatedAt: time.Now(),
+	}
+	d.NextAttemptAt = d.CreatedAt
+	res, err := s.db.Exec(`INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?)`, d.WebhookID, d.EventID, d.Event, d.Payload, d.Status, d.NextAttemptAt, d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := res.LastInsertId()
+	d.ID = int(id)
+	return &d, nil
+}
+
+// updateDeliveryRow menjalankan UPDATE satu pengiriman dan mengembalikan ErrDeliveryNotFound jika ID tidak ada.
+func (s *SQLStore) updateDeliveryRow(query string, args ...interface{}) error {
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
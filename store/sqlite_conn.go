@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteConnector membuka koneksi SQLite yang menyimpan waktu dalam UTC dan mengembalikannya
+// dalam zona lokal. SQLite tidak memiliki tipe waktu sehingga DATETIME dibandingkan sebagai
+// teks; waktu dengan offset zona berbeda ("+07:00" dan "+00:00") akan salah urut jika tidak
+// diseragamkan lebih dulu.
+type sqliteConnector struct {
+	dsn string
+}
+
+func (c sqliteConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Driver().Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteConn{conn}, nil
+}
+
+func (c sqliteConnector) Driver() driver.Driver {
+	return &sqlite.Driver{}
+}
+
+// sqliteConn membungkus koneksi modernc.org/sqlite. Semua antarmuka opsional yang dipakai
+// database/sql diteruskan agar perilaku koneksi (konteks, BEGIN IMMEDIATE) tidak berubah.
+type sqliteConn struct {
+	driver.Conn
+}
+
+// CheckNamedValue mengubah parameter waktu ke UTC sebelum ditulis oleh driver.
+func (c *sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch v := nv.Value.(type) {
+	case time.Time:
+		nv.Value = v.UTC()
+	case *time.Time:
+		if v != nil {
+			nv.Value = v.UTC()
+		}
+	}
+	return driver.ErrSkip
+}
+
+func (c *sqliteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteRows{rows}, nil
+}
+
+func (c *sqliteConn) Ping(ctx context.Context) error {
+	return c.Conn.(driver.Pinger).Ping(ctx)
+}
+
+func (c *sqliteConn) ResetSession(ctx context.Context) error {
+	return c.Conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+func (c *sqliteConn) IsValid() bool {
+	return c.Conn.(driver.Validator).IsValid()
+}
+
+// sqliteRows mengembalikan kolom DATETIME dalam zona lokal seperti sebelum penyimpanan UTC,
+// agar tanggal yang ditampilkan (jatuh tempo, laporan harian) tidak bergeser hari.
+type sqliteRows struct {
+	driver.Rows
+}
+
+func (r *sqliteRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, v := range dest {
+		if t, ok := v.(time.Time); ok {
+			dest[i] = t.In(time.Local)
+		}
+	}
+	return nil
+}
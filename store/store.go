@@ -0,0 +1,623 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"latihan_cloud8/models"
+	"sort"
+	"time"
+)
+
+var (
+	ErrUserExists       = errors.New("user already exists")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrBookNotFound     = errors.New("book not found")
+	ErrOutOfStock       = errors.New("book out of stock")
+	ErrLoanNotFound     = errors.New("loan not found")
+	ErrAlreadyReturned  = errors.New("book already returned")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrISBNExists       = errors.New("isbn already exists in catalog")
+	ErrBookHasLoans     = errors.New("book still referenced by loans")
+	ErrInvalidReference = errors.New("referenced record does not exist")
+	ErrItemNotFound     = errors.New("book copy not found")
+	ErrItemUnavailable  = errors.New("book copy not available")
+	ErrItemBorrowed     = errors.New("book copy is currently borrowed")
+	ErrItemHasLoans     = errors.New("book copy still referenced by loans")
+	ErrBarcodeExists    = errors.New("barcode already exists")
+	ErrItemReserved     = errors.New("book copy is reserved for a hold")
+	ErrHoldNotFound     = errors.New("hold not found")
+	ErrHoldExists       = errors.New("hold already placed for this book")
+	ErrHoldClosed       = errors.New("hold is no longer active")
+	ErrBookAvailable    = errors.New("book is available, borrow it directly")
+	ErrAlreadyBorrowing = errors.New("book already borrowed by this user")
+	ErrLoanOverdue      = errors.New("loan is overdue")
+	ErrRenewalLimit     = errors.New("renewal limit reached")
+	ErrHoldsPending     = errors.New("title has pending holds")
+	ErrPolicyNotFound   = errors.New("loan policy not found")
+	ErrPolicyExists     = errors.New("loan policy for this role and category already exists")
+	ErrHolidayNotFound  = errors.New("holiday not found")
+	ErrHolidayExists    = errors.New("holiday already exists for this date")
+	ErrInvalidAmount    = errors.New("amount must be positive")
+	ErrExceedsBalance   = errors.New("amount exceeds outstanding fine balance")
+	ErrReceiptExists    = errors.New("receipt number already used")
+	ErrFineNotFound     = errors.New("fine entry not found")
+	ErrOutboxNotFound   = errors.New("outbox message not found")
+	ErrOutboxSent       = errors.New("outbox message already sent")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrJobClaimed       = errors.New("job run already claimed for this schedule slot")
+	ErrJobRunNotFound   = errors.New("job run not found")
+)
+
+// UserStore mengelola data pengguna.
+type UserStore interface {
+	CreateUser(username, hashedPassword, role, fullname string) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	GetAllUsers() ([]models.User, error)
+	UpdateUser(user *models.User) error
+	DeleteUser(id string) error
+	SearchUsers(query string) ([]models.User, error)
+	CountUsers() (int, error)
+
+	// SetMembershipExpiry mengatur masa berlaku keanggotaan (nil = tanpa batas).
+	SetMembershipExpiry(userID string, expiresAt *time.Time) error
+	// SetUserBlock memblokir pengguna dengan alasan tertentu; alasan kosong membuka blokir.
+	SetUserBlock(userID, reason string) error
+	GetUserByID(id string) (*models.User, error)
+	// SetEmailPreference mengatur alamat email dan opt-in notifikasi email pengguna.
+	SetEmailPreference(userID, email string, enabled bool) error
+}
+
+// BookStore mengelola data buku dan kategori.
+type BookStore interface {
+	SearchBooks(query string) ([]models.Book, error)
+	CreateBook(book *models.Book) error
+	GetAllBooks() ([]models.Book, error)
+	GetBookByID(id int) (*models.Book, error)
+	UpdateBook(book *models.Book) error
+	DeleteBook(id int) error
+	CountBooks() (int, error)
+	// ImportBooks menyimpan hasil impor katalog dalam satu transaksi: kategori baru dibuat
+	// lebih dulu, lalu setiap buku beserta eksemplar awalnya seperti CreateBook (ID diisi ke
+	// books). ISBN diperiksa ulang di dalam transaksi; ErrISBNExists jika buku dengan ISBN
+	// yang sama sudah ada (mis. ditambahkan setelah berkas divalidasi). Jika satu gagal, tidak
+	// ada yang tersimpan.
+	ImportBooks(books []models.Book, categories []string) error
+
+	CreateCategory(name string) error
+	GetAllCategories() ([]models.Category, error)
+	DeleteCategory(id int) error
+}
+
+// BookItemStore mengelola eksemplar fisik buku.
+// Stok buku (Book.Stock) selalu diturunkan dari jumlah eksemplar berstatus "available".
+type BookItemStore interface {
+	CreateBookItem(item *models.BookItem) error
+	GetBookItems(bookID int) ([]models.BookItem, error)
+	GetBookItemByID(id int) (*models.BookItem, error)
+	GetBookItemByBarcode(barcode string) (*models.BookItem, error)
+	UpdateBookItem(item *models.BookItem) error
+	DeleteBookItem(id int) error
+}
+
+// LoanStore mengelola transaksi peminjaman buku.
+type LoanStore interface {
+	BorrowBook(userID string, bookID, duration int) (*models.Loan, error)
+	BorrowItem(userID string, itemID, duration int) (*models.Loan, error)
+	ReturnBook(loanID int) (*models.Loan, error)
+	RenewLoan(loanID int, renewedBy string, days, maxRenewals int) (*models.Loan, error)
+	GetLoanByID(id int) (*models.Loan, error)
+	GetLoanRenewals(loanID int) ([]models.LoanRenewal, error)
+	GetAllBorrowedLoans() ([]models.Loan, error)
+	GetAllLoans() ([]models.Loan, error)
+	GetLoansFiltered(startDate, endDate time.Time) ([]models.Loan, error)
+	GetLoansByUserID(userID string) ([]models.Loan, error)
+	GetOverdueLoans(userID string) ([]models.Loan, error)
+	// SetLoanAccrual memperbarui status ("borrowed"/"late") dan denda berjalan pinjaman yang
+	// belum dikembalikan. Pinjaman yang sudah dikembalikan tidak diubah.
+	SetLoanAccrual(loanID int, status string, accruedFine int) error
+	CountTotalActiveLoans() (int, error)
+	CountActiveLoansByUser(userID string) (int, error)
+}
+
+// HoldStore mengelola reservasi (antrean FIFO per judul) untuk buku yang stoknya habis.
+// Eksemplar yang kembali disisihkan (status "reserved") untuk antrean terdepan lewat AssignHolds.
+type HoldStore interface {
+	PlaceHold(userID string, bookID int) (*models.Hold, error)
+	GetHoldByID(id int) (*models.Hold, error)
+	GetHoldsByUser(userID string) ([]models.Hold, error)
+	GetActiveHolds(bookID int) ([]models.Hold, error)
+	CancelHold(id int) (*models.Hold, error)
+	AssignHolds(bookID int) ([]models.Hold, error)
+	ExpireHolds(now time.Time) ([]models.Hold, error)
+}
+
+// NotificationStore mengelola notifikasi pengguna beserta preferensi kategorinya.
+// Notifikasi yang baru tersimpan diteruskan ke publisher yang terpasang (lihat SetNotificationPublisher).
+type NotificationStore interface {
+	GetNotifications(userID string, filter models.NotificationFilter) ([]models.Notification, error)
+	// GetNotificationsSince mengambil notifikasi dengan ID lebih besar dari afterID, terlama lebih dulu.
+	GetNotificationsSince(userID string, afterID int) ([]models.Notification, error)
+	MarkNotificationRead(id int) error
+	// CreateNotification menyimpan notifikasi kecuali kategori tersebut dimatikan oleh penerimanya.
+	// Publisher tetap dipanggil untuk kategori yang dimatikan dengan ID 0 (tidak tersimpan),
+	// sehingga kanal luar seperti email dapat mengikuti preferensinya sendiri.
+	CreateNotification(userID, category, severity, message string) error
+	DeleteNotification(id int) error
+	SetNotificationPublisher(p NotificationPublisher)
+
+	// GetNotificationPreferences mengambil preferensi untuk semua kategori (lihat NotificationCategories).
+	GetNotificationPreferences(userID string) ([]models.NotificationPreference, error)
+	// SetNotificationPreferences menyimpan preferensi; kategori yang tidak dikirim tidak diubah.
+	SetNotificationPreferences(userID string, prefs []models.NotificationPreference) error
+}
+
+// NotificationPublisher menerima setiap notifikasi yang baru tersimpan, misalnya untuk
+// dikirim langsung ke browser lewat WebSocket. Dipanggil setelah data tersimpan. Notifikasi
+// dengan ID 0 tidak tersimpan di aplikasi karena kategorinya dimatikan penerima; publisher
+// untuk aplikasi harus melewatinya.
+type NotificationPublisher interface {
+	PublishNotification(n models.Notification)
+}
+
+// OutboxStore mengelola antrean pesan notifikasi untuk kanal luar seperti email (lihat package notify).
+// Pesan pending dikirim oleh worker outbox; yang gagal dijadwalkan ulang sampai batas percobaan.
+type OutboxStore interface {
+	EnqueueOutbox(msg *models.OutboxMessage) error
+	// GetDueOutbox mengambil pesan pending yang jadwal kirimnya sudah tiba, terlama lebih dulu,
+	// termasuk pesan sending yang klaimnya sudah kedaluwarsa.
+	GetDueOutbox(now time.Time, limit int) ([]models.OutboxMessage, error)
+	// ClaimOutbox menandai pesan sending sebelum dikirim. false berarti pesan sudah diklaim
+	// worker lain (atau sudah tidak pending) sehingga tidak boleh dikirim.
+	ClaimOutbox(id int, now time.Time) (bool, error)
+	// GetOutbox mengambil pesan terbaru, difilter status jika tidak kosong.
+	GetOutbox(status string, limit int) ([]models.OutboxMessage, error)
+	MarkOutboxSent(id int, sentAt time.Time) error
+	// MarkOutboxFailed mencatat percobaan yang gagal; retryAt nil berarti pesan tidak dicoba lagi.
+	MarkOutboxFailed(id int, lastError string, retryAt *time.Time) error
+	// RetryOutbox menjadwalkan ulang pesan yang belum terkirim agar segera dikirim.
+	RetryOutbox(id int) error
+}
+
+// WebhookStore mengelola langganan webhook dan log pengirimannya (lihat package webhook).
+// Log pengiriman sekaligus menjadi antrean: pengiriman pending dikirim oleh worker webhook.
+type WebhookStore interface {
+	GetWebhooks() ([]models.Webhook, error)
+	GetWebhook(id int) (*models.Webhook, error)
+	CreateWebhook(hook *models.Webhook) error
+	// UpdateWebhook memperbarui URL, event, deskripsi dan status aktif; Secret kosong berarti tidak diganti.
+	UpdateWebhook(hook *models.Webhook) error
+	// DeleteWebhook menghapus webhook beserta log pengirimannya.
+	DeleteWebhook(id int) error
+
+	// EnqueueWebhookEvent membuat satu pengiriman untuk setiap webhook aktif yang berlangganan
+	// event tersebut, dan mengembalikan jumlah pengiriman yang dibuat.
+	EnqueueWebhookEvent(eventID, event, payload string) (int, error)
+	// GetDueWebhookDeliveries mengambil pengiriman pending yang jadwalnya sudah tiba, terlama lebih dulu,
+	// termasuk pengiriman sending yang klaimnya sudah kedaluwarsa.
+	GetDueWebhookDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// ClaimWebhookDelivery menandai pengiriman sending sebelum dikirim. false berarti sudah
+	// diklaim worker lain sehingga tidak boleh dikirim.
+	ClaimWebhookDelivery(id int, now time.Time) (bool, error)
+	// GetWebhookDeliveries mengambil log pengiriman terbaru milik satu webhook.
+	GetWebhookDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error)
+	MarkWebhookDelivered(id, responseCode int, deliveredAt time.Time) error
+	// MarkWebhookFailed mencatat percobaan yang gagal; retryAt nil berarti tidak dicoba lagi.
+	MarkWebhookFailed(id, responseCode int, lastError string, retryAt *time.Time) error
+	// RedeliverWebhook membuat pengiriman baru dengan payload yang sama dari pengiriman lama.
+	RedeliverWebhook(deliveryID int) (*models.WebhookDelivery, error)
+}
+
+// JobStore mencatat eksekusi job terjadwal (lihat package scheduler). Satu slot jadwal
+// (job_name, scheduled_at) hanya bisa diklaim sekali, sehingga beberapa instance aplikasi
+// yang berbagi database tidak menjalankan job yang sama dua kali.
+type JobStore interface {
+	// StartJobRun mengklaim slot run.ScheduledAt untuk job run.JobName dan mencatatnya berstatus
+	// "running"; ErrJobClaimed jika slot tersebut sudah diklaim instance lain.
+	StartJobRun(run *models.JobRun) error
+	// FinishJobRun mencatat hasil run dan memperbarui ringkasan run terakhir di tabel jobs.
+	FinishJobRun(run *models.JobRun) error
+	// GetJobs mengambil ringkasan run terakhir semua job yang pernah dijalankan.
+	GetJobs() ([]models.Job, error)
+	// GetJobRuns mengambil riwayat run terbaru, difilter nama job jika tidak kosong.
+	GetJobRuns(name string, limit int) ([]models.JobRun, error)
+}
+
+// LoanPolicyStore mengelola kebijakan peminjaman per role (dan opsional per kategori).
+// Lihat ResolveLoanPolicy untuk urutan pemilihan kebijakan yang berlaku.
+type LoanPolicyStore interface {
+	GetLoanPolicies() ([]models.LoanPolicy, error)
+	GetLoanPolicyByID(id int) (*models.LoanPolicy, error)
+	CreateLoanPolicy(p *models.LoanPolicy) error
+	UpdateLoanPolicy(p *models.LoanPolicy) error
+	DeleteLoanPolicy(id int) error
+}
+
+// FineStore mengelola buku besar denda. Tagihan dibuat otomatis oleh ReturnBook;
+// pembayaran dan pembebasan mengurangi saldo dan tidak boleh melebihi saldo tertunggak
+// (saldo pinjaman tersebut jika LoanID diisi).
+type FineStore interface {
+	GetFineEntries(userID string) ([]models.FineEntry, error)
+	GetFineEntry(id int) (*models.FineEntry, error)
+	GetFineBalance(userID string) (*models.FineBalance, error)
+	GetOutstandingBalances() ([]models.FineBalance, error)
+	RecordFinePayment(entry *models.FineEntry) error
+	WaiveFine(entry *models.FineEntry) error
+}
+
+// HolidayStore mengelola tanggal libur perpustakaan (lihat Calendar).
+type HolidayStore interface {
+	GetHolidays() ([]models.Holiday, error)
+	CreateHoliday(h *models.Holiday) error
+	DeleteHoliday(id int) error
+	ImportHolidays(holidays []models.Holiday) (int, error)
+}
+
+// SettingsStore mengelola pengaturan aplikasi.
+// Setiap perubahan lewat UpdateSettings dicatat per field sebagai audit trail.
+// Kop surat disimpan terpisah karena berisi teks; perubahannya hanya mencatat pengubah terakhir.
+type SettingsStore interface {
+	GetSettings() (*models.Settings, error)
+	UpdateSettings(settings *models.Settings, userID, username string) ([]models.SettingsChange, error)
+	GetSettingsHistory(limit int) ([]models.SettingsChange, error)
+	GetLetterhead() (*models.Letterhead, error)
+	UpdateLetterhead(lh *models.Letterhead) error
+}
+
+// ReportStore menghitung statistik sirkulasi untuk laporan admin dalam rentang tanggal.
+// Pinjaman dikelompokkan menurut tanggal pinjam, pengembalian menurut tanggal kembali,
+// buku besar denda menurut tanggal entri, dan keterlambatan menurut tanggal jatuh tempo.
+// Interval periode adalah ReportDaily, ReportWeekly atau ReportMonthly (lihat ReportPeriod).
+type ReportStore interface {
+	GetLoanVolume(r models.ReportRange, interval string) ([]models.LoanVolume, error)
+	GetTopTitles(r models.ReportRange, limit int) ([]models.TitleCount, error)
+	GetTopCategories(r models.ReportRange, limit int) ([]models.CategoryCount, error)
+	GetTopBorrowers(r models.ReportRange, limit int) ([]models.BorrowerCount, error)
+	GetFineReport(r models.ReportRange, interval string) (*models.FineReport, error)
+	// GetOverdueReport dan GetRoleReport menganggap pinjaman yang belum kembali terlambat
+	// jika jatuh temponya sebelum now.
+	GetOverdueReport(r models.ReportRange, now time.Time) (*models.OverdueReport, error)
+	GetRoleReport(r models.ReportRange, now time.Time) ([]models.RoleReport, error)
+}
+
+// ExportStore membaca data baris demi baris untuk ekspor berkas tanpa memuat seluruh tabel
+// ke memori. fn dipanggil untuk setiap baris sesuai urutan GetLoansFiltered, GetAllUsers dan
+// GetAllBooks; error dari fn menghentikan iterasi dan dikembalikan apa adanya.
+type ExportStore interface {
+	EachLoan(startDate, endDate time.Time, fn func(*models.Loan) error) error
+	EachUser(fn func(*models.User) error) error
+	EachBook(fn func(*models.Book) error) error
+}
+
+// Store adalah gabungan seluruh kemampuan penyimpanan yang dibutuhkan aplikasi.
+// Diimplementasikan oleh SQLStore (MySQL/SQLite) dan MemoryStore.
+type Store interface {
+	UserStore
+	BookStore
+	BookItemStore
+	LoanStore
+	HoldStore
+	NotificationStore
+	OutboxStore
+	WebhookStore
+	JobStore
+	LoanPolicyStore
+	HolidayStore
+	FineStore
+	SettingsStore
+	ReportStore
+	ExportStore
+
+	InitSchema() error
+	Close() error
+}
+
+// HealthChecker diimplementasikan store berbasis database (SQLStore) untuk probe kesiapan
+// dan metrik pool koneksi. MemoryStore tidak mengimplementasikannya.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+	DBStats() sql.DBStats
+}
+
+// Status dan kondisi eksemplar buku.
+const (
+	ItemAvailable   = "available"
+	ItemBorrowed    = "borrowed"
+	ItemMaintenance = "maintenance"
+	ItemLost        = "lost"
+	ItemReserved    = "reserved" // Disisihkan untuk reservasi yang siap diambil
+
+	ConditionGood    = "good"
+	ConditionFair    = "fair"
+	ConditionDamaged = "damaged"
+)
+
+// Status peminjaman. "borrowed" dan "late" dianggap aktif (buku belum kembali);
+// "late" diatur oleh pemeriksaan harian worker lewat SetLoanAccrual.
+const (
+	LoanBorrowed = "borrowed"
+	LoanLate     = "late"
+	LoanReturned = "returned"
+)
+
+// openLoanStatuses adalah daftar status pinjaman aktif untuk klausa SQL "status IN (...)".
+const openLoanStatuses = "'borrowed', 'late'"
+
+// IsOpenLoan memeriksa apakah status pinjaman berarti buku belum dikembalikan.
+func IsOpenLoan(status string) bool {
+	return status == LoanBorrowed || status == LoanLate
+}
+
+// Status reservasi. "waiting" dan "ready" dianggap aktif.
+const (
+	HoldWaiting   = "waiting"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldExpired   = "expired"
+	HoldCancelled = "cancelled"
+)
+
+// Jenis entri buku besar denda dan metode pembayaran.
+const (
+	FineCharge  = "charge"
+	FinePayment = "payment"
+	FineWaiver  = "waiver"
+
+	PaymentCash     = "cash"
+	PaymentTransfer = "transfer"
+)
+
+// Kategori dan tingkat kepentingan notifikasi.
+const (
+	NotifyGeneral   = "general" // Notifikasi lama yang dibuat sebelum ada kategori
+	NotifyReminder  = "reminder"
+	NotifyOverdue   = "overdue"
+	NotifyLoan      = "loan"
+	NotifyHold      = "hold"
+	NotifyFine      = "fine"
+	NotifyAccount   = "account"
+	NotifyBroadcast = "broadcast"
+
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// NotificationCategories adalah kategori yang dapat diatur pengguna beserta nilai bawaannya,
+// sesuai urutan tampilan. Kategori yang tidak Mutable selalu dikirim ke aplikasi.
+var NotificationCategories = []models.NotificationPreference{
+	{Category: NotifyReminder, Label: "Pengingat jatuh tempo", Mutable: true},
+	{Category: NotifyOverdue, Label: "Keterlambatan dan denda berjalan", Mutable: true},
+	{Category: NotifyLoan, Label: "Peminjaman, pengembalian dan perpanjangan", Mutable: true},
+	{Category: NotifyHold, Label: "Reservasi", Mutable: true},
+	{Category: NotifyFine, Label: "Pembayaran dan pembebasan denda", Mutable: true},
+	{Category: NotifyBroadcast, Label: "Pengumuman perpustakaan", Mutable: true},
+	{Category: NotifyAccount, Label: "Status akun", Mutable: false},
+	{Category: NotifyGeneral, Label: "Lainnya", Mutable: true},
+}
+
+// IsNotificationCategory memeriksa apakah kategori dikenal.
+func IsNotificationCategory(category string) bool {
+	for _, c := range NotificationCategories {
+		if c.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotificationSeverity memeriksa apakah tingkat kepentingan dikenal.
+func IsNotificationSeverity(severity string) bool {
+	return severity == SeverityInfo || severity == SeverityWarning || severity == SeverityCritical
+}
+
+// mergeNotificationPreferences melengkapi preferensi tersimpan dengan nilai bawaan
+// (aktif di aplikasi dan email) untuk setiap kategori, sesuai urutan NotificationCategories.
+func mergeNotificationPreferences(saved []models.NotificationPreference) []models.NotificationPreference {
+	prefs := make([]models.NotificationPreference, 0, len(NotificationCategories))
+	for _, c := range NotificationCategories {
+		pref := c
+		pref.Enabled, pref.Email = true, true
+		for _, sp := range saved {
+			if sp.Category == c.Category && c.Mutable {
+				pref.Enabled, pref.Email = sp.Enabled, sp.Email
+			}
+		}
+		prefs = append(prefs, pref)
+	}
+	return prefs
+}
+
+// FindNotificationPreference mengambil preferensi satu kategori dari hasil GetNotificationPreferences.
+// Kategori yang tidak dikenal dianggap aktif.
+func FindNotificationPreference(prefs []models.NotificationPreference, category string) models.NotificationPreference {
+	for _, p := range prefs {
+		if p.Category == category {
+			return p
+		}
+	}
+	return models.NotificationPreference{Category: category, Enabled: true, Email: true}
+}
+
+// Status pesan outbox.
+const (
+	OutboxPending = "pending"
+	OutboxSending = "sending" // Sedang dikirim oleh satu worker (lihat ClaimOutbox)
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed" // Batas percobaan habis, hanya dikirim lagi lewat RetryOutbox
+)
+
+// OutboxClaimTimeout adalah lama klaim pengiriman outbox. Pesan berstatus sending yang
+// tidak selesai dalam waktu ini (misalnya aplikasi mati saat mengirim) dianggap pending lagi.
+const OutboxClaimTimeout = 10 * time.Minute
+
+// Status pengiriman webhook.
+const (
+	DeliveryPending   = "pending"
+	DeliverySending   = "sending" // Sedang dikirim oleh satu worker (lihat ClaimWebhookDelivery)
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // Batas percobaan habis atau webhook dinonaktifkan
+)
+
+// DeliveryClaimTimeout adalah lama klaim pengiriman webhook, seperti OutboxClaimTimeout.
+const DeliveryClaimTimeout = 10 * time.Minute
+
+// Status run job terjadwal dan pemicunya.
+const (
+	JobRunning = "running"
+	JobSuccess = "success"
+	JobFailed  = "failed"
+
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// WebhookSubscribes memeriksa apakah daftar event langganan mencakup event tertentu ("*" = semua).
+func WebhookSubscribes(events []string, event string) bool {
+	for _, e := range events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateBarcode membuat barcode bawaan untuk eksemplar yang tidak diberi barcode.
+func GenerateBarcode(bookID, copyNo int) string {
+	return fmt.Sprintf("SP%05d-%03d", bookID, copyNo)
+}
+
+// checkItemStatusChange memastikan perubahan status eksemplar secara manual tidak
+// menyentuh status yang dikelola proses peminjaman ("borrowed") dan reservasi ("reserved").
+func checkItemStatusChange(current, next string) error {
+	if current == next {
+		return nil
+	}
+	if current == ItemBorrowed || next == ItemBorrowed {
+		return ErrItemBorrowed
+	}
+	if current == ItemReserved || next == ItemReserved {
+		return ErrItemReserved
+	}
+	return nil
+}
+
+// DefaultSettings adalah pengaturan bawaan jika belum ada data di penyimpanan.
+func DefaultSettings() *models.Settings {
+	return &models.Settings{ID: 1, MaxLoanBooks: 3, LoanDuration: 7, FinePerDay: 5000, HoldPickupDays: 3, MaxRenewals: 2,
+		MaxOverdueLoans: -1, MaxOutstandingFine: -1}
+}
+
+// DefaultLetterhead adalah kop surat sebelum diatur admin.
+func DefaultLetterhead() *models.Letterhead {
+	return &models.Letterhead{LibraryName: "Perpustakaan SIMPUS"}
+}
+
+// LoanSlipNo membuat nomor slip peminjaman dari tanggal pinjam dan ID pinjaman.
+func LoanSlipNo(loan *models.Loan) string {
+	return fmt.Sprintf("PJ-%s-%06d", loan.LoanDate.Format("20060102"), loan.ID)
+}
+
+// ReturnReceiptNo membuat nomor bukti pengembalian dari tanggal kembali dan ID pinjaman.
+// Kosong jika pinjaman belum dikembalikan.
+func ReturnReceiptNo(loan *models.Loan) string {
+	if loan.ReturnDate == nil {
+		return ""
+	}
+	return fmt.Sprintf("KB-%s-%06d", loan.ReturnDate.Format("20060102"), loan.ID)
+}
+
+// GenerateReceiptNo membuat nomor kuitansi pembayaran denda dari tanggal dan ID entri.
+func GenerateReceiptNo(date time.Time, entryID int) string {
+	return fmt.Sprintf("KW-%s-%06d", date.Format("20060102"), entryID)
+}
+
+// FineDocumentNo mengembalikan nomor dokumen entri denda: nomor kuitansi untuk pembayaran
+// (dibuat dari tanggal dan ID jika kosong) atau nomor bukti pembebasan untuk waiver.
+func FineDocumentNo(entry *models.FineEntry) string {
+	if entry.Type == FineWaiver {
+		return fmt.Sprintf("BD-%s-%06d", entry.CreatedAt.Format("20060102"), entry.ID)
+	}
+	if entry.ReceiptNo != "" {
+		return entry.ReceiptNo
+	}
+	return GenerateReceiptNo(entry.CreatedAt, entry.ID)
+}
+
+// sortBalances mengurutkan saldo denda dari tunggakan terbesar, lalu username.
+func sortBalances(balances []models.FineBalance) {
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Outstanding != balances[j].Outstanding {
+			return balances[i].Outstanding > balances[j].Outstanding
+		}
+		return balances[i].Username < balances[j].Username
+	})
+}
+
+// settingsField adalah pasangan nama kolom dan nilai pengaturan.
+type settingsField struct {
+	name  string
+	value int
+}
+
+// settingsFields mengurai pengaturan menjadi daftar field dengan urutan tetap,
+// dipakai untuk membandingkan perubahan dan mengisi audit trail.
+func settingsFields(s *models.Settings) []settingsField {
+	return []settingsField{
+		{"max_loan_books", s.MaxLoanBooks},
+		{"loan_duration", s.LoanDuration},
+		{"fine_per_day", s.FinePerDay},
+		{"hold_pickup_days", s.HoldPickupDays},
+		{"max_renewals", s.MaxRenewals},
+		{"closed_weekdays", s.ClosedWeekdays},
+		{"max_overdue_loans", s.MaxOverdueLoans},
+		{"max_outstanding_fine", s.MaxOutstandingFine},
+	}
+}
+
+// diffSettings mengembalikan field pengaturan yang nilainya berubah.
+func diffSettings(old, next *models.Settings, userID, username string, now time.Time) []models.SettingsChange {
+	before, after := settingsFields(old), settingsFields(next)
+	var changes []models.SettingsChange
+	for i := range before {
+		if before[i].value == after[i].value {
+			continue
+		}
+		changes = append(changes, models.SettingsChange{
+			ChangedBy:     userID,
+			ChangedByName: username,
+			Field:         before[i].name,
+			OldValue:      before[i].value,
+			NewValue:      after[i].value,
+			ChangedAt:     now,
+		})
+	}
+	return changes
+}
+
+// CalculateFine menghitung denda keterlambatan berdasarkan tanggal jatuh tempo dan tanggal kembali,
+// yaitu LateDays × finePerDay.
+func CalculateFine(dueDate, returnDate time.Time, finePerDay int, cal *Calendar) int {
+	return LateDays(dueDate, returnDate, cal) * finePerDay
+}
+
+// LateDays menghitung hari keterlambatan yang dikenai denda. Keterlambatan kurang dari 24 jam
+// tetapi sudah berganti hari dihitung 1 hari. Hari tutup pada kalender (boleh nil) tidak dihitung.
+func LateDays(dueDate, returnDate time.Time, cal *Calendar) int {
+	if !returnDate.After(dueDate) {
+		return 0
+	}
+
+	daysLate := int(returnDate.Sub(dueDate).Hours() / 24)
+	if daysLate < 1 {
+		if returnDate.Day() != dueDate.Day() || returnDate.Month() != dueDate.Month() || returnDate.Year() != dueDate.Year() {
+			daysLate = 1
+		} else {
+			daysLate = 0
+		}
+	}
+
+	return daysLate - cal.ClosedDaysAfter(dueDate, daysLate)
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"latihan_cloud8/models"
+	"path/filepath"
+	"testing"
+)
+
+// newSQLiteTestStore membuat SQLStore SQLite di direktori sementara dengan semua migrasi diterapkan.
+func newSQLiteTestStore(t *testing.T) *SQLStore {
+	t.Helper()
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "simpus.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if _, err := s.MigrateUp(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return s
+}
+
+// eachStore menjalankan test yang sama terhadap MemoryStore dan SQLStore (SQLite), karena
+// keduanya harus memiliki semantik yang sama.
+func eachStore(t *testing.T, test func(t *testing.T, s Store)) {
+	t.Run("memory", func(t *testing.T) { test(t, NewMemoryStore()) })
+	t.Run("sqlite", func(t *testing.T) { test(t, newSQLiteTestStore(t)) })
+}
+
+func createTestUser(t *testing.T, s Store, username string) *models.User {
+	t.Helper()
+	u, err := s.CreateUser(username, "hash", "anggota", username)
+	if err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return u
+}
+
+func createTestBook(t *testing.T, s Store, title string, stock int) *models.Book {
+	t.Helper()
+	b := &models.Book{Title: title, Author: "Penulis", Category: "Umum", Stock: stock}
+	if err := s.CreateBook(b); err != nil {
+		t.Fatalf("create book %s: %v", title, err)
+	}
+	return b
+}
+
+func bookStock(t *testing.T, s Store, id int) int {
+	t.Helper()
+	b, err := s.GetBookByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Stock
+}
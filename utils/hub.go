@@ -1,29 +1,46 @@
 package utils
 
 import (
+	"encoding/json"
+	"latihan_cloud8/models"
+	"log"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
+const (
+	// Batas waktu menulis satu pesan ke klien
+	writeWait = 10 * time.Second
+	// Batas waktu menunggu pong dari klien sebelum koneksi dianggap putus
+	pongWait = 60 * time.Second
+	// Interval ping, harus lebih pendek dari pongWait
+	pingPeriod = (pongWait * 9) / 10
+	// Klien hanya mengirim pong/close, pesan besar ditolak
+	maxMessageSize = 512
+	// Antrean pesan per koneksi; klien yang terlalu lambat diputus
+	sendBufferSize = 16
+)
+
+// Upgrader memakai pemeriksaan Origin bawaan (harus sama dengan Host) karena
+// autentikasi WebSocket dari browser memakai cookie token.
+var upgrader = websocket.Upgrader{}
 
 type Client struct {
 	UserID string
-	Conn   *websocket.Conn
+	Conn   *websocket.Conn // Nil untuk penerima Server-Sent Events
 	Send   chan []byte
 }
 
 type Hub struct {
-	Clients    map[string]*Client // Map UserID -> Client
+	Clients    map[string]map[*Client]bool // Map UserID -> koneksi aktif (satu per tab)
 	Register   chan *Client
 	Unregister chan *Client
 	Broadcast  chan Message
+	quit       chan struct{}
+	closed     bool // Setelah Close, klien baru langsung diputus
 	mu         sync.Mutex
 }
 
@@ -35,44 +52,181 @@ type Message struct {
 // NewHub membuat instance Hub baru untuk mengelola koneksi WebSocket.
 func NewHub() *Hub {
 	return &Hub{
-		Clients:    make(map[string]*Client),
+		Clients:    make(map[string]map[*Client]bool),
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
-		Broadcast:  make(chan Message),
+		Broadcast:  make(chan Message, 64),
+		quit:       make(chan struct{}),
 	}
 }
 
 // Run menjalankan loop utama Hub untuk memproses register, unregister, dan broadcast pesan.
+// Pesan untuk seorang pengguna dikirim ke semua koneksinya (multi tab).
 func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.Register:
 			h.mu.Lock()
-			h.Clients[client.UserID] = client
+			if h.closed {
+				close(client.Send)
+				h.mu.Unlock()
+				continue
+			}
+			if h.Clients[client.UserID] == nil {
+				h.Clients[client.UserID] = make(map[*Client]bool)
+			}
+			h.Clients[client.UserID][client] = true
 			h.mu.Unlock()
 		case client := <-h.Unregister:
 			h.mu.Lock()
-			if current, ok := h.Clients[client.UserID]; ok && current == client {
-				delete(h.Clients, client.UserID)
-				close(client.Send)
-			}
+			h.remove(client)
 			h.mu.Unlock()
 		case message := <-h.Broadcast:
 			h.mu.Lock()
-			client, ok := h.Clients[message.UserID]
-			if ok {
+			for client := range h.Clients[message.UserID] {
 				select {
 				case client.Send <- []byte(message.Content):
 				default:
-					close(client.Send)
-					delete(h.Clients, client.UserID)
+					// Klien tidak membaca pesan, putuskan agar Hub tidak ikut tertahan
+					h.remove(client)
+				}
+			}
+			h.mu.Unlock()
+		case <-h.quit:
+			h.mu.Lock()
+			for _, conns := range h.Clients {
+				for client := range conns {
+					h.remove(client)
 				}
 			}
+			h.closed = true
 			h.mu.Unlock()
 		}
 	}
 }
 
+// Close memutus semua koneksi saat aplikasi berhenti: klien WebSocket menerima close frame
+// dan stream SSE selesai sehingga browser menyambung ulang ke instance lain atau setelah restart.
+// Hub tetap berjalan agar Unregister dari koneksi yang sedang ditutup tidak tertahan.
+func (h *Hub) Close() {
+	h.quit <- struct{}{}
+}
+
+// remove melepas klien dari Hub dan menutup antrean kirimnya. Pemanggil wajib memegang h.mu.
+func (h *Hub) remove(client *Client) {
+	conns, ok := h.Clients[client.UserID]
+	if !ok || !conns[client] {
+		return
+	}
+	delete(conns, client)
+	close(client.Send)
+	if len(conns) == 0 {
+		delete(h.Clients, client.UserID)
+	}
+}
+
+// ConnectionCount menghitung koneksi aktif milik seorang pengguna.
+func (h *Hub) ConnectionCount(userID string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.Clients[userID])
+}
+
+// PublishNotification mengirim notifikasi baru ke semua koneksi (WebSocket/SSE) penerimanya.
+func (h *Hub) PublishNotification(n models.Notification) {
+	if n.ID == 0 {
+		return // Kategori dimatikan di aplikasi, tidak dikirim ke browser
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":         "notification",
+		"notification": n,
+	})
+	if err != nil {
+		log.Println("Hub marshal error:", err)
+		return
+	}
+	h.Broadcast <- Message{UserID: n.UserID, Content: string(payload)}
+}
+
+// Subscribe mendaftarkan penerima tanpa WebSocket (misalnya Server-Sent Events) untuk userID.
+// Pesan diterima dari client.Send; channel ditutup jika penerima terlalu lambat.
+// Panggil Unsubscribe setelah selesai.
+func (h *Hub) Subscribe(userID string) *Client {
+	client := &Client{UserID: userID, Send: make(chan []byte, sendBufferSize)}
+	h.Register <- client
+	return client
+}
+
+// Unsubscribe melepas penerima yang didaftarkan lewat Subscribe.
+func (h *Hub) Unsubscribe(client *Client) {
+	h.Unregister <- client
+}
+
+// ServeWS meng-upgrade request HTTP menjadi koneksi WebSocket milik userID
+// lalu menjalankan read pump dan write pump untuk koneksi tersebut.
+func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request, userID string) error {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err // Upgrader sudah menulis respon error
+	}
+
+	client := &Client{UserID: userID, Conn: conn, Send: make(chan []byte, sendBufferSize)}
+	h.Register <- client
+
+	go client.writePump()
+	go client.readPump(h)
+	return nil
+}
+
+// readPump membaca pesan dari klien hanya untuk memproses pong dan close.
+// Saat koneksi putus, klien dilepas dari Hub.
+func (c *Client) readPump(h *Hub) {
+	defer func() {
+		h.Unregister <- c
+		c.Conn.Close()
+	}()
+
+	c.Conn.SetReadLimit(maxMessageSize)
+	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.Conn.SetPongHandler(func(string) error {
+		return c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		if _, _, err := c.Conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump mengirim pesan dari antrean Send dan ping berkala ke klien.
+// Berhenti saat antrean ditutup oleh Hub atau penulisan gagal.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.Conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.Send:
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.Conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
 // Global Hub
 var NotificationHub = NewHub()
 
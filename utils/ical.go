@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"latihan_cloud8/models"
+	"strings"
+	"time"
+)
+
+// maxEventDays membatasi panjang satu event agar file yang keliru tidak menghasilkan
+// ribuan tanggal libur.
+const maxEventDays = 366
+
+var ErrNotICalendar = errors.New("not an iCalendar file")
+
+// ParseICalHolidays membaca event (VEVENT) dari file iCalendar (RFC 5545) sebagai daftar
+// tanggal libur. Event beberapa hari dipecah menjadi satu tanggal per hari; DTEND bertipe
+// tanggal bersifat eksklusif. Aturan pengulangan (RRULE) tidak diperluas.
+func ParseICalHolidays(r io.Reader) ([]models.Holiday, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []models.Holiday
+	var inEvent, isCalendar bool
+	var start, end, summary string
+	for _, line := range lines {
+		name, value := splitICalProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			isCalendar = true
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			start, end, summary = "", "", ""
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			days, err := icalEventDates(start, end)
+			if err != nil {
+				return nil, err
+			}
+			if summary == "" {
+				summary = "Libur"
+			}
+			for _, d := range days {
+				holidays = append(holidays, models.Holiday{Date: d, Name: summary})
+			}
+		case !inEvent:
+		case name == "DTSTART":
+			start = value
+		case name == "DTEND":
+			end = value
+		case name == "SUMMARY":
+			summary = unescapeICalText(value)
+		}
+	}
+	if !isCalendar {
+		return nil, ErrNotICalendar
+	}
+	return holidays, nil
+}
+
+// unfoldICalLines membaca baris iCalendar dan menggabungkan baris lanjutan
+// (diawali spasi atau tab) ke baris sebelumnya.
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// splitICalProperty memisahkan nama properti (tanpa parameter) dan nilainya.
+func splitICalProperty(line string) (string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return strings.ToUpper(line), ""
+	}
+	name := line[:i]
+	if j := strings.Index(name, ";"); j >= 0 {
+		name = name[:j]
+	}
+	return strings.ToUpper(name), strings.TrimSpace(line[i+1:])
+}
+
+// icalEventDates mengubah DTSTART/DTEND menjadi daftar tanggal (YYYY-MM-DD).
+func icalEventDates(start, end string) ([]string, error) {
+	if len(start) < 8 {
+		return nil, errors.New("invalid DTSTART in iCalendar event")
+	}
+	from, err := time.Parse("20060102", start[:8])
+	if err != nil {
+		return nil, errors.New("invalid DTSTART in iCalendar event")
+	}
+
+	// Tanpa DTEND event berlangsung satu hari
+	to := from.AddDate(0, 0, 1)
+	if len(end) >= 8 {
+		if to, err = time.Parse("20060102", end[:8]); err != nil {
+			return nil, errors.New("invalid DTEND in iCalendar event")
+		}
+		// DTEND berupa tanggal+jam bersifat inklusif kecuali tepat tengah malam
+		if len(end) > 8 && !strings.HasPrefix(end[8:], "T000000") {
+			to = to.AddDate(0, 0, 1)
+		}
+		if !to.After(from) {
+			to = from.AddDate(0, 0, 1)
+		}
+	}
+
+	var dates []string
+	for d := from; d.Before(to) && len(dates) < maxEventDays; d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates, nil
+}
+
+// unescapeICalText mengembalikan karakter yang di-escape pada nilai TEXT iCalendar.
+func unescapeICalText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
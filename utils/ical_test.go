@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"latihan_cloud8/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ical membungkus baris-baris event menjadi file iCalendar dengan akhir baris CRLF.
+func ical(lines ...string) string {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	all = append(all, "END:VCALENDAR")
+	return strings.Join(all, "\r\n") + "\r\n"
+}
+
+func TestParseICalHolidays(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []models.Holiday
+	}{
+		{
+			name: "all-day event",
+			in: ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260817", "DTEND;VALUE=DATE:20260818",
+				"SUMMARY:Hari Kemerdekaan", "END:VEVENT"),
+			want: []models.Holiday{{Date: "2026-08-17", Name: "Hari Kemerdekaan"}},
+		},
+		{
+			name: "multi-day DTEND is exclusive",
+			in: ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260320", "DTEND;VALUE=DATE:20260323",
+				"SUMMARY:Idul Fitri", "END:VEVENT"),
+			want: []models.Holiday{
+				{Date: "2026-03-20", Name: "Idul Fitri"},
+				{Date: "2026-03-21", Name: "Idul Fitri"},
+				{Date: "2026-03-22", Name: "Idul Fitri"},
+			},
+		},
+		{
+			name: "multi-day across month end",
+			in: ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20261231", "DTEND;VALUE=DATE:20270102",
+				"SUMMARY:Tahun Baru", "END:VEVENT"),
+			want: []models.Holiday{
+				{Date: "2026-12-31", Name: "Tahun Baru"},
+				{Date: "2027-01-01", Name: "Tahun Baru"},
+			},
+		},
+		{
+			name: "without DTEND lasts one day",
+			in:   ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20261225", "SUMMARY:Natal", "END:VEVENT"),
+			want: []models.Holiday{{Date: "2026-12-25", Name: "Natal"}},
+		},
+		{
+			name: "date-time DTEND is inclusive unless midnight",
+			in: ical("BEGIN:VEVENT", "DTSTART:20260501T080000", "DTEND:20260502T120000", "SUMMARY:Rapat", "END:VEVENT",
+				"BEGIN:VEVENT", "DTSTART:20260601T000000", "DTEND:20260602T000000", "SUMMARY:Pancasila", "END:VEVENT"),
+			want: []models.Holiday{
+				{Date: "2026-05-01", Name: "Rapat"},
+				{Date: "2026-05-02", Name: "Rapat"},
+				{Date: "2026-06-01", Name: "Pancasila"},
+			},
+		},
+		{
+			name: "folded lines and escaped text",
+			in: ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260214", "SUMMARY:Tahun Baru Imlek\\, libur na",
+				" sional", "\tbersama", "END:VEVENT"),
+			want: []models.Holiday{{Date: "2026-02-14", Name: "Tahun Baru Imlek, libur nasionalbersama"}},
+		},
+		{
+			name: "missing summary",
+			in:   ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260101", "END:VEVENT"),
+			want: []models.Holiday{{Date: "2026-01-01", Name: "Libur"}},
+		},
+		{
+			name: "properties outside events are ignored",
+			in:   ical("DTSTART;VALUE=DATE:20260101", "X-WR-CALNAME:Libur Nasional"),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseICalHolidays(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseICalHolidaysErrors(t *testing.T) {
+	if _, err := ParseICalHolidays(strings.NewReader("Tanggal,Nama\n2026-01-01,Tahun Baru\n")); !errors.Is(err, ErrNotICalendar) {
+		t.Errorf("CSV err = %v, want ErrNotICalendar", err)
+	}
+	bad := ical("BEGIN:VEVENT", "DTSTART;VALUE=DATE:2026", "END:VEVENT")
+	if _, err := ParseICalHolidays(strings.NewReader(bad)); err == nil {
+		t.Error("invalid DTSTART accepted")
+	}
+}
@@ -0,0 +1,162 @@
+// Package webhook mengirim event perpustakaan ke sistem lain (sistem akademik, chat bot)
+// sebagai HTTP POST JSON yang ditandatangani HMAC-SHA256.
+//
+// Setiap request membawa header:
+//
+//	X-Simpus-Event      nama event, mis. "loan.created"
+//	X-Simpus-Delivery   ID pengiriman (berubah setiap pengiriman ulang)
+//	X-Simpus-Timestamp  waktu kirim dalam detik Unix
+//	X-Simpus-Signature  "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+//
+// Penerima sebaiknya menghitung ulang tanda tangan, menolak timestamp yang terlalu lama,
+// dan memakai field "id" pada body untuk mengabaikan event yang sudah pernah diproses.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event yang dapat dilanggan.
+const (
+	EventLoanCreated    = "loan.created"
+	EventLoanReturned   = "loan.returned"
+	EventLoanOverdue    = "loan.overdue"
+	EventBookCreated    = "book.created"
+	EventUserRegistered = "user.registered"
+)
+
+// Events adalah daftar semua event yang dikenal.
+var Events = []string{EventLoanCreated, EventLoanReturned, EventLoanOverdue, EventBookCreated, EventUserRegistered}
+
+const (
+	// MaxAttempts adalah batas percobaan sebelum pengiriman dinyatakan gagal.
+	MaxAttempts = 8
+	// Batas waktu satu request ke penerima
+	requestTimeout = 10 * time.Second
+	// Potongan body respon yang disimpan sebagai pesan error
+	maxErrorBody = 200
+)
+
+// ValidEvent memeriksa apakah nama event dikenal ("*" berarti semua event).
+func ValidEvent(event string) bool {
+	if event == "*" {
+		return true
+	}
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryDelay menghitung jeda sebelum percobaan berikutnya setelah attempts kali gagal:
+// 30 detik, 1, 2, 4 menit, dan seterusnya (sekitar 1 jam pada percobaan terakhir).
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return 30 * time.Second << (attempts - 1)
+}
+
+// NewSecret membuat secret HMAC acak.
+func NewSecret() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand tidak pernah gagal pada sistem yang didukung
+	}
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// Sign menghitung nilai header X-Simpus-Signature untuk body pada waktu timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Emit memasukkan event ke antrean pengiriman semua webhook yang berlangganan.
+// Kegagalan hanya dicatat di log agar tidak menggagalkan proses yang memicu event.
+func Emit(st store.Store, event string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Webhook error (%s): %v", event, err)
+		return
+	}
+	id := uuid.NewString()
+	payload, err := json.Marshal(models.WebhookEvent{
+		ID:        id,
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      raw,
+	})
+	if err != nil {
+		log.Printf("Webhook error (%s): %v", event, err)
+		return
+	}
+
+	if _, err := st.EnqueueWebhookEvent(id, event, string(payload)); err != nil {
+		log.Printf("Webhook error (%s): %v", event, err)
+	}
+}
+
+// EmitLoan memuat pinjaman (beserta ringkasan buku dan peminjam) lalu mengirim event-nya.
+func EmitLoan(st store.Store, event string, loanID int) {
+	loan, err := st.GetLoanByID(loanID)
+	if err != nil {
+		log.Printf("Webhook error (%s): %v", event, err)
+		return
+	}
+	Emit(st, event, loan)
+}
+
+// Client mengirim pengiriman webhook lewat HTTP.
+var Client = &http.Client{Timeout: requestTimeout}
+
+// Deliver mengirim satu pengiriman ke URL webhook. Respon 2xx dianggap berhasil;
+// selain itu error dikembalikan bersama status HTTP (0 jika tidak ada respon).
+func Deliver(hook *models.Webhook, d *models.WebhookDelivery) (int, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SIMPUS-Webhook/1.0")
+	req.Header.Set("X-Simpus-Event", d.Event)
+	req.Header.Set("X-Simpus-Delivery", strconv.Itoa(d.ID))
+	req.Header.Set("X-Simpus-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Simpus-Signature", Sign(hook.Secret, timestamp, body))
+
+	resp, err := Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return resp.StatusCode, nil
+}
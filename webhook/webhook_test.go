@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"io"
+	"latihan_cloud8/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+const testBody = `{"id":"evt_1","event":"loan.created"}`
+
+// Nilai acuan dihitung di luar Go:
+//
+//	printf '%s' '1760000000.{"id":"evt_1","event":"loan.created"}' | openssl dgst -sha256 -hmac whsec_test
+func TestSignKnownVector(t *testing.T) {
+	tests := []struct {
+		timestamp int64
+		want      string
+	}{
+		{1760000000, "sha256=6a7944955802f89375ab51d4c15e0aef1e1d265af160360ecdedb5d53617af93"},
+		// Timestamp ikut ditandatangani, jadi body yang sama pada detik lain berbeda tanda tangannya
+		{1760000001, "sha256=9bf7d17af79a2c049c565432cc29b57cb19984ad701d03de8536dee189957c42"},
+	}
+	for _, tt := range tests {
+		if got := Sign("whsec_test", tt.timestamp, []byte(testBody)); got != tt.want {
+			t.Errorf("Sign(%d) = %s, want %s", tt.timestamp, got, tt.want)
+		}
+	}
+	if Sign("whsec_other", 1760000000, []byte(testBody)) == tests[0].want {
+		t.Error("signature does not depend on the secret")
+	}
+}
+
+func TestDeliverHeaders(t *testing.T) {
+	var got http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	hook := &models.Webhook{URL: srv.URL, Secret: "whsec_test"}
+	d := &models.WebhookDelivery{ID: 42, Event: EventLoanCreated, Payload: testBody}
+	code, err := Deliver(hook, d)
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("Deliver = %d, %v", code, err)
+	}
+
+	if string(body) != testBody {
+		t.Errorf("body = %s, want %s", body, testBody)
+	}
+	if got.Get("X-Simpus-Event") != EventLoanCreated || got.Get("X-Simpus-Delivery") != "42" {
+		t.Errorf("event/delivery headers = %q/%q", got.Get("X-Simpus-Event"), got.Get("X-Simpus-Delivery"))
+	}
+	// Penerima memverifikasi dengan timestamp dari header, seperti yang dijelaskan di dokumentasi paket
+	ts, err := strconv.ParseInt(got.Get("X-Simpus-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("X-Simpus-Timestamp = %q", got.Get("X-Simpus-Timestamp"))
+	}
+	if want := Sign(hook.Secret, ts, body); got.Get("X-Simpus-Signature") != want {
+		t.Errorf("X-Simpus-Signature = %s, want %s", got.Get("X-Simpus-Signature"), want)
+	}
+}
+
+func TestDeliverErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "signature mismatch", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	code, err := Deliver(&models.Webhook{URL: srv.URL, Secret: "s"}, &models.WebhookDelivery{Payload: "{}"})
+	if code != http.StatusUnauthorized || err == nil || err.Error() != "HTTP 401: signature mismatch" {
+		t.Fatalf("Deliver = %d, %v", code, err)
+	}
+}
@@ -1,73 +1,119 @@
 package workers
 
 import (
+	"context"
 	"fmt"
+	"latihan_cloud8/models"
 	"latihan_cloud8/store"
+	"latihan_cloud8/webhook"
 	"log"
 	"time"
 )
 
+// overdueLoan adalah data event loan.overdue: pinjaman beserta lama keterlambatan dan denda sementara.
+type overdueLoan struct {
+	models.Loan
+	DaysLate      int `json:"days_late"`
+	EstimatedFine int `json:"estimated_fine"`
+}
+
 type Notifier struct {
-	Store *store.MySQLStore
+	Store store.Store
 }
 
 // NewNotifier membuat instance Notifier baru.
-func NewNotifier(store *store.MySQLStore) *Notifier {
+func NewNotifier(store store.Store) *Notifier {
 	return &Notifier{Store: store}
 }
 
-// Start memulai worker background yang berjalan setiap 24 jam.
-func (n *Notifier) Start() {
-	ticker := time.NewTicker(24 * time.Hour)
-	// Jalankan langsung saat mulai
-	go func() {
-		n.Check() // Cek awal
-		for range ticker.C {
-			n.Check()
-		}
-	}()
+// CheckHolds mengakhiri reservasi yang melewati batas pengambilan dan
+// meneruskan eksemplarnya ke antrean berikutnya. Dijalankan scheduler sebagai job "hold_expiry".
+func (n *Notifier) CheckHolds(ctx context.Context) error {
+	expired, err := store.ExpireHoldQueue(n.Store, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(expired) > 0 {
+		log.Printf("Worker: %d hold(s) expired", len(expired))
+	}
+	return nil
 }
 
-// Check memeriksa buku yang terlambat dan mengirimkan notifikasi.
-func (n *Notifier) Check() {
+// Check menandai pinjaman yang terlambat, memperbarui denda berjalannya, dan mengirimkan notifikasi.
+// Dijalankan scheduler sebagai job "overdue_check"; pemeriksaan berhenti jika ctx dibatalkan.
+func (n *Notifier) Check(ctx context.Context) error {
 	log.Println("Worker: Checking for overdue books and reminders...")
+
 	loans, err := n.Store.GetAllBorrowedLoans()
 	if err != nil {
-		log.Println("Worker Error:", err)
-		return
+		return err
 	}
 
+	// Denda sementara mengikuti kebijakan role peminjam dan kategori buku
 	settings, _ := n.Store.GetSettings()
-	finePerDay := 5000
-	if settings != nil {
-		finePerDay = settings.FinePerDay
+	policies, err := n.Store.GetLoanPolicies()
+	if err != nil {
+		log.Println("Worker Error (policies):", err)
+	}
+	// Hari tutup perpustakaan tidak dikenai denda
+	cal, err := store.LoadCalendar(n.Store)
+	if err != nil {
+		log.Println("Worker Error (calendar):", err)
 	}
 
+	accrued, failed := 0, 0
+	var lastErr error
 	for _, l := range loans {
-		book, _ := n.Store.GetBookByID(l.BookID)
-		bookTitle := "Buku"
-		if book != nil {
-			bookTitle = book.Title
+		if err := ctx.Err(); err != nil {
+			return err
 		}
+		bookTitle := l.Book.Title
+		finePerDay := store.ResolveLoanPolicy(policies, settings, l.User.Role, l.Book.Category).FinePerDay
 
 		now := time.Now()
-		// Cek Keterlambatan
+		// Status "late" dan denda berjalan dihitung ulang dari jatuh tempo dengan rumus yang sama
+		// seperti saat pengembalian, sehingga pemeriksaan yang diulang memberi hasil yang sama
+		daysLate := store.LateDays(l.DueDate, now, cal)
+		fine := daysLate * finePerDay
+		status := store.LoanBorrowed
 		if now.After(l.DueDate) {
-			daysLate := int(now.Sub(l.DueDate).Hours() / 24)
-			if daysLate < 1 {
-				daysLate = 1
-			} // Minimum 1 day if passed due date
-			fine := daysLate * finePerDay
+			status = store.LoanLate
+		}
+		if status != l.Status || fine != l.AccruedFine {
+			if err := n.Store.SetLoanAccrual(l.ID, status, fine); err != nil {
+				log.Println("Worker Error (accrual):", err)
+				failed++
+				lastErr = err
+			} else {
+				accrued++
+			}
+		}
 
+		// Cek Keterlambatan
+		// Jumlah hari pada pesan sama dengan hari yang dikenai denda (hari tutup tidak dihitung)
+		if now.After(l.DueDate) {
 			msg := fmt.Sprintf("PERINGATAN: Buku '%s' terlambat %d hari. Denda sementara: Rp %d. Segera kembalikan!", bookTitle, daysLate, fine)
+			if daysLate == 0 {
+				// Jatuh tempo terlewat selama perpustakaan tutup, belum ada denda
+				msg = fmt.Sprintf("PERINGATAN: Buku '%s' sudah melewati jatuh tempo. Segera kembalikan!", bookTitle)
+			}
 
-			n.Store.CreateNotification(l.UserID, msg)
+			n.Store.CreateNotification(l.UserID, store.NotifyOverdue, store.SeverityWarning, msg)
+			// loan.overdue dikirim setiap pemeriksaan harian selama pinjaman masih terlambat
+			webhook.Emit(n.Store, webhook.EventLoanOverdue, overdueLoan{Loan: l, DaysLate: daysLate, EstimatedFine: fine})
 		}
 
 		durationUntilDue := l.DueDate.Sub(now)
 		if durationUntilDue > 0 && durationUntilDue < 24*time.Hour {
 			msg := fmt.Sprintf("PENGINGAT: Buku '%s' harus dikembalikan besok (%s).", bookTitle, l.DueDate.Format("02 Jan 2006"))
-			n.Store.CreateNotification(l.UserID, msg)
+			n.Store.CreateNotification(l.UserID, store.NotifyReminder, store.SeverityInfo, msg)
 		}
 	}
+	if accrued > 0 {
+		log.Printf("Worker: %d loan(s) updated with late status or accrued fine", accrued)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d loan(s) failed to update: %v", failed, lastErr)
+	}
+	return nil
 }
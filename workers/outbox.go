@@ -0,0 +1,110 @@
+package workers
+
+import (
+	"context"
+	"latihan_cloud8/health"
+	"latihan_cloud8/metrics"
+	"latihan_cloud8/notify"
+	"latihan_cloud8/store"
+	"log"
+	"time"
+)
+
+const (
+	// Interval pemeriksaan outbox
+	outboxInterval = 30 * time.Second
+	// Jumlah pesan maksimal yang dikirim per putaran
+	outboxBatchSize = 50
+)
+
+type OutboxWorker struct {
+	Store    store.Store
+	Channels map[string]notify.Channel
+}
+
+// NewOutboxWorker membuat worker pengirim pesan outbox untuk kanal-kanal yang aktif.
+func NewOutboxWorker(store store.Store, channels ...notify.Channel) *OutboxWorker {
+	w := &OutboxWorker{Store: store, Channels: make(map[string]notify.Channel)}
+	for _, ch := range channels {
+		w.Channels[ch.Name()] = ch
+	}
+	return w
+}
+
+// Run mengirim pesan outbox setiap 30 detik sampai ctx dibatalkan.
+// Putaran yang sedang berjalan diselesaikan dulu sebelum Run kembali.
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+	health.Expect("outbox", heartbeatFactor*outboxInterval)
+	defer health.Stop("outbox")
+
+	flush := func(beat func()) { w.Flush(ctx, beat) }
+	runRound("outbox", flush)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runRound("outbox", flush)
+		}
+	}
+}
+
+// Flush mengirim pesan outbox yang jadwalnya sudah tiba. Pesan yang gagal dijadwalkan
+// ulang dengan jeda bertambah (lihat notify.RetryDelay) sampai notify.MaxAttempts percobaan.
+// Pengiriman berhenti di antara pesan jika ctx dibatalkan. beat dipanggil sebelum setiap pesan
+// (lihat runRound).
+func (w *OutboxWorker) Flush(ctx context.Context, beat func()) {
+	msgs, err := w.Store.GetDueOutbox(time.Now(), outboxBatchSize)
+	if err != nil {
+		log.Println("Worker Error (outbox):", err)
+		return
+	}
+
+	sent := 0
+	for i := range msgs {
+		// Saat berhenti, pesan sisanya tetap pending dan dikirim setelah aplikasi hidup lagi
+		if ctx.Err() != nil {
+			break
+		}
+		beat()
+		msg := &msgs[i]
+		ch, ok := w.Channels[msg.Channel]
+		if !ok {
+			// Kanal dinonaktifkan (misalnya SMTP_HOST dihapus), pesan tetap menunggu
+			continue
+		}
+		// Pesan diklaim dulu agar tidak terkirim dua kali oleh instance lain
+		claimed, err := w.Store.ClaimOutbox(msg.ID, time.Now())
+		if err != nil {
+			log.Println("Worker Error (outbox):", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := ch.Send(msg); err != nil {
+			var retryAt *time.Time
+			if msg.Attempts+1 < notify.MaxAttempts {
+				t := time.Now().Add(notify.RetryDelay(msg.Attempts + 1))
+				retryAt = &t
+			}
+			log.Printf("Worker: outbox #%d via %s failed (attempt %d): %v", msg.ID, msg.Channel, msg.Attempts+1, err)
+			if err := w.Store.MarkOutboxFailed(msg.ID, err.Error(), retryAt); err != nil {
+				log.Println("Worker Error (outbox):", err)
+			}
+			continue
+		}
+		metrics.NotificationsSent.Inc(msg.Channel)
+		if err := w.Store.MarkOutboxSent(msg.ID, time.Now()); err != nil {
+			log.Println("Worker Error (outbox):", err)
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("Worker: %d outbox message(s) sent", sent)
+	}
+}
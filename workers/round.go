@@ -0,0 +1,21 @@
+package workers
+
+import (
+	"latihan_cloud8/health"
+	"latihan_cloud8/metrics"
+	"time"
+)
+
+// heartbeatFactor adalah kelipatan interval worker sebelum worker dianggap macet di /readyz.
+const heartbeatFactor = 3
+
+// runRound menjalankan satu putaran worker, mencatat durasinya ke metrik dan mengirim heartbeat.
+// fn menerima beat untuk dipanggil sebelum setiap item, sehingga putaran yang lama karena
+// penerima lambat tidak membuat worker dianggap macet; batas detak cukup menampung satu
+// pengiriman (timeout kanal), bukan satu putaran penuh.
+func runRound(name string, fn func(beat func())) {
+	start := time.Now()
+	fn(func() { health.Beat(name) })
+	metrics.WorkerDuration.Observe(time.Since(start).Seconds(), name)
+	health.Beat(name)
+}
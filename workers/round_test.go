@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"latihan_cloud8/health"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"testing"
+	"time"
+)
+
+// slowChannel meniru penerima lambat dan mencatat worker yang dianggap macet selama pengiriman.
+type slowChannel struct {
+	delay time.Duration
+	stale []string
+}
+
+func (c *slowChannel) Name() string { return "slow" }
+
+func (c *slowChannel) Prepare(*models.User, models.Notification) (*models.OutboxMessage, error) {
+	return nil, nil
+}
+
+func (c *slowChannel) Send(*models.OutboxMessage) error {
+	time.Sleep(c.delay)
+	c.stale = append(c.stale, health.Stale(time.Now())...)
+	return nil
+}
+
+func TestSlowRoundKeepsHeartbeat(t *testing.T) {
+	s := store.NewMemoryStore()
+	u, err := s.CreateUser("anggota1", "hash", "anggota", "Anggota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const messages = 5
+	for i := 0; i < messages; i++ {
+		if err := s.EnqueueOutbox(&models.OutboxMessage{UserID: u.ID, Channel: "slow", Recipient: "a@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Satu putaran (5 × 60ms) jauh melewati batas detak, tetapi setiap pengiriman masih di bawahnya
+	ch := &slowChannel{delay: 60 * time.Millisecond}
+	w := NewOutboxWorker(s, ch)
+	health.Expect("outbox", 100*time.Millisecond)
+	defer health.Stop("outbox")
+
+	start := time.Now()
+	runRound("outbox", func(beat func()) { w.Flush(context.Background(), beat) })
+	if elapsed := time.Since(start); elapsed < 3*100*time.Millisecond {
+		t.Fatalf("round took %s, too short to prove anything", elapsed)
+	}
+
+	if len(ch.stale) != 0 {
+		t.Errorf("worker marked stale during a slow round: %v", ch.stale)
+	}
+	if stale := health.Stale(time.Now()); len(stale) != 0 {
+		t.Errorf("stale after round: %v", stale)
+	}
+	sent, err := s.GetOutbox(store.OutboxSent, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != messages {
+		t.Errorf("sent %d messages, want %d", len(sent), messages)
+	}
+}
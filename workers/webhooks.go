@@ -0,0 +1,112 @@
+package workers
+
+import (
+	"context"
+	"latihan_cloud8/health"
+	"latihan_cloud8/models"
+	"latihan_cloud8/store"
+	"latihan_cloud8/webhook"
+	"log"
+	"time"
+)
+
+const (
+	// Interval pemeriksaan antrean webhook
+	webhookInterval = 10 * time.Second
+	// Jumlah pengiriman maksimal per putaran
+	webhookBatchSize = 20
+)
+
+type WebhookWorker struct {
+	Store store.Store
+}
+
+// NewWebhookWorker membuat worker pengirim webhook.
+func NewWebhookWorker(store store.Store) *WebhookWorker {
+	return &WebhookWorker{Store: store}
+}
+
+// Run mengirim webhook setiap 10 detik sampai ctx dibatalkan.
+// Putaran yang sedang berjalan diselesaikan dulu sebelum Run kembali.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+	health.Expect("webhooks", heartbeatFactor*webhookInterval)
+	defer health.Stop("webhooks")
+
+	flush := func(beat func()) { w.Flush(ctx, beat) }
+	runRound("webhooks", flush)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runRound("webhooks", flush)
+		}
+	}
+}
+
+// Flush mengirim pengiriman webhook yang jadwalnya sudah tiba. Pengiriman yang gagal
+// dijadwalkan ulang dengan jeda berlipat (lihat webhook.RetryDelay) sampai webhook.MaxAttempts
+// percobaan. Pengiriman untuk webhook yang dinonaktifkan langsung dinyatakan gagal.
+// Jika ctx dibatalkan, pengiriman berikutnya ditunda ke putaran setelah aplikasi hidup lagi.
+// beat dipanggil sebelum setiap pengiriman (lihat runRound).
+func (w *WebhookWorker) Flush(ctx context.Context, beat func()) {
+	deliveries, err := w.Store.GetDueWebhookDeliveries(time.Now(), webhookBatchSize)
+	if err != nil {
+		log.Println("Worker Error (webhooks):", err)
+		return
+	}
+
+	hooks := make(map[int]*models.Webhook)
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		beat()
+		d := &deliveries[i]
+		// Pengiriman diklaim dulu agar tidak dikirim dua kali oleh instance lain
+		claimed, err := w.Store.ClaimWebhookDelivery(d.ID, time.Now())
+		if err != nil {
+			log.Println("Worker Error (webhooks):", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		hook, ok := hooks[d.WebhookID]
+		if !ok {
+			if hook, err = w.Store.GetWebhook(d.WebhookID); err != nil && err != store.ErrWebhookNotFound {
+				log.Println("Worker Error (webhooks):", err)
+				continue
+			}
+			hooks[d.WebhookID] = hook
+		}
+		if hook == nil || !hook.Active {
+			w.markFailed(d, 0, "webhook inactive", nil)
+			continue
+		}
+
+		code, err := webhook.Deliver(hook, d)
+		if err != nil {
+			var retryAt *time.Time
+			if d.Attempts+1 < webhook.MaxAttempts {
+				t := time.Now().Add(webhook.RetryDelay(d.Attempts + 1))
+				retryAt = &t
+			}
+			log.Printf("Worker: webhook delivery #%d (%s) failed (attempt %d): %v", d.ID, d.Event, d.Attempts+1, err)
+			w.markFailed(d, code, err.Error(), retryAt)
+			continue
+		}
+		if err := w.Store.MarkWebhookDelivered(d.ID, code, time.Now()); err != nil {
+			log.Println("Worker Error (webhooks):", err)
+		}
+	}
+}
+
+// markFailed mencatat percobaan pengiriman yang gagal.
+func (w *WebhookWorker) markFailed(d *models.WebhookDelivery, code int, msg string, retryAt *time.Time) {
+	if err := w.Store.MarkWebhookFailed(d.ID, code, msg, retryAt); err != nil {
+		log.Println("Worker Error (webhooks):", err)
+	}
+}